@@ -5,41 +5,33 @@ import (
 	dotenv "github.com/joho/godotenv"
 	internalHttp "github.com/ntwarijoshua/siena/internal/http"
 	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/metrics"
 	"github.com/ntwarijoshua/siena/internal/models"
 	"github.com/ntwarijoshua/siena/internal/services"
 	"github.com/ntwarijoshua/siena/internal/storage"
+	"github.com/ntwarijoshua/siena/internal/tracing"
 	"github.com/sirupsen/logrus"
-	"io"
 	"os"
 	"path/filepath"
 	"time"
 )
 
 func setupLogger() *logrus.Logger {
-	logger := logrus.New()
 	cwd, err := os.Getwd()
 	if err != nil {
-		logger.Fatalf("Failed to determine working directory: %s", err)
+		logrus.Fatalf("Failed to determine working directory: %s", err)
 	}
-	runID := time.Now().Format("run-2006-01-02")
-	logDirPath := filepath.Join(cwd, "logs")
-	if _, err := os.Stat(logDirPath); os.IsNotExist(err) {
-		if err = os.Mkdir(logDirPath, 0777); err != nil {
-			logger.Fatalf("Failed to create logs directory %s", err)
-		}
-	}
-	logLocation := filepath.Join(logDirPath, runID+".log")
-	logFile, err := os.OpenFile(logLocation, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0777)
+	logger, logFile, err := logging.NewLogger(
+		logging.RotationConfigFromEnv(filepath.Join(cwd, "logs")),
+		os.Getenv("LOG_LEVEL"),
+	)
 	if err != nil {
-		logger.Fatalf("Failed to create log file: %s", err)
+		logrus.Fatalf("Failed to setup logger: %s", err)
 	}
-	logger.SetOutput(io.MultiWriter(os.Stderr, logFile))
 	logrus.RegisterExitHandler(func() {
-		if logFile == nil {
-			return
-		}
 		if err = logFile.Close(); err != nil {
-			logger.Fatalf("Failed to close logfile %s", err)
+			logrus.Errorf("Failed to close logfile %s", err)
 		}
 	})
 	return logger
@@ -72,16 +64,29 @@ func main() {
 	mainContext, cancelFunc := context.WithCancel(context.Background())
 	// clean up transactions
 	defer cancelFunc()
+	shutdownTracing, err := tracing.Init(mainContext)
+	if err != nil {
+		appLogger.Fatalf("Could not initialize tracing %s", err)
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
 	serviceContainer := &services.ServiceContainer{
 		Store:   initDB(),
 		Logger:  appLogger,
 		Context: mainContext,
 	}
 	serviceContainer.BuildServiceContainer()
+	metrics.RegisterDBStats(serviceContainer.Store.DB)
+	metrics.RegisterMailerLogStatus(mainContext, serviceContainer.Store.DB, appLogger)
 	app := Handlers.App{
 		Logger:           appLogger,
 		ServiceContainer: serviceContainer,
 	}
+	go services.StartAccountPurger(
+		mainContext,
+		appLogger,
+		serviceContainer.GetService("accountService").(*services.AccountService),
+		time.Hour,
+	)
 	go services.StartMailerConsumer(
 		appLogger,
 		serviceContainer.GetService("mailerService").(*services.MailerService),
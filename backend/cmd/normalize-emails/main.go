@@ -0,0 +1,65 @@
+// Command normalize-emails puts the stored email addresses of users in the form the api looks them up
+// in, and lists the active accounts that share an address once normalized. It only reports by
+// default, -apply saves the changes. Conflicting accounts are left alone, resolve them and run it
+// again before migrating to the case insensitive email index.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	dotenv "github.com/joho/godotenv"
+	"github.com/ntwarijoshua/siena/internal/emails"
+	"github.com/ntwarijoshua/siena/internal/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "save the normalized addresses instead of only reporting them")
+	flag.Parse()
+	if err := dotenv.Load(); err != nil {
+		logrus.Warnf("Could not load environment variables %s", err)
+	}
+
+	store, err := storage.NewDB(storage.DBCredentials{
+		Host:     os.Getenv("DB_HOST"),
+		Port:     os.Getenv("DB_PORT"),
+		Dbname:   os.Getenv("DB_NAME"),
+		Username: os.Getenv("DB_USER"),
+		Password: os.Getenv("DB_PASS"),
+	})
+	if err != nil {
+		logrus.Fatalf("Could not initialize connection to database %s", err)
+	}
+	ctx := context.Background()
+	var report *emails.Report
+	err = store.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		report, err = emails.Backfill(ctx, tx, emails.NormalizerFromEnv(), *apply)
+		return err
+	})
+	if err != nil {
+		logrus.Fatalf("Could not normalize emails %s", err)
+	}
+
+	verb := "would change"
+	if *apply {
+		verb = "changed"
+	}
+	for _, change := range report.Changes {
+		fmt.Printf("user %d: %s %q to %q\n", change.UserID, verb, change.From, change.To)
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("conflict: %d active accounts are %q:\n", len(conflict.Users), conflict.Email)
+		for _, user := range conflict.Users {
+			fmt.Printf("  user %d %q created %s confirmed %t\n",
+				user.ID, user.Email, user.CreatedAt.Format("2006-01-02"), user.Confirmed.Bool)
+		}
+	}
+	fmt.Printf("%d addresses %s, %d conflicts\n", len(report.Changes), verb, len(report.Conflicts))
+	if len(report.Conflicts) > 0 {
+		os.Exit(1)
+	}
+}
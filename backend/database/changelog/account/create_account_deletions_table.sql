@@ -0,0 +1,13 @@
+CREATE TABLE "public"."account_deletions"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL,
+    restore_token_hash VARCHAR(64) UNIQUE,
+    purge_after TIMESTAMPTZ NOT NULL,
+    restored_at TIMESTAMPTZ,
+    purged_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX account_deletions_pending_idx ON "public"."account_deletions" (purge_after)
+    WHERE restored_at IS NULL AND purged_at IS NULL;
@@ -0,0 +1,12 @@
+CREATE TABLE "public"."data_exports"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    file_path TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    downloaded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+CREATE TABLE "public"."audit_events"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    actor_id INT,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50),
+    target_id VARCHAR(100),
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    metadata TEXT NOT NULL DEFAULT '{}',
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX audit_events_actor_idx ON "public"."audit_events" (actor_id, id);
+CREATE INDEX audit_events_target_idx ON "public"."audit_events" (target_type, target_id, id);
+CREATE INDEX audit_events_action_idx ON "public"."audit_events" (action, id);
+CREATE INDEX audit_events_created_at_idx ON "public"."audit_events" (created_at);
@@ -0,0 +1,5 @@
+-- keys an admin issued outlive the admin, only the record of who issued them is lost
+ALTER TABLE "public"."api_keys" ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE "public"."api_keys" DROP CONSTRAINT api_keys_created_by_fkey;
+ALTER TABLE "public"."api_keys" ADD CONSTRAINT api_keys_created_by_fkey
+    FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE SET NULL;
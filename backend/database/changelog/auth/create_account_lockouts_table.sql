@@ -0,0 +1,12 @@
+CREATE TABLE "public"."account_lockouts"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    scope VARCHAR(20) NOT NULL,
+    subject VARCHAR(100) NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    unlock_token VARCHAR(255),
+    released_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX account_lockouts_scope_subject_idx ON "public"."account_lockouts" (scope, subject, locked_until);
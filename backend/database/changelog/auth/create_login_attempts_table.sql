@@ -0,0 +1,11 @@
+CREATE TABLE "public"."login_attempts"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    email VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(64) NOT NULL,
+    successful BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX login_attempts_email_created_at_idx ON "public"."login_attempts" (email, created_at);
+CREATE INDEX login_attempts_ip_address_created_at_idx ON "public"."login_attempts" (ip_address, created_at);
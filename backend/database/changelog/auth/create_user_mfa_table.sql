@@ -0,0 +1,11 @@
+CREATE TABLE "public"."user_mfa"
+(
+    id SERIAL NOT NULL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE,
+    secret VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+CREATE TABLE "public"."cache_entries"
+(
+    key VARCHAR(255) NOT NULL PRIMARY KEY,
+    value BYTEA NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX cache_entries_expires_at_idx ON "public"."cache_entries" (expires_at);
@@ -0,0 +1,8 @@
+CREATE TABLE "public"."rate_limits"
+(
+    key VARCHAR(255) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    hits INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (key, window_start)
+);
+CREATE INDEX rate_limits_window_start_idx ON "public"."rate_limits" (window_start);
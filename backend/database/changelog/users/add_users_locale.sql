@@ -0,0 +1,2 @@
+-- the language the user asked the api to answer in, requests negotiate one from Accept-Language without it
+ALTER TABLE "public"."users" ADD COLUMN locale VARCHAR(35);
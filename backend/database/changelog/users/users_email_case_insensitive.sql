@@ -0,0 +1,7 @@
+-- emails are compared regardless of case, Jane@x.com and jane@x.com are the same account
+DROP INDEX IF EXISTS "public"."users_email_active_key";
+
+CREATE UNIQUE INDEX users_email_active_key ON "public"."users" (lower(email)) WHERE deleted = FALSE;
+
+-- lookups that include deleted accounts
+CREATE INDEX users_email_lower_idx ON "public"."users" (lower(email));
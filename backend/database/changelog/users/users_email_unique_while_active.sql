@@ -0,0 +1,4 @@
+-- a deleted account gives up its email, someone else may sign up with it during the grace period
+ALTER TABLE "public"."users" DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX users_email_active_key ON "public"."users" (email) WHERE deleted = FALSE;
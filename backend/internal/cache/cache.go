@@ -0,0 +1,41 @@
+// Package cache keeps the results of expensive lookups for a while. Services read through it with
+// Aside and drop entries as the rows behind them are written, stale entries left behind by writes
+// the api doesn't see, e.g. manual fixes in the database, last until their ttl runs out.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Cache stores values by key for up to a ttl, a ttl of 0 keeps them until they are evicted.
+//
+// LRU keeps entries in process, each api instance has its own and only sees its own invalidations.
+// SQLCache shares them between instances through postgres. Anything with get, set with an expiry and
+// delete, e.g. redis or memcached, can implement the same contract.
+type Cache interface {
+	// Get reports whether key is cached, and its value when it is.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Aside reads key into dest, a pointer, from cache. On a miss it calls load to fill dest and caches
+// the result for ttl. A cache that fails is treated as one that misses, only the errors of load are
+// returned, and nothing is cached when load fails. A nil cache always loads.
+func Aside(ctx context.Context, cache Cache, key string, ttl time.Duration, dest interface{}, load func() error) error {
+	if cache == nil {
+		return load()
+	}
+	if raw, ok, err := cache.Get(ctx, key); err == nil && ok && json.Unmarshal(raw, dest) == nil {
+		return nil
+	}
+	if err := load(); err != nil {
+		return err
+	}
+	if raw, err := json.Marshal(dest); err == nil {
+		_ = cache.Set(ctx, key, raw, ttl)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+// forever stands in for the expiry of entries set without a ttl, the column can't be null.
+var forever = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SQLCache shares entries between api instances through the cache_entries table.
+type SQLCache struct {
+	Executor boil.ContextExecutor
+
+	mutex     sync.Mutex
+	lastPurge time.Time
+}
+
+func NewSQLCache(executor boil.ContextExecutor) *SQLCache {
+	return &SQLCache{Executor: executor}
+}
+
+func (c *SQLCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := c.Executor.QueryRowContext(ctx,
+		`SELECT value FROM cache_entries WHERE key = $1 AND expires_at > now()`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *SQLCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	expiresAt := forever
+	if ttl > 0 {
+		expiresAt = now.Add(ttl)
+	}
+	_, err := c.Executor.ExecContext(ctx, `
+		INSERT INTO cache_entries (key, value, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at`,
+		key, value, expiresAt)
+	c.purge(ctx, now)
+	return err
+}
+
+func (c *SQLCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := c.Executor.ExecContext(ctx, `DELETE FROM cache_entries WHERE key = ANY($1)`, pq.Array(keys))
+	return err
+}
+
+// purge deletes expired entries at most once a minute per instance.
+func (c *SQLCache) purge(ctx context.Context, now time.Time) {
+	c.mutex.Lock()
+	if now.Sub(c.lastPurge) < time.Minute {
+		c.mutex.Unlock()
+		return
+	}
+	c.lastPurge = now
+	c.mutex.Unlock()
+	_, _ = c.Executor.ExecContext(ctx, `DELETE FROM cache_entries WHERE expires_at <= $1`, now)
+}
@@ -0,0 +1,85 @@
+package emails
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Change is a stored address that isn't in its normal form yet.
+type Change struct {
+	UserID int
+	From   string
+	To     string
+}
+
+// Conflict is a normal form shared by several active accounts. They can't all keep it, someone has to
+// decide which account stays, so their addresses are left alone.
+type Conflict struct {
+	Email string
+	Users models.UserSlice
+}
+
+// Report is what a backfill changes, or would change, and what it can't.
+type Report struct {
+	Changes   []Change
+	Conflicts []Conflict
+}
+
+// Plan works out the changes that put the addresses of users in their normal form. Deleted accounts
+// are normalized too, they don't take part in conflicts since only active addresses are unique.
+func Plan(users models.UserSlice, normalizer Normalizer) *Report {
+	active := map[string]models.UserSlice{}
+	for _, user := range users {
+		if !user.Deleted {
+			normal := normalizer.Normalize(user.Email)
+			active[normal] = append(active[normal], user)
+		}
+	}
+
+	report := &Report{}
+	for _, user := range users {
+		normal := normalizer.Normalize(user.Email)
+		if normal == user.Email || len(active[normal]) > 1 && !user.Deleted {
+			continue
+		}
+		report.Changes = append(report.Changes, Change{UserID: user.ID, From: user.Email, To: normal})
+	}
+	for email, owners := range active {
+		if len(owners) > 1 {
+			report.Conflicts = append(report.Conflicts, Conflict{Email: email, Users: owners})
+		}
+	}
+	sort.Slice(report.Changes, func(i, j int) bool { return report.Changes[i].UserID < report.Changes[j].UserID })
+	sort.Slice(report.Conflicts, func(i, j int) bool { return report.Conflicts[i].Email < report.Conflicts[j].Email })
+	return report
+}
+
+// Backfill plans the normalization of every stored address and, when apply is set, saves the changes
+// through exec. Conflicts are reported and left for someone to resolve, by deleting or renaming all
+// but one of the accounts, before running it again.
+func Backfill(ctx context.Context, exec boil.ContextExecutor, normalizer Normalizer, apply bool) (*Report, error) {
+	users, err := models.ScopedUsers(models.WithDeleted, qm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	report := Plan(users, normalizer)
+	if !apply {
+		return report, nil
+	}
+	byID := make(map[int]*models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	for _, change := range report.Changes {
+		user := byID[change.UserID]
+		user.Email = change.To
+		if _, err = user.Update(ctx, exec, boil.Whitelist(models.UserColumns.Email, models.UserColumns.UpdatedAt)); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
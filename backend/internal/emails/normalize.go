@@ -0,0 +1,77 @@
+// Package emails turns the addresses users type into the form accounts are keyed by, so Jane@x.com
+// and jane@x.com are one account.
+package emails
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rule describes how a mail provider reads the local part of its addresses.
+type Rule struct {
+	// Domain is the canonical domain of an alias, gmail.com for googlemail.com. Empty keeps the domain.
+	Domain string
+	// IgnoreDots drops the dots of the local part, the provider delivers j.doe and jdoe alike.
+	IgnoreDots bool
+	// IgnoreSubaddress drops everything from the first + of the local part.
+	IgnoreSubaddress bool
+}
+
+// ProviderRules are the rules of the big providers, by lowercased domain.
+var ProviderRules = map[string]Rule{
+	"gmail.com":      {IgnoreDots: true, IgnoreSubaddress: true},
+	"googlemail.com": {Domain: "gmail.com", IgnoreDots: true, IgnoreSubaddress: true},
+	"outlook.com":    {IgnoreSubaddress: true},
+	"hotmail.com":    {IgnoreSubaddress: true},
+	"live.com":       {IgnoreSubaddress: true},
+	"icloud.com":     {IgnoreSubaddress: true},
+	"fastmail.com":   {IgnoreSubaddress: true},
+	"proton.me":      {IgnoreSubaddress: true},
+	"protonmail.com": {IgnoreSubaddress: true},
+}
+
+// Normalizer trims and lowercases addresses, then applies the rule of their domain if there is one.
+// The zero value applies no provider rules.
+type Normalizer struct {
+	Rules map[string]Rule
+}
+
+// NormalizerFromEnv applies ProviderRules when EMAIL_PROVIDER_RULES is true. Turning them on or off
+// changes the normal form of stored addresses, the backfill has to be run again afterwards.
+func NormalizerFromEnv() Normalizer {
+	var normalizer Normalizer
+	if providerRules, _ := strconv.ParseBool(os.Getenv("EMAIL_PROVIDER_RULES")); providerRules {
+		normalizer.Rules = ProviderRules
+	}
+	return normalizer
+}
+
+// Normalize returns the normal form of address. Anything that doesn't look like an address is only
+// trimmed and lowercased, validating it is up to the caller.
+func (n Normalizer) Normalize(address string) string {
+	address = strings.ToLower(strings.TrimSpace(address))
+	at := strings.LastIndex(address, "@")
+	if at <= 0 || at == len(address)-1 {
+		return address
+	}
+	local, domain := address[:at], address[at+1:]
+	rule, ok := n.Rules[domain]
+	if !ok {
+		return address
+	}
+	if rule.IgnoreSubaddress {
+		if plus := strings.Index(local, "+"); plus > 0 {
+			local = local[:plus]
+		}
+	}
+	if rule.IgnoreDots {
+		if dotless := strings.Replace(local, ".", "", -1); dotless != "" {
+			local = dotless
+		}
+	}
+	if rule.Domain != "" {
+		domain = rule.Domain
+	}
+	return local + "@" + domain
+}
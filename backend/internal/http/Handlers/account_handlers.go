@@ -0,0 +1,153 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+	"time"
+)
+
+const exportFileName = "siena-export.zip"
+
+type RestoreAccountRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ProfileResponse is the public part of an account.
+type ProfileResponse struct {
+	ID           int    `json:"id"`
+	Names        string `json:"names,omitempty"`
+	TagLine      string `json:"tag_line,omitempty"`
+	DateOfBirth  string `json:"date_of_birth,omitempty"`
+	ProfilePhoto string `json:"profile_photo,omitempty"`
+}
+
+type AccountResponse struct {
+	User    UserResponse    `json:"user"`
+	Profile ProfileResponse `json:"profile"`
+}
+
+type GetAccountResponse struct {
+	Data AccountResponse `json:"data"`
+}
+
+// AccountDeletion tells until when a deleted account can be restored.
+type AccountDeletion struct {
+	PurgeAfter time.Time `json:"purge_after"`
+}
+
+type DeleteAccountResponse struct {
+	Message string          `json:"message"`
+	Data    AccountDeletion `json:"data"`
+}
+
+// AccountExport tells until when the mailed link to an export works.
+type AccountExport struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ExportAccountResponse struct {
+	Message string        `json:"message"`
+	Data    AccountExport `json:"data"`
+}
+
+func newProfileResponse(profile *models.Profile) ProfileResponse {
+	response := ProfileResponse{
+		ID:           profile.ID,
+		Names:        profile.Names.String,
+		TagLine:      profile.TagLine.String,
+		ProfilePhoto: profile.ProfilePhoto.String,
+	}
+	if profile.DateOfBirth.Valid {
+		response.DateOfBirth = profile.DateOfBirth.Time.Format("2006-01-02")
+	}
+	return response
+}
+
+// GetAccount returns the signed in user and their profile.
+func (app *App) GetAccount(c *gin.Context) {
+	profileService := app.ServiceContainer.GetService("profileService").(*services.ProfileService)
+	user := c.MustGet("user").(*models.User)
+
+	profile, err := profileService.Get(c.Request.Context(), user.ProfileID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, GetAccountResponse{
+		Data: AccountResponse{User: newUserResponse(user), Profile: newProfileResponse(profile)},
+	})
+}
+
+// DeleteAccount deletes the signed in user's account. It is purged once the grace period is over,
+// until then the link mailed to the user restores it.
+func (app *App) DeleteAccount(c *gin.Context) {
+	accountService := app.ServiceContainer.GetService("accountService").(*services.AccountService)
+	user := c.MustGet("user").(*models.User)
+
+	deletion, err := accountService.RequestDeletion(c.Request.Context(), user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	clearAuthCookie(c)
+	c.JSON(http.StatusAccepted, DeleteAccountResponse{
+		Message: app.translate(c, "your account has been deleted, you can restore it from the link we mailed you"),
+		Data:    AccountDeletion{PurgeAfter: deletion.PurgeAfter},
+	})
+}
+
+func (app *App) RestoreAccount(c *gin.Context) {
+	var (
+		payload           RestoreAccountRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		accountService    = app.ServiceContainer.GetService("accountService").(*services.AccountService)
+	)
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	if _, err := accountService.Restore(c.Request.Context(), payload.Token); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "account restored successfully")})
+}
+
+// ExportAccountData mails the signed in user a link to an archive of their data.
+func (app *App) ExportAccountData(c *gin.Context) {
+	accountService := app.ServiceContainer.GetService("accountService").(*services.AccountService)
+	user := c.MustGet("user").(*models.User)
+
+	export, err := accountService.Export(c.Request.Context(), user)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, ExportAccountResponse{
+		Message: app.translate(c, "your data export is ready, we mailed you a link to download it"),
+		Data:    AccountExport{ExpiresAt: export.ExpiresAt},
+	})
+}
+
+// DownloadAccountData serves an export given the token from the mailed link. The link only works
+// in a session of the user it was made for, a forwarded mail is not enough.
+func (app *App) DownloadAccountData(c *gin.Context) {
+	accountService := app.ServiceContainer.GetService("accountService").(*services.AccountService)
+	user := c.MustGet("user").(*models.User)
+
+	export, err := accountService.OpenExport(c.Request.Context(), user.ID, c.Query("token"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.FileAttachment(export.FilePath, exportFileName)
+}
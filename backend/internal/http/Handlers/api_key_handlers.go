@@ -0,0 +1,157 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/volatiletech/null"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	// ExpiresInDays is optional, keys without it don't expire.
+	ExpiresInDays int `json:"expires_in_days" validate:"min=0,max=3650"`
+}
+
+// APIKeyResponse is what clients get to see of a key, never its hash.
+type APIKeyResponse struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	Scopes     []string  `json:"scopes"`
+	Key        string    `json:"key,omitempty"`
+	LastUsedAt null.Time `json:"last_used_at"`
+	ExpiresAt  null.Time `json:"expires_at"`
+	RevokedAt  null.Time `json:"revoked_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type APIKeysResponse struct {
+	Data []APIKeyResponse `json:"data"`
+}
+
+type CreateAPIKeyResponse struct {
+	Message string         `json:"message"`
+	Data    APIKeyResponse `json:"data"`
+}
+
+func newAPIKeyResponse(key *models.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     services.APIKeyPrefix + key.Prefix,
+		Scopes:     strings.Fields(key.Scopes),
+		LastUsedAt: key.LastUsedAt,
+		ExpiresAt:  key.ExpiresAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (app *App) ListAPIKeys(c *gin.Context) {
+	app.listAPIKeys(c, c.MustGet("user").(*models.User).ID)
+}
+
+func (app *App) CreateAPIKey(c *gin.Context) {
+	user := c.MustGet("user").(*models.User)
+	app.createAPIKey(c, user, user)
+}
+
+func (app *App) RevokeAPIKey(c *gin.Context) {
+	app.revokeAPIKey(c, c.MustGet("user").(*models.User).ID)
+}
+
+func (app *App) AdminListAPIKeys(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, services.NotFoundError("User not found", nil))
+		return
+	}
+	app.listAPIKeys(c, userID)
+}
+
+// AdminCreateAPIKey issues a key for another user, e.g. for a partner integration.
+func (app *App) AdminCreateAPIKey(c *gin.Context) {
+	userService := app.ServiceContainer.GetService("userService").(*services.UserService)
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, services.NotFoundError("User not found", nil))
+		return
+	}
+	owner, err := userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	app.createAPIKey(c, owner, c.MustGet("user").(*models.User))
+}
+
+func (app *App) AdminRevokeAPIKey(c *gin.Context) {
+	app.revokeAPIKey(c, 0)
+}
+
+func (app *App) listAPIKeys(c *gin.Context, userID int) {
+	apiKeyService := app.ServiceContainer.GetService("apiKeyService").(*services.APIKeyService)
+	keys, err := apiKeyService.List(c.Request.Context(), userID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	response := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = newAPIKeyResponse(key)
+	}
+	c.JSON(http.StatusOK, APIKeysResponse{Data: response})
+}
+
+func (app *App) createAPIKey(c *gin.Context, owner, issuer *models.User) {
+	var (
+		payload           CreateAPIKeyRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		apiKeyService     = app.ServiceContainer.GetService("apiKeyService").(*services.APIKeyService)
+	)
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	var expiresAt null.Time
+	if payload.ExpiresInDays > 0 {
+		expiresAt = null.TimeFrom(time.Now().AddDate(0, 0, payload.ExpiresInDays))
+	}
+	issued, err := apiKeyService.Create(c.Request.Context(), owner, issuer, payload.Name, payload.Scopes, expiresAt)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	response := newAPIKeyResponse(issued.Key)
+	response.Key = issued.Secret
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{
+		Message: app.translate(c, "api key created, copy it now as it won't be shown again"),
+		Data:    response,
+	})
+}
+
+func (app *App) revokeAPIKey(c *gin.Context, ownerID int) {
+	apiKeyService := app.ServiceContainer.GetService("apiKeyService").(*services.APIKeyService)
+	keyID, err := strconv.Atoi(c.Param("key"))
+	if err != nil {
+		abortWithError(c, services.NotFoundError("Api key not found", nil))
+		return
+	}
+	actor := c.MustGet("user").(*models.User)
+	if err = apiKeyService.Revoke(c.Request.Context(), keyID, ownerID, actor.ID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "api key revoked")})
+}
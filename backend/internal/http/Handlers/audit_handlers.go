@@ -0,0 +1,229 @@
+package Handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/audit"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/volatiletech/null"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const auditExportFileName = "audit-events.csv"
+
+// auditCSVHeader is the first line of the csv export, in column order.
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_id", "action", "target_type", "target_id",
+	"ip_address", "user_agent", "metadata", "prev_hash", "hash",
+}
+
+// AuditEventResponse is an audit event as admins get to see it.
+type AuditEventResponse struct {
+	ID         int             `json:"id"`
+	ActorID    null.Int        `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType null.String     `json:"target_type"`
+	TargetID   null.String     `json:"target_id"`
+	IPAddress  null.String     `json:"ip_address"`
+	UserAgent  null.String     `json:"user_agent"`
+	Metadata   json.RawMessage `json:"metadata"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditEventsResponse struct {
+	Data []AuditEventResponse `json:"data"`
+}
+
+// AuditTrailStatus is the outcome of checking the hash chain of the audit trail.
+type AuditTrailStatus struct {
+	Intact  bool `json:"intact"`
+	Checked int  `json:"checked"`
+	// BrokenAt is the id of the first event that doesn't match, left out while the chain is intact.
+	BrokenAt int `json:"broken_at,omitempty"`
+}
+
+type AuditTrailResponse struct {
+	Data AuditTrailStatus `json:"data"`
+}
+
+func newAuditEventResponse(event *models.AuditEvent) AuditEventResponse {
+	return AuditEventResponse{
+		ID:         event.ID,
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IPAddress:  event.IPAddress,
+		UserAgent:  event.UserAgent,
+		Metadata:   json.RawMessage(event.Metadata),
+		PrevHash:   event.PrevHash,
+		Hash:       event.Hash,
+		CreatedAt:  event.CreatedAt,
+	}
+}
+
+// AuditContext attributes the audit events recorded while handling a request to its client.
+// The actor is added once the request is authenticated.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := audit.WithRequest(c.Request.Context(), audit.Request{
+			IPAddress: RequestIP(c),
+			UserAgent: c.Request.UserAgent(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// recordAudit records an audit event for an action taken by a handler.
+func (app *App) recordAudit(c *gin.Context, event services.AuditEvent) {
+	auditService := app.ServiceContainer.GetService("auditService").(*services.AuditService)
+	auditService.Record(c.Request.Context(), event)
+}
+
+// ListAuditEvents returns a page of audit events, newest first. The next page is requested with
+// before_id set to the id of the last event.
+func (app *App) ListAuditEvents(c *gin.Context) {
+	auditService := app.ServiceContainer.GetService("auditService").(*services.AuditService)
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	events, err := auditService.Query(c.Request.Context(), filter)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	response := make([]AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		response = append(response, newAuditEventResponse(event))
+	}
+	c.JSON(http.StatusOK, AuditEventsResponse{Data: response})
+}
+
+// ExportAuditEvents streams every audit event matching the filters as csv, oldest first.
+func (app *App) ExportAuditEvents(c *gin.Context) {
+	auditService := app.ServiceContainer.GetService("auditService").(*services.AuditService)
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+auditExportFileName+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write(auditCSVHeader)
+	err = auditService.Each(c.Request.Context(), filter, func(event *models.AuditEvent) error {
+		return writer.Write([]string{
+			strconv.Itoa(event.ID),
+			event.CreatedAt.UTC().Format(time.RFC3339Nano),
+			nullIntCell(event.ActorID),
+			csvCell(event.Action),
+			csvCell(event.TargetType.String),
+			csvCell(event.TargetID.String),
+			csvCell(event.IPAddress.String),
+			csvCell(event.UserAgent.String),
+			csvCell(event.Metadata),
+			event.PrevHash,
+			event.Hash,
+		})
+	})
+	writer.Flush()
+	if err != nil {
+		// the status is already out, all we can do is cut the file short and log why
+		logging.FromContext(c.Request.Context(), app.Logger).Errorf("Audit event export failed %s", err)
+	}
+}
+
+// VerifyAuditTrail recomputes the hash chain of the audit trail to detect tampering.
+func (app *App) VerifyAuditTrail(c *gin.Context) {
+	auditService := app.ServiceContainer.GetService("auditService").(*services.AuditService)
+	verifier, err := auditService.Verify(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, AuditTrailResponse{Data: AuditTrailStatus{
+		Intact:   verifier.BrokenAt == 0,
+		Checked:  verifier.Checked,
+		BrokenAt: verifier.BrokenAt,
+	}})
+}
+
+func auditFilterFromQuery(c *gin.Context) (services.AuditFilter, error) {
+	filter := services.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		IPAddress:  c.Query("ip_address"),
+	}
+	var fields []services.FieldError
+	for _, param := range []struct {
+		name   string
+		target *int
+	}{
+		{"actor_id", &filter.ActorID},
+		{"before_id", &filter.BeforeID},
+		{"limit", &filter.Limit},
+	} {
+		name, raw := param.name, c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			fields = append(fields, services.FieldError{Field: name, Message: "{0} should be a positive number"}.Naming(name))
+			continue
+		}
+		*param.target = value
+	}
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	} {
+		name, raw := param.name, c.Query(param.name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			fields = append(fields, services.FieldError{Field: name, Message: "{0} should be an RFC 3339 timestamp"}.Naming(name))
+			continue
+		}
+		*param.target = value
+	}
+	if len(fields) > 0 {
+		return filter, services.ValidationError("The submitted filters are invalid", fields...)
+	}
+	return filter, nil
+}
+
+func nullIntCell(value null.Int) string {
+	if !value.Valid {
+		return ""
+	}
+	return strconv.Itoa(value.Int)
+}
+
+// csvCell keeps spreadsheet applications from evaluating values, user agents come from clients.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
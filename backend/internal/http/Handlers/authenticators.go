@@ -0,0 +1,136 @@
+package Handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/audit"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"strings"
+)
+
+const (
+	AuthCookieName   = "siena_token"
+	AuthMethodCookie = "cookie"
+	AuthMethodAPIKey = "api_key"
+	APIKeyHeader     = "X-API-Key"
+	apiKeyScopesKey  = "api_key_scopes"
+	apiKeyIDKey      = "api_key_id"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request doesn't carry the kind of
+// credentials it handles, which lets the next authenticator in the chain have a go.
+var ErrNoCredentials = errors.New("no credentials supplied")
+
+// Authenticator resolves the user a request is made on behalf of.
+type Authenticator interface {
+	Authenticate(c *gin.Context) (*models.User, error)
+}
+
+// TokenVerifier turns an access token into the user it was issued to.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (*models.User, error)
+}
+
+// BearerAuthenticator reads a token from the Authorization header.
+type BearerAuthenticator struct {
+	Verifier TokenVerifier
+}
+
+func (a BearerAuthenticator) Authenticate(c *gin.Context) (*models.User, error) {
+	header := c.GetHeader("Authorization")
+	if header == "" {
+		return nil, ErrNoCredentials
+	}
+	token, ok := ParseBearerToken(header)
+	if !ok {
+		return nil, services.UnauthorizedError("Malformed authorization header", nil)
+	}
+	return a.Verifier.VerifyToken(c.Request.Context(), token)
+}
+
+// CookieAuthenticator reads a token from the cookie set on login, which is how the web frontend
+// authenticates.
+type CookieAuthenticator struct {
+	Verifier   TokenVerifier
+	CookieName string
+}
+
+func (a CookieAuthenticator) Authenticate(c *gin.Context) (*models.User, error) {
+	token, err := c.Cookie(a.CookieName)
+	if err != nil || token == "" {
+		return nil, ErrNoCredentials
+	}
+	user, err := a.Verifier.VerifyToken(c.Request.Context(), token)
+	if err == nil {
+		// cookies are sent by the browser on its own, CSRFProtection needs to know
+		c.Set(authMethodKey, AuthMethodCookie)
+	}
+	return user, err
+}
+
+// APIKeyVerifier turns an api key into its owner and the stored key.
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key, ip string) (*models.User, *models.APIKey, error)
+}
+
+// APIKeyAuthenticator reads an api key from the X-API-Key header. Requests made with a key are
+// limited to its scopes, see RequireScope.
+type APIKeyAuthenticator struct {
+	Verifier APIKeyVerifier
+}
+
+func (a APIKeyAuthenticator) Authenticate(c *gin.Context) (*models.User, error) {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	user, apiKey, err := a.Verifier.VerifyAPIKey(c.Request.Context(), key, RequestIP(c))
+	if err == nil {
+		c.Set(authMethodKey, AuthMethodAPIKey)
+		c.Set(apiKeyIDKey, apiKey.ID)
+		c.Set(apiKeyScopesKey, strings.Fields(apiKey.Scopes))
+	}
+	return user, err
+}
+
+// ParseBearerToken extracts the token of an "Authorization: Bearer <token>" header value.
+// The scheme is matched case insensitively as required by RFC 7235.
+func ParseBearerToken(header string) (string, bool) {
+	parts := strings.Fields(header)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// Authenticate runs the authenticators in order and stores the first resolved user under "user".
+// The request is rejected when one of them fails or none of them finds credentials.
+func Authenticate(authenticators ...Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, authenticator := range authenticators {
+			user, err := authenticator.Authenticate(c)
+			if err == ErrNoCredentials {
+				continue
+			}
+			if err != nil {
+				// unexpected failures surface as internal errors, any other refusal is a 401
+				var serviceErr *services.ServiceError
+				if errors.As(err, &serviceErr) && serviceErr.Kind != services.KindUnauthorized {
+					err = services.UnauthorizedError("Unauthorized", err)
+				}
+				abortWithError(c, err)
+				return
+			}
+			if user == nil {
+				break
+			}
+			c.Set("user", user)
+			c.Request = c.Request.WithContext(audit.WithActor(c.Request.Context(), user.ID))
+			c.Next()
+			return
+		}
+		abortWithError(c, services.UnauthorizedError("Authentication required", nil))
+	}
+}
@@ -0,0 +1,113 @@
+package Handlers
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const clientIPKey = "client_ip"
+
+// TrustedProxies are the load balancers in front of the api. X-Forwarded-For is written by the
+// client as much as by them, it is only believed for the hops they added.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies reads a comma separated list of addresses and CIDR ranges.
+func ParseTrustedProxies(raw string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an address or a CIDR range", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an address or a CIDR range", entry)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// TrustedProxiesFromEnv reads TRUSTED_PROXIES, which is empty unless the api runs behind a proxy.
+// Entries that don't parse are a configuration mistake the api refuses to start with.
+func TrustedProxiesFromEnv() TrustedProxies {
+	proxies, err := ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		panic(fmt.Sprintf("TRUSTED_PROXIES is invalid: %s", err))
+	}
+	return proxies
+}
+
+func (proxies TrustedProxies) trust(ip net.IP) bool {
+	for _, network := range proxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve walks X-Forwarded-For back from the peer, as long as the hop that wrote an entry is a
+// trusted proxy. The first address written by anyone else is the client.
+func (proxies TrustedProxies) resolve(remoteAddr string, forwardedFor []string) net.IP {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(remoteAddr))
+	if err != nil {
+		host = strings.TrimSpace(remoteAddr)
+	}
+	client := net.ParseIP(host)
+	if client == nil {
+		return nil
+	}
+	var hops []string
+	for _, header := range forwardedFor {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0 && proxies.trust(client); i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			// whatever the proxy was handed is garbage, the proxy is the last address we know
+			break
+		}
+		client = hop
+	}
+	return client
+}
+
+// ClientIP resolves the address requests come from, trusting X-Forwarded-For only as far as
+// proxies wrote it. Handlers read it with RequestIP, it is always a valid address in canonical form
+// or empty when the peer address can't be parsed.
+func ClientIP(proxies TrustedProxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ip := proxies.resolve(c.Request.RemoteAddr, c.Request.Header["X-Forwarded-For"]); ip != nil {
+			c.Set(clientIPKey, ip.String())
+		}
+		c.Next()
+	}
+}
+
+// RequestIP returns the address ClientIP resolved, or the peer address when the middleware didn't run.
+func RequestIP(c *gin.Context) string {
+	if ip := c.GetString(clientIPKey); ip != "" {
+		return ip
+	}
+	if ip := TrustedProxies(nil).resolve(c.Request.RemoteAddr, nil); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+)
+
+// ErrorBody is the single shape every failed request is reported with.
+type ErrorBody struct {
+	Code      services.ErrorKind    `json:"code"`
+	Message   string                `json:"message"`
+	Details   []services.FieldError `json:"details,omitempty"`
+	RequestID string                `json:"request_id,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// MessageResponse is the body of requests that only report how they went.
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+var errorStatuses = map[services.ErrorKind]int{
+	services.KindNotFound:     http.StatusNotFound,
+	services.KindConflict:     http.StatusConflict,
+	services.KindValidation:   http.StatusBadRequest,
+	services.KindUnauthorized: http.StatusUnauthorized,
+	services.KindForbidden:    http.StatusForbidden,
+	services.KindTooMany:      http.StatusTooManyRequests,
+	services.KindInternal:     http.StatusInternalServerError,
+}
+
+// ErrorHandler renders the last error attached to the context with c.Error, in the language of the
+// request. Internal errors are logged with their cause and replaced with a generic message.
+func (app *App) ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+		serviceErr := app.localize(c, services.AsServiceError(last.Err))
+		status, known := errorStatuses[serviceErr.Kind]
+		if !known {
+			status = http.StatusInternalServerError
+		}
+		if status >= http.StatusInternalServerError {
+			logging.FromContext(c.Request.Context(), app.Logger).Errorf("Request failed: %s", last.Err)
+		}
+		c.JSON(status, ErrorResponse{Error: ErrorBody{
+			Code:      serviceErr.Kind,
+			Message:   serviceErr.Message,
+			Details:   serviceErr.Fields,
+			RequestID: c.Writer.Header().Get(RequestIDHeader),
+		}})
+	}
+}
+
+// abortWithError stops the handler chain and leaves err for ErrorHandler to render.
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+var errMalformedPayload = services.ValidationError("Failed parsing payload")
+
+// errAccountDeleted is returned to logins of an account waiting to be purged, the deletion mail
+// has the link to restore it.
+var errAccountDeleted = services.ForbiddenError("This account has been deleted, use the link we mailed you to restore it")
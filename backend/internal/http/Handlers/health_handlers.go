@@ -0,0 +1,27 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+)
+
+type LivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// Liveness only tells the orchestrator that the process is able to serve requests.
+func (app *App) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, LivenessResponse{Status: services.HealthStatusUp})
+}
+
+// Readiness reports the state of every dependency the api needs to serve traffic.
+func (app *App) Readiness(c *gin.Context) {
+	healthService := app.ServiceContainer.GetService("healthService").(*services.HealthService)
+	report := healthService.Readiness(c.Request.Context())
+	if !report.Healthy() {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,21 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/listing"
+	"github.com/ntwarijoshua/siena/internal/services"
+)
+
+// listQuery parses the limit, sort, filter and cursor parameters of a list request against
+// resource. Refused parameters are reported like other invalid filters.
+func listQuery(c *gin.Context, resource *listing.Resource) (*listing.Query, error) {
+	query, err := listing.Parse(resource, c.Request.URL.Query())
+	if paramErrors, ok := err.(listing.ParamErrors); ok {
+		fields := make([]services.FieldError, len(paramErrors))
+		for i, paramError := range paramErrors {
+			fields[i] = services.FieldError{Field: paramError.Param, Message: paramError.Template}.Naming(paramError.Params...)
+		}
+		return nil, services.ValidationError("The submitted filters are invalid", fields...)
+	}
+	return query, err
+}
@@ -0,0 +1,70 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/i18n"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+)
+
+// SetLocaleRequest picks the language the api answers the signed in user in, an empty locale goes
+// back to what their clients ask for.
+type SetLocaleRequest struct {
+	Locale string `json:"locale"`
+}
+
+// requestLocale is the locale c is answered in: the preference of the signed in user, then the
+// Accept-Language header of the client.
+func requestLocale(c *gin.Context) string {
+	var preference string
+	if user, ok := c.Get("user"); ok {
+		preference = user.(*models.User).Locale.String
+	}
+	return i18n.Negotiate(preference, c.GetHeader("Accept-Language"))
+}
+
+// setContentLanguage tells the client, and the caches in between, which language c is answered in.
+func setContentLanguage(c *gin.Context, locale string) {
+	c.Header("Content-Language", locale)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+}
+
+// translate returns message in the locale of c.
+func (app *App) translate(c *gin.Context, message string) string {
+	validationService := app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+	locale := requestLocale(c)
+	setContentLanguage(c, locale)
+	return validationService.Translate(locale, message)
+}
+
+// localize returns err in the locale of c. Routers built without services, as some tests do, answer
+// in English.
+func (app *App) localize(c *gin.Context, err *services.ServiceError) *services.ServiceError {
+	if app.ServiceContainer == nil {
+		return err
+	}
+	validationService := app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+	locale := requestLocale(c)
+	setContentLanguage(c, locale)
+	return validationService.Localize(err, locale)
+}
+
+// SetLocale stores the language the signed in user is answered in.
+func (app *App) SetLocale(c *gin.Context) {
+	var (
+		payload     SetLocaleRequest
+		userService = app.ServiceContainer.GetService("userService").(*services.UserService)
+		user        = c.MustGet("user").(*models.User)
+	)
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	if err := userService.SetLocale(c.Request.Context(), user, payload.Locale); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "locale updated successfully")})
+}
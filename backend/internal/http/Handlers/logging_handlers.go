@@ -0,0 +1,102 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the ids clients may choose, longer ones are replaced.
+const maxRequestIDLength = 128
+
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// RequestLogger tags every request with a correlation id, stores a logger carrying it in the
+// request context and writes one access log line once the request is done.
+func (app *App) RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.Must(uuid.NewV4()).String()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		fields := logrus.Fields{"request_id": requestID}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.IsValid() {
+			fields["trace_id"] = spanContext.TraceID().String()
+		}
+		entry := app.Logger.WithFields(fields)
+		c.Request = c.Request.WithContext(logging.WithLogger(c.Request.Context(), entry))
+		c.Next()
+
+		entry = entry.WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  RequestIP(c),
+		})
+		if len(c.Errors) > 0 {
+			entry.WithField("errors", c.Errors.String()).Error("request completed with errors")
+			return
+		}
+		entry.Info("request completed")
+	}
+}
+
+// validRequestID accepts the ids of proxies and clients that are printable ascii without spaces, an
+// id is written to every log line of the request and must not be able to forge one.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func (app *App) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, LogLevelResponse{Level: app.Logger.GetLevel().String()})
+}
+
+// SetLogLevel changes the verbosity of the running process without a restart.
+func (app *App) SetLogLevel(c *gin.Context) {
+	var payload LogLevelRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	previous := app.Logger.GetLevel().String()
+	if err := logging.SetLevel(app.Logger, payload.Level); err != nil || payload.Level == "" {
+		abortWithError(c, services.ValidationError("The submitted data is invalid", services.FieldError{
+			Field:   "level",
+			Message: "level should be one of trace, debug, info, warn, error, fatal or panic",
+		}))
+		return
+	}
+	logging.FromContext(c.Request.Context(), app.Logger).Warnf("Log level changed to %s", payload.Level)
+	app.recordAudit(c, services.AuditEvent{
+		Action:     "admin.log_level_changed",
+		TargetType: services.AuditTargetSystem,
+		TargetID:   "log_level",
+		Metadata:   map[string]interface{}{"from": previous, "to": app.Logger.GetLevel().String()},
+	})
+	c.JSON(http.StatusOK, LogLevelResponse{Level: app.Logger.GetLevel().String()})
+}
@@ -0,0 +1,98 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+	"os"
+)
+
+const (
+	// DeviceCookieName identifies a browser for magic link device binding, apps send DeviceIDHeader.
+	DeviceCookieName = "siena_device"
+	DeviceIDHeader   = "X-Device-ID"
+	deviceCookieAge  = 365 * 24 * 60 * 60
+)
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"email"`
+}
+
+type VerifyMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// RequestMagicLink mails a login link. It answers the same whether or not the account exists.
+func (app *App) RequestMagicLink(c *gin.Context) {
+	var (
+		payload           MagicLinkRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		magicLinkService  = app.ServiceContainer.GetService("magicLinkService").(*services.MagicLinkService)
+	)
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	deviceID, err := ensureDeviceID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	magicLinkService.RequestLink(c.Request.Context(), payload.Email, RequestIP(c), deviceID)
+	c.JSON(http.StatusOK, MessageResponse{
+		Message: app.translate(c, "if an account exists for this email, a login link is on its way"),
+	})
+}
+
+// VerifyMagicLink exchanges the token from the mailed link for a session, going through the second
+// factor first when the account has one.
+func (app *App) VerifyMagicLink(c *gin.Context) {
+	var (
+		payload           VerifyMagicLinkRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		magicLinkService  = app.ServiceContainer.GetService("magicLinkService").(*services.MagicLinkService)
+	)
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	user, err := magicLinkService.Redeem(c.Request.Context(), payload.Token, RequestIP(c), deviceID(c))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	app.login(c, user)
+}
+
+func deviceID(c *gin.Context) string {
+	if id := c.GetHeader(DeviceIDHeader); id != "" {
+		return id
+	}
+	id, _ := c.Cookie(DeviceCookieName)
+	return id
+}
+
+// ensureDeviceID returns the device id of the client, giving browsers that don't have one yet a cookie.
+func ensureDeviceID(c *gin.Context) (string, error) {
+	if id := deviceID(c); id != "" {
+		return id, nil
+	}
+	id, err := services.RandomToken()
+	if err != nil {
+		return "", err
+	}
+	c.SetCookie(DeviceCookieName, id, deviceCookieAge, "/", "", os.Getenv("ENV") != "dev", true)
+	return id, nil
+}
@@ -0,0 +1,177 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+	"strconv"
+)
+
+type MFAChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFAEnrollmentResponse struct {
+	Message string                 `json:"message"`
+	Data    services.MFAEnrollment `json:"data"`
+}
+
+// RecoveryCodes are shown once, only their hashes are kept.
+type RecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type RecoveryCodesResponse struct {
+	Message string        `json:"message"`
+	Data    RecoveryCodes `json:"data"`
+}
+
+// VerifyMFAChallenge is the second step of the login for accounts with two-factor authentication,
+// the code is either from the authenticator app or one of the recovery codes.
+func (app *App) VerifyMFAChallenge(c *gin.Context) {
+	var (
+		payload           MFAChallengeRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		loginProtection   = app.ServiceContainer.GetService("loginProtectionService").(*services.LoginProtectionService)
+		mfaService        = app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	)
+
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	ctx, ip := c.Request.Context(), RequestIP(c)
+	user, err := mfaService.ChallengeUser(ctx, payload.ChallengeToken)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if err = loginProtection.CheckAllowed(ctx, user.Email, ip); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	err = mfaService.VerifyCode(ctx, user, payload.Code)
+	if err == services.ErrInvalidMFACode {
+		// codes are short, guessing them is throttled like guessing passwords
+		targetType, targetID := services.UserTarget(user.ID)
+		app.recordAudit(c, services.AuditEvent{
+			Action:     "login.failed",
+			TargetType: targetType,
+			TargetID:   targetID,
+			Metadata:   map[string]interface{}{"method": "mfa"},
+		})
+		if recordErr := loginProtection.RecordFailure(ctx, user.Email, ip); recordErr != nil {
+			abortWithError(c, recordErr)
+			return
+		}
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	app.completeLogin(c, user)
+}
+
+func (app *App) BeginMFAEnrollment(c *gin.Context) {
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	enrollment, err := mfaService.BeginEnrollment(c.Request.Context(), c.MustGet("user").(*models.User))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MFAEnrollmentResponse{
+		Message: app.translate(c, "scan the provisioning uri and confirm with a code"),
+		Data:    *enrollment,
+	})
+}
+
+func (app *App) ConfirmMFAEnrollment(c *gin.Context) {
+	payload, ok := app.bindMFACode(c)
+	if !ok {
+		return
+	}
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	codes, err := mfaService.ConfirmEnrollment(c.Request.Context(), c.MustGet("user").(*models.User), payload.Code)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{
+		Message: app.translate(c, "two-factor authentication enabled, store the recovery codes somewhere safe"),
+		Data:    RecoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+func (app *App) RegenerateRecoveryCodes(c *gin.Context) {
+	payload, ok := app.bindMFACode(c)
+	if !ok {
+		return
+	}
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	codes, err := mfaService.RegenerateRecoveryCodes(c.Request.Context(), c.MustGet("user").(*models.User), payload.Code)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{
+		Message: app.translate(c, "recovery codes regenerated"),
+		Data:    RecoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+func (app *App) DisableMFA(c *gin.Context) {
+	payload, ok := app.bindMFACode(c)
+	if !ok {
+		return
+	}
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	if err := mfaService.Disable(c.Request.Context(), c.MustGet("user").(*models.User), payload.Code); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "two-factor authentication disabled")})
+}
+
+// ResetUserMFA lets an admin remove two-factor authentication from an account whose owner lost
+// both their device and their recovery codes.
+func (app *App) ResetUserMFA(c *gin.Context) {
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, services.NotFoundError("User not found", nil))
+		return
+	}
+	if err = mfaService.Reset(c.Request.Context(), userID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "two-factor authentication reset")})
+}
+
+func (app *App) bindMFACode(c *gin.Context) (MFACodeRequest, bool) {
+	var (
+		payload           MFACodeRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+	)
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return payload, false
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return payload, false
+	}
+	return payload, true
+}
@@ -1,38 +1,74 @@
 package Handlers
 
 import (
-	"fmt"
-	"github.com/dgrijalva/jwt-go"
+	"database/sql"
 	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/models"
 	"github.com/ntwarijoshua/siena/internal/services"
-	"net/http"
-	"os"
-	"strings"
+	"github.com/pkg/errors"
 )
 
-func (app *App) AuthMiddleware() gin.HandlerFunc  {
+// AuthMiddleware authenticates requests with a bearer token or an api key, falling back to the
+// session cookie used by the web frontend.
+func (app *App) AuthMiddleware() gin.HandlerFunc {
+	userService := app.ServiceContainer.GetService("userService").(*services.UserService)
+	apiKeyService := app.ServiceContainer.GetService("apiKeyService").(*services.APIKeyService)
+	return Authenticate(
+		BearerAuthenticator{Verifier: userService},
+		APIKeyAuthenticator{Verifier: apiKeyService},
+		CookieAuthenticator{Verifier: userService, CookieName: AuthCookieName},
+	)
+}
+
+// RequireScope limits requests authenticated with an api key to keys granted scope. Other requests
+// act with the full rights of the user and go through.
+func RequireScope(scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := strings.Split(c.GetHeader("AUTHORIZATION"), "Bearer ")[1]
-		jwtToken, err := jwt.Parse(token, func(token *jwt.Token) (i interface{}, err error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("Unexpected Signing Method: %v !", token.Header["alg"])
-			}
-			return []byte(os.Getenv("JWT_SIGNING_KEY")), nil
-		})
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, "Unauthorized")
+		if c.GetString(authMethodKey) != AuthMethodAPIKey {
+			c.Next()
 			return
 		}
-		if claims, ok := jwtToken.Claims.(jwt.MapClaims); ok && jwtToken.Valid {
-			userService := app.ServiceContainer.GetService("userService").(*services.UserService)
-			if user, err := userService.GetUserByMail(claims["email"].(string)); err == nil {
-				c.Set("user", user)
+		for _, granted := range c.GetStringSlice(apiKeyScopesKey) {
+			if granted == scope {
 				c.Next()
+				return
 			}
+		}
+		abortWithError(c, services.ForbiddenError("This api key is missing the {0} scope").Naming(scope))
+	}
+}
+
+// DenyAPIKeys keeps api keys away from account management, such as creating more keys.
+func DenyAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(authMethodKey) == AuthMethodAPIKey {
+			abortWithError(c, services.ForbiddenError("This action can't be performed with an api key"))
+			return
+		}
+		c.Next()
+	}
+}
 
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, "Unauthorized")
+// RequireRole only lets through authenticated users holding one of the given role slugs.
+func (app *App) RequireRole(slugs ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, ok := c.Get("user")
+		if !ok {
+			abortWithError(c, services.UnauthorizedError("Unauthorized", nil))
 			return
 		}
+		// a deleted role grants nothing, its holders are turned away like those of any other role
+		role, err := app.ServiceContainer.Store.Roles().FindByID(c.Request.Context(), user.(*models.User).RoleID)
+		if err != nil && errors.Cause(err) != sql.ErrNoRows {
+			abortWithError(c, err)
+			return
+		}
+		for _, slug := range slugs {
+			if role != nil && role.Slug == slug {
+				c.Next()
+				return
+			}
+		}
+		abortWithError(c, services.ForbiddenError("You are not allowed to perform this action"))
 	}
 }
@@ -0,0 +1,221 @@
+package Handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/volatiletech/null"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// OIDCStateCookieName ties the provider callback to the browser that started the sign in, so that
+// nobody can log a victim into their own account by sending them a callback link.
+const OIDCStateCookieName = "siena_oidc_state"
+
+// The frontend pages the provider callback lands the browser on.
+const (
+	oidcCompletePath = "/login/complete"
+	oidcMFAPath      = "/login/mfa"
+	oidcErrorPath    = "/login"
+	oidcLinkedPath   = "/account/identities"
+)
+
+type OIDCProvidersResponse struct {
+	Data []string `json:"data"`
+}
+
+// AuthorizationURL is where the browser is sent to sign in with a provider.
+type AuthorizationURL struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type LinkIdentityResponse struct {
+	Data AuthorizationURL `json:"data"`
+}
+
+// IdentityResponse is an external identity linked to an account.
+type IdentityResponse struct {
+	ID        int         `json:"id"`
+	UserID    int         `json:"user_id"`
+	Provider  string      `json:"provider"`
+	Subject   string      `json:"subject"`
+	Email     null.String `json:"email,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type IdentitiesResponse struct {
+	Data []IdentityResponse `json:"data"`
+}
+
+func newIdentityResponse(identity *models.UserIdentity) IdentityResponse {
+	return IdentityResponse{
+		ID:        identity.ID,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+		UpdatedAt: identity.UpdatedAt,
+	}
+}
+
+func (app *App) ListOIDCProviders(c *gin.Context) {
+	oidcService := app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+	c.JSON(http.StatusOK, OIDCProvidersResponse{Data: oidcService.Providers()})
+}
+
+// StartOIDCLogin sends the browser to the provider's sign in page.
+func (app *App) StartOIDCLogin(c *gin.Context) {
+	oidcService := app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+	authURL, state, err := oidcService.Begin(c.Request.Context(), c.Param("provider"), 0)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	setOIDCStateCookie(c, state)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// LinkIdentity starts linking an external identity to the signed in account. It answers with the
+// url to send the browser to since api clients can't follow redirects to another origin.
+func (app *App) LinkIdentity(c *gin.Context) {
+	oidcService := app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+	user := c.MustGet("user").(*models.User)
+	authURL, state, err := oidcService.Begin(c.Request.Context(), c.Param("provider"), user.ID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	setOIDCStateCookie(c, state)
+	c.JSON(http.StatusOK, LinkIdentityResponse{Data: AuthorizationURL{AuthorizationURL: authURL}})
+}
+
+// OIDCCallback is where providers send the browser back to, with a GET or, for form_post
+// providers, a POST. It ends by redirecting to the frontend.
+func (app *App) OIDCCallback(c *gin.Context) {
+	var (
+		oidcService = app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+		mfaService  = app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+		ctx         = c.Request.Context()
+		state       = c.Request.FormValue("state")
+	)
+	if providerErr := c.Request.FormValue("error"); providerErr != "" {
+		redirectToApp(c, oidcErrorPath, url.Values{"error": {providerErr}}, "")
+		return
+	}
+	cookieState, _ := c.Cookie(OIDCStateCookieName)
+	clearOIDCStateCookie(c)
+	if state == "" || cookieState != state {
+		redirectToApp(c, oidcErrorPath, url.Values{"error": {"invalid_state"}}, "")
+		return
+	}
+
+	result, err := oidcService.Complete(ctx, c.Param("provider"), c.Request.FormValue("code"), state)
+	if err != nil {
+		serviceErr := services.AsServiceError(err)
+		if serviceErr.Kind == services.KindInternal {
+			logging.FromContext(ctx, app.Logger).Errorf("OIDC sign in failed %s", err)
+		}
+		redirectToApp(c, oidcErrorPath, url.Values{"error": {string(serviceErr.Kind)}}, "")
+		return
+	}
+	if result.Linked {
+		redirectToApp(c, oidcLinkedPath, url.Values{"linked": {c.Param("provider")}}, "")
+		return
+	}
+
+	mfaEnabled, err := mfaService.Enabled(ctx, result.User.ID)
+	if err != nil {
+		logging.FromContext(ctx, app.Logger).Errorf("OIDC sign in failed %s", err)
+		redirectToApp(c, oidcErrorPath, url.Values{"error": {string(services.KindInternal)}}, "")
+		return
+	}
+	if mfaEnabled {
+		challenge, err := mfaService.IssueChallenge(result.User)
+		if err != nil {
+			redirectToApp(c, oidcErrorPath, url.Values{"error": {string(services.KindInternal)}}, "")
+			return
+		}
+		// the fragment never reaches a server, so the challenge stays out of access logs
+		redirectToApp(c, oidcMFAPath, nil, "challenge_token="+url.QueryEscape(challenge))
+		return
+	}
+	if _, err = app.startSession(c, result.User); err != nil {
+		redirectToApp(c, oidcErrorPath, url.Values{"error": {string(services.KindInternal)}}, "")
+		return
+	}
+	redirectToApp(c, oidcCompletePath, nil, "")
+}
+
+func (app *App) ListIdentities(c *gin.Context) {
+	oidcService := app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+	identities, err := oidcService.Identities(c.Request.Context(), c.MustGet("user").(*models.User).ID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	response := make([]IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, newIdentityResponse(identity))
+	}
+	c.JSON(http.StatusOK, IdentitiesResponse{Data: response})
+}
+
+func (app *App) UnlinkIdentity(c *gin.Context) {
+	oidcService := app.ServiceContainer.GetService("oidcService").(*services.OIDCService)
+	identityID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, services.NotFoundError("Identity not found", nil))
+		return
+	}
+	if err = oidcService.Unlink(c.Request.Context(), c.MustGet("user").(*models.User).ID, identityID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "identity unlinked")})
+}
+
+func redirectToApp(c *gin.Context, path string, query url.Values, fragment string) {
+	target := services.AppURL() + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	if fragment != "" {
+		target += "#" + fragment
+	}
+	c.Redirect(http.StatusSeeOther, target)
+}
+
+// setOIDCStateCookie has to survive the cross site POST of form_post providers, which needs
+// SameSite=None. gin's SetCookie can't set SameSite so the cookie is written directly.
+func setOIDCStateCookie(c *gin.Context, state string) {
+	cookie := &http.Cookie{
+		Name:     OIDCStateCookieName,
+		Value:    state,
+		Path:     "/api/v1/auth/oidc",
+		MaxAge:   int(services.OIDCStateLifetime.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	if os.Getenv("ENV") != "dev" {
+		cookie.Secure = true
+		cookie.SameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(c.Writer, cookie)
+}
+
+func clearOIDCStateCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     OIDCStateCookieName,
+		Value:    "",
+		Path:     "/api/v1/auth/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
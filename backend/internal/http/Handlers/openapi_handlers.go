@@ -0,0 +1,515 @@
+package Handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/openapi"
+	"github.com/ntwarijoshua/siena/internal/services"
+)
+
+// Security schemes, any one of them authenticates a request to a protected route.
+const (
+	bearerAuth = "bearerAuth"
+	cookieAuth = "cookieAuth"
+	apiKeyAuth = "apiKeyAuth"
+)
+
+// swaggerUIVersion is the swagger-ui-dist release the docs page loads.
+const swaggerUIVersion = "5.11.0"
+
+var (
+	protectedSecurity = []string{bearerAuth, cookieAuth, apiKeyAuth}
+	// sessionSecurity is for account management, api keys are turned away
+	sessionSecurity = []string{bearerAuth, cookieAuth}
+)
+
+// apiRoutes is the contract the frontend is built against, every route the router serves is in it.
+// Request and response types are the ones the handlers bind and render, the contract tests keep them
+// honest.
+var apiRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/healthz", Tag: "health",
+		Summary: "Check that the process is up",
+		Responses: map[int]interface{}{
+			http.StatusOK: LivenessResponse{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/readyz", Tag: "health",
+		Summary: "Check the dependencies the api needs to serve traffic",
+		Responses: map[int]interface{}{
+			http.StatusOK:                 services.HealthReport{},
+			http.StatusServiceUnavailable: services.HealthReport{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/metrics", Tag: "health",
+		Summary: "Prometheus metrics, in the text exposition format",
+		Responses: map[int]interface{}{
+			http.StatusOK: nil,
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/openapi.json", Tag: "api",
+		Summary: "This document",
+		Responses: map[int]interface{}{
+			http.StatusOK: openapi.Document{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/docs", Tag: "api",
+		Summary: "This document rendered as html",
+		Responses: map[int]interface{}{
+			http.StatusOK: nil,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth", Tag: "auth",
+		Summary: "Sign in with an email and password",
+		Request: AuthenticateUserRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:           loginResponse,
+			http.StatusBadRequest:   ErrorResponse{},
+			http.StatusUnauthorized: ErrorResponse{},
+			http.StatusForbidden:    ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/mfa", Tag: "auth",
+		Summary: "Complete a sign in with a second factor code",
+		Request: MFAChallengeRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:           AuthenticateUserResponse{},
+			http.StatusBadRequest:   ErrorResponse{},
+			http.StatusUnauthorized: ErrorResponse{},
+			http.StatusForbidden:    ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/magic-link", Tag: "auth",
+		Summary: "Mail a single use login link",
+		Request: MagicLinkRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/magic-link/verify", Tag: "auth",
+		Summary: "Sign in with the token of a mailed login link",
+		Request: VerifyMagicLinkRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:           loginResponse,
+			http.StatusBadRequest:   ErrorResponse{},
+			http.StatusUnauthorized: ErrorResponse{},
+			http.StatusForbidden:    ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/auth/oidc", Tag: "auth",
+		Summary: "List the identity providers users can sign in with",
+		Responses: map[int]interface{}{
+			http.StatusOK: OIDCProvidersResponse{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/auth/oidc/:provider", Tag: "auth",
+		Summary: "Redirect the browser to an identity provider to sign in",
+		Responses: map[int]interface{}{
+			http.StatusFound:    nil,
+			http.StatusNotFound: ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/auth/oidc/:provider/callback", Tag: "auth",
+		Summary: "Where identity providers send the browser back to, it is redirected to the app",
+		Query:   []string{"code", "state", "error"},
+		Responses: map[int]interface{}{
+			http.StatusSeeOther: nil,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/oidc/:provider/callback", Tag: "auth",
+		Summary: "Where form_post identity providers send the browser back to, it is redirected to the app",
+		Responses: map[int]interface{}{
+			http.StatusSeeOther: nil,
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/auth/unlock", Tag: "auth",
+		Summary: "Lift a lockout with the token of a mailed unlock link",
+		Request: UnlockAccountRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/users", Tag: "users",
+		Summary: "Sign up, a confirmation link is mailed to the address",
+		Request: CreateUserRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         CreateUserResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+			http.StatusConflict:   ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/users/confirm", Tag: "users",
+		Summary: "Confirm an account with the mailed confirmation link",
+		Request: ConfirmUserRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/users/restore", Tag: "users",
+		Summary: "Restore a deleted account before it is purged",
+		Request: RestoreAccountRequest{},
+		Responses: map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+			http.StatusConflict:   ErrorResponse{},
+		},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/", Tag: "api",
+		Summary:  "Check the credentials of a request",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK: "",
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/me", Tag: "account",
+		Summary:  "The signed in account and its profile, api keys need the profile:read scope",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:          GetAccountResponse{},
+			http.StatusNotModified: nil,
+		}),
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/me/locale", Tag: "account",
+		Summary:  "Set the language the account is answered in, api keys need the profile:write scope",
+		Security: protectedSecurity,
+		Request:  SetLocaleRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/me", Tag: "account",
+		Summary:  "Delete the account, it can be restored from the mailed link until it is purged",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusAccepted: DeleteAccountResponse{},
+			http.StatusConflict: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/export", Tag: "account",
+		Summary:  "Export the data of the account, a download link is mailed",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusAccepted: ExportAccountResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/me/export", Tag: "account",
+		Summary:  "Download an export, a zip archive, with the token of the mailed link",
+		Security: sessionSecurity,
+		Query:    []string{"token"},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       nil,
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/me/identities", Tag: "account",
+		Summary:  "List the external identities linked to the account",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK: IdentitiesResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/identities/:provider", Tag: "account",
+		Summary:  "Start linking an identity of a provider, the browser is to be sent to the url answered",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       LinkIdentityResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/me/identities/:id", Tag: "account",
+		Summary:  "Unlink an external identity",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       MessageResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/mfa", Tag: "mfa",
+		Summary:  "Start enrolling an authenticator app",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       MFAEnrollmentResponse{},
+			http.StatusConflict: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/mfa/confirm", Tag: "mfa",
+		Summary:  "Enable two-factor authentication with a code of the enrolled app",
+		Security: sessionSecurity,
+		Request:  MFACodeRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         RecoveryCodesResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+			http.StatusConflict:   ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/mfa/recovery-codes", Tag: "mfa",
+		Summary:  "Replace the recovery codes",
+		Security: sessionSecurity,
+		Request:  MFACodeRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         RecoveryCodesResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/me/mfa", Tag: "mfa",
+		Summary:  "Disable two-factor authentication",
+		Security: sessionSecurity,
+		Request:  MFACodeRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         MessageResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/me/api-keys", Tag: "api-keys",
+		Summary:  "List the api keys of the account",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK: APIKeysResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/me/api-keys", Tag: "api-keys",
+		Summary:  "Issue an api key, the key is only answered this once",
+		Security: sessionSecurity,
+		Request:  CreateAPIKeyRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusCreated:    CreateAPIKeyResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/me/api-keys/:key", Tag: "api-keys",
+		Summary:  "Revoke an api key",
+		Security: sessionSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       MessageResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/log-level", Tag: "admin",
+		Summary:  "The log level of the running process",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK: LogLevelResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/admin/log-level", Tag: "admin",
+		Summary:  "Change the log level of the running process",
+		Security: protectedSecurity,
+		Request:  LogLevelRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         LogLevelResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/users", Tag: "admin",
+		Summary:  "List the active accounts a page at a time",
+		Security: protectedSecurity,
+		Query: []string{
+			"limit", "sort", "cursor",
+			"filter[id]", "filter[email]", "filter[role_id]", "filter[confirmed]", "filter[created_at]",
+		},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:          UsersPageResponse{},
+			http.StatusNotModified: nil,
+			http.StatusBadRequest:  ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/admin/users/:id/mfa", Tag: "admin",
+		Summary:  "Remove two-factor authentication from an account",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       MessageResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/users/:id/api-keys", Tag: "admin",
+		Summary:  "List the api keys of an account",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       APIKeysResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/admin/users/:id/api-keys", Tag: "admin",
+		Summary:  "Issue an api key for an account, e.g. for a partner integration",
+		Security: protectedSecurity,
+		Request:  CreateAPIKeyRequest{},
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusCreated:    CreateAPIKeyResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+			http.StatusNotFound:   ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/admin/api-keys/:key", Tag: "admin",
+		Summary:  "Revoke the api key of any account",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:       MessageResponse{},
+			http.StatusNotFound: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/audit-events", Tag: "admin",
+		Summary:  "List audit events newest first, the next page is asked for with before_id",
+		Security: protectedSecurity,
+		Query:    auditFilterParams,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         AuditEventsResponse{},
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/audit-events/export", Tag: "admin",
+		Summary:  "Download the audit events matching the filters as csv, oldest first",
+		Security: protectedSecurity,
+		Query:    auditFilterParams,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK:         nil,
+			http.StatusBadRequest: ErrorResponse{},
+		}),
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/audit-events/verify", Tag: "admin",
+		Summary:  "Check the hash chain of the audit trail for tampering",
+		Security: protectedSecurity,
+		Responses: withAuthErrors(map[int]interface{}{
+			http.StatusOK: AuditTrailResponse{},
+		}),
+	},
+}
+
+// auditFilterParams are the query parameters read by auditFilterFromQuery.
+var auditFilterParams = []string{"action", "target_type", "target_id", "ip_address", "actor_id", "before_id", "limit", "from", "to"}
+
+// withAuthErrors adds what protected routes answer requests they can't authenticate or don't allow
+// to responses.
+func withAuthErrors(responses map[int]interface{}) map[int]interface{} {
+	responses[http.StatusUnauthorized] = ErrorResponse{}
+	responses[http.StatusForbidden] = ErrorResponse{}
+	return responses
+}
+
+// loginResponse is what signing in answers with: a token, or a challenge when the account has a
+// second factor.
+var loginResponse = openapi.AnyOf{AuthenticateUserResponse{}, MFAChallengeResponse{}}
+
+var apiDocument struct {
+	once     sync.Once
+	document *openapi.Document
+}
+
+// APIDocument is the OpenAPI description of the api.
+func APIDocument() *openapi.Document {
+	apiDocument.once.Do(func() {
+		apiDocument.document = openapi.Build(
+			openapi.Info{Title: "Siena API", Version: "1"},
+			[]openapi.Server{{URL: services.APIURL()}},
+			map[string]*openapi.SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				cookieAuth: {Type: "apiKey", In: "cookie", Name: AuthCookieName},
+				apiKeyAuth: {Type: "apiKey", In: "header", Name: APIKeyHeader},
+			},
+			// every route is rate limited and can fail
+			map[int]interface{}{
+				http.StatusTooManyRequests:     ErrorResponse{},
+				http.StatusInternalServerError: ErrorResponse{},
+			},
+			apiRoutes,
+		)
+	})
+	return apiDocument.document
+}
+
+func (app *App) OpenAPISpec(c *gin.Context) {
+	c.JSON(http.StatusOK, APIDocument())
+}
+
+// APIDocs renders the OpenAPI document with swagger ui. The page needs more than the json api's
+// content security policy allows, it is relaxed for this response only: swagger ui comes from its
+// cdn and the inline script that starts it carries a nonce. The page is sandboxed into an origin of
+// its own, the cdn's scripts can't read the cookies of the api or call it as the user, so the
+// document is inlined and requests can't be tried out from the page.
+func (app *App) APIDocs(c *gin.Context) {
+	nonce, err := services.RandomToken()
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	// the encoder escapes <, > and &, the document can't close the script it is written in
+	document, err := json.Marshal(APIDocument())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	cdn := "https://cdn.jsdelivr.net"
+	c.Header("Content-Security-Policy", fmt.Sprintf(
+		"sandbox allow-scripts; default-src 'none'; script-src 'nonce-%s' %s; style-src %s 'unsafe-inline'; "+
+			"img-src %s data:; frame-ancestors 'none'",
+		nonce, cdn, cdn, cdn,
+	))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(fmt.Sprintf(docsPage, swaggerUIVersion, swaggerUIVersion, nonce, document)))
+}
+
+// docsPage is served from /api/v1/docs.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Siena API</title>
+<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@%s/swagger-ui.css" crossorigin="anonymous">
+</head>
+<body>
+<div id="docs"></div>
+<script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@%s/swagger-ui-bundle.js" crossorigin="anonymous"></script>
+<script nonce="%s">
+SwaggerUIBundle({spec: %s, dom_id: "#docs", supportedSubmitMethods: []});
+</script>
+</body>
+</html>
+`
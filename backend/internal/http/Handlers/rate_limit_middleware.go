@@ -0,0 +1,90 @@
+package Handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/ratelimit"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/pkg/errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RateLimitKeyFunc picks the identity a request is counted against, an empty key skips the limiter.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP counts requests against the address ClientIP resolved. Requests whose address is
+// unknown share a single counter rather than escaping the limit.
+func RateLimitByIP(c *gin.Context) string {
+	if ip := RequestIP(c); ip != "" {
+		return "ip:" + ip
+	}
+	return "ip:unknown"
+}
+
+// RateLimitByUser must run after authentication.
+func RateLimitByUser(c *gin.Context) string {
+	user, ok := c.Get("user")
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("user:%d", user.(*models.User).ID)
+}
+
+// RateLimitByAPIKey counts requests against the api key that authenticated them, so it must run
+// after authentication. A key header on a request authenticated otherwise is ignored, or sending a
+// new one each time would buy a new budget.
+func RateLimitByAPIKey(c *gin.Context) string {
+	if c.GetString(authMethodKey) != AuthMethodAPIKey {
+		return ""
+	}
+	return fmt.Sprintf("key:%d", c.GetInt(apiKeyIDKey))
+}
+
+// FirstRateLimitKey uses the first non empty key, e.g. the user when authenticated and the ip otherwise.
+func FirstRateLimitKey(keyFuncs ...RateLimitKeyFunc) RateLimitKeyFunc {
+	return func(c *gin.Context) string {
+		for _, keyFunc := range keyFuncs {
+			if key := keyFunc(c); key != "" {
+				return key
+			}
+		}
+		return ""
+	}
+}
+
+// RateLimit limits the routes it is attached to, name separates the counters of different groups.
+func (app *App) RateLimit(name string, limit ratelimit.Limit, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	store := app.ServiceContainer.GetService("rateLimitStore").(ratelimit.Store)
+	return RateLimitWith(store, name, limit, keyFunc)
+}
+
+// RateLimitWith reports the state of the limit in RateLimit-* headers and rejects requests over it.
+// The limiter fails closed: requests it can't count are refused, or a store outage would lift every
+// limit at once.
+func RateLimitWith(store ratelimit.Store, name string, limit ratelimit.Limit, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		if key == "" {
+			c.Next()
+			return
+		}
+		result, err := store.Hit(c.Request.Context(), name+":"+key, limit, time.Now())
+		if err != nil {
+			abortWithError(c, errors.Wrap(err, "rate limiter unavailable"))
+			return
+		}
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", reset)
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
+			abortWithError(c, services.TooManyRequestsError("Rate limit exceeded, slow down"))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,142 @@
+package Handlers
+
+import (
+	"crypto/subtle"
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CSRFCookieName = "siena_csrf"
+	CSRFHeader     = "X-CSRF-Token"
+	authMethodKey  = "auth_method"
+)
+
+type CORSConfig struct {
+	// AllowedOrigins lists exact origins, "*" allows any origin but never with credentials.
+	AllowedOrigins   []string
+	AllowCredentials bool
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	MaxAge           time.Duration
+}
+
+// CORSConfigFromEnv reads the comma separated CORS_ALLOWED_ORIGINS and CORS_ALLOW_CREDENTIALS.
+func CORSConfigFromEnv() CORSConfig {
+	var origins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimRight(origin, "/"))
+		}
+	}
+	credentials, _ := strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS"))
+	return CORSConfig{
+		AllowedOrigins:   origins,
+		AllowCredentials: credentials,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type", "Accept-Language", "If-None-Match", CSRFHeader, RequestIDHeader, APIKeyHeader, DeviceIDHeader},
+		ExposedHeaders:   []string{RequestIDHeader, "ETag", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+func (config CORSConfig) allows(origin string) (allowed bool, wildcard bool) {
+	for _, allowedOrigin := range config.AllowedOrigins {
+		if allowedOrigin == "*" {
+			return !config.AllowCredentials, true
+		}
+		if strings.EqualFold(allowedOrigin, origin) {
+			return true, false
+		}
+	}
+	return false, false
+}
+
+// CORS answers preflight requests and decorates responses to allowed origins. Requests from other
+// origins are served without CORS headers so browsers keep them from reading the response.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	methods := strings.Join(config.AllowedMethods, ", ")
+	headers := strings.Join(config.AllowedHeaders, ", ")
+	exposed := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+		allowed, wildcard := config.allows(origin)
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !allowed {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+		if wildcard {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Header("Access-Control-Expose-Headers", exposed)
+		c.Next()
+	}
+}
+
+// SecurityHeaders sets headers that are safe for a JSON api. HSTS is left out on plain http dev setups.
+func SecurityHeaders(hsts bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		if hsts {
+			header.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		}
+		header.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "no-referrer")
+		c.Next()
+	}
+}
+
+// CSRFProtection enforces the double submit pattern on requests authenticated by cookie: unsafe
+// methods must echo the csrf cookie in the X-CSRF-Token header, which a cross site form can't do.
+// It must run after authentication.
+func CSRFProtection() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if c.GetString(authMethodKey) != AuthMethodCookie {
+			c.Next()
+			return
+		}
+		cookie, err := c.Cookie(CSRFCookieName)
+		header := c.GetHeader(CSRFHeader)
+		if err != nil || cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) != 1 {
+			abortWithError(c, services.ForbiddenError("Missing or invalid CSRF token"))
+			return
+		}
+		c.Next()
+	}
+}
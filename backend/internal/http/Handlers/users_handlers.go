@@ -1,53 +1,116 @@
 package Handlers
 
 import (
-	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/listing"
+	"github.com/ntwarijoshua/siena/internal/logging"
 	"github.com/ntwarijoshua/siena/internal/models"
 	"github.com/ntwarijoshua/siena/internal/services"
-	logger "github.com/sirupsen/logrus"
 	"github.com/volatiletech/null"
 	"net/http"
+	"os"
 	"time"
 )
 
 type CreateUserRequest struct {
 	Email    string `json:"email" validate:"email,is_unique"`
-	Password string `json:"password" validate:"min=6"`
+	Password string `json:"password" validate:"required"`
 	Names    string `json:"names" validate:"required"`
 	DOB      string `json:"date_of_birth" validate:"required"`
 }
 
 type AuthenticateUserRequest struct {
-	Email string 	`json:"email" validate:"email"`
+	Email    string `json:"email" validate:"email"`
 	Password string `json:"password" validate:"required"`
 }
 
+type UnlockAccountRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ConfirmUserRequest struct {
+	ID    int    `json:"id" validate:"required"`
+	Token string `json:"token" validate:"required"`
+}
+
+// UserResponse is an account as its owner gets to see it, the password hash stays on the server.
+type UserResponse struct {
+	ID        int       `json:"id"`
+	Email     string    `json:"email"`
+	Confirmed bool      `json:"confirmed"`
+	ProfileID int       `json:"profile_id"`
+	RoleID    int       `json:"role_id"`
+	Locale    string    `json:"locale,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
 
+type CreateUserResponse struct {
+	Message string       `json:"message"`
+	Data    UserResponse `json:"data"`
+}
+
+type AuthenticateUserResponse struct {
+	Message string `json:"message"`
+	Token   string `json:"token"`
+}
+
+// MFAChallengeResponse answers logins of accounts with two-factor authentication, the challenge token
+// is exchanged for a session along with a code at /auth/mfa.
+type MFAChallengeResponse struct {
+	Message        string `json:"message"`
+	MFARequired    bool   `json:"mfa_required"`
+	ChallengeToken string `json:"challenge_token"`
+}
 
-func (app *App)CreateUser(c *gin.Context) {
+// UsersPageResponse documents the envelope AdminListUsers answers with, listing.Envelope can't say
+// what its data holds.
+type UsersPageResponse struct {
+	Data  []UserResponse `json:"data"`
+	Links listing.Links  `json:"links"`
+}
+
+func newUserResponse(user *models.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Confirmed: user.Confirmed.Bool,
+		ProfileID: user.ProfileID,
+		RoleID:    user.RoleID,
+		Locale:    user.Locale.String,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
+func (app *App) CreateUser(c *gin.Context) {
 	var (
-		payload CreateUserRequest
+		payload           CreateUserRequest
 		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
-		dateLayout = "2006-01-02"
-		userService = app.ServiceContainer.GetService("userService").(*services.UserService)
-		)
-
+		dateLayout        = "2006-01-02"
+		userService       = app.ServiceContainer.GetService("userService").(*services.UserService)
+		passwordService   = app.ServiceContainer.GetService("passwordService").(*services.PasswordService)
+	)
 
-	if err := c.BindJSON(&payload); err != nil {
-		app.Logger.Errorf("Error occurred while decoding user payload:", err)
-		c.JSON(http.StatusBadRequest, map[string]string{"error": "failed parsing payload"})
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
 		return
 	}
 	validate := validationService.GetValidator()
-	if err := validate.Struct(payload); err != nil {
-		c.JSON(http.StatusBadRequest, validationService.GenerateValidationResponse(err))
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	if err := passwordService.Validate(c.Request.Context(), payload.Password, payload.Email, payload.Names); err != nil {
+		abortWithError(c, err)
 		return
 	}
 	dob, err := time.Parse(dateLayout, payload.DOB)
 	if err != nil {
-		logger.Errorf("Error occurred while parsing user date of birth", err)
-		c.JSON(http.StatusBadRequest, map[string]interface{}{"error occurred": err})
+		abortWithError(c, services.ValidationError("The submitted data is invalid", services.FieldError{
+			Field:   "date_of_birth",
+			Message: "date_of_birth should be formatted as YYYY-MM-DD",
+		}))
 		return
 	}
 	user := models.User{
@@ -56,60 +119,219 @@ func (app *App)CreateUser(c *gin.Context) {
 	}
 
 	profile := models.Profile{
-		Names:   null.StringFrom(payload.Names),
-		TagLine: null.StringFrom(""),
-		DateOfBirth:     null.TimeFrom(dob),
+		Names:       null.StringFrom(payload.Names),
+		TagLine:     null.StringFrom(""),
+		DateOfBirth: null.TimeFrom(dob),
 	}
-	newUser, err := userService.CreateUser(user, profile)
-
+	newUser, err := userService.CreateUser(c.Request.Context(), user, profile)
 	if err != nil {
-		logger.Errorf("Error occurred trying to create user", err)
-		c.JSON(http.StatusInternalServerError, map[string]interface{}{"errors occurred": err})
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "user created successfully",
-		"data":    newUser,
+	c.JSON(http.StatusOK, CreateUserResponse{
+		Message: app.translate(c, "user created successfully"),
+		Data:    newUserResponse(&newUser),
 	})
 }
 
-func (app *App) AuthenticateUser (c *gin.Context)  {
+func (app *App) AuthenticateUser(c *gin.Context) {
 	var (
-		payload AuthenticateUserRequest
-		usersService = app.ServiceContainer.GetService("userService").(*services.UserService)
+		payload           AuthenticateUserRequest
+		usersService      = app.ServiceContainer.GetService("userService").(*services.UserService)
 		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		loginProtection   = app.ServiceContainer.GetService("loginProtectionService").(*services.LoginProtectionService)
 	)
 
-	if err := c.BindJSON(&payload); err != nil {
-		app.Logger.Errorf("Error occurred while decoding user payload:", err)
-		c.JSON(400, map[string]string{"error": "failed parsing payload"})
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
 		return
 	}
 	validate := validationService.GetValidator()
-	if err := validate.Struct(payload); err != nil {
-		c.JSON(http.StatusBadRequest, validationService.GenerateValidationResponse(err))
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
 		return
 	}
-	user, err := usersService.GetUserByMail(payload.Email)
+	ctx, ip := c.Request.Context(), RequestIP(c)
+	if err := loginProtection.CheckAllowed(ctx, payload.Email, ip); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	user, err := usersService.Authenticate(ctx, payload.Email, payload.Password)
+	if err == services.ErrInvalidCredentials {
+		app.recordAudit(c, services.AuditEvent{
+			Action:     "login.failed",
+			TargetType: services.AuditTargetEmail,
+			TargetID:   services.NormalizeEmail(payload.Email),
+			Metadata:   map[string]interface{}{"method": "password"},
+		})
+		if recordErr := loginProtection.RecordFailure(ctx, payload.Email, ip); recordErr != nil {
+			// a failure that isn't counted would let guessing go on forever
+			abortWithError(c, recordErr)
+			return
+		}
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	app.login(c, user)
+}
+
+// login hands out the access token, or an MFA challenge first when the account has two-factor
+// authentication enabled.
+func (app *App) login(c *gin.Context, user *models.User) {
+	if user.Deleted {
+		abortWithError(c, errAccountDeleted)
+		return
+	}
+	mfaService := app.ServiceContainer.GetService("mfaService").(*services.MFAService)
+	mfaEnabled, err := mfaService.Enabled(c.Request.Context(), user.ID)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, map[string]string{
-			"error": fmt.Sprintf("User with this email not found %s", err),
+		abortWithError(c, err)
+		return
+	}
+	if mfaEnabled {
+		// the login only counts as successful once the second factor is in
+		challenge, err := mfaService.IssueChallenge(user)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, MFAChallengeResponse{
+			Message:        app.translate(c, "Two-factor authentication required"),
+			MFARequired:    true,
+			ChallengeToken: challenge,
 		})
 		return
 	}
-	token, err := usersService.GetJWTToken(user, payload.Password)
+	app.completeLogin(c, user)
+}
+
+// completeLogin records the successful login and hands out the access token.
+func (app *App) completeLogin(c *gin.Context, user *models.User) {
+	token, err := app.startSession(c, user)
 	if err != nil {
-		app.Logger.Errorf("Error occurred while generating user token:", err)
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed generating token"})
+		abortWithError(c, err)
 		return
 	}
-	c.JSON(http.StatusOK, map[string]string{
-		"message" : "Authenticate Successfully",
-		"token":token,
+	c.JSON(http.StatusOK, AuthenticateUserResponse{
+		Message: app.translate(c, "Authenticate Successfully"),
+		Token:   token,
+	})
+}
+
+// startSession records the successful login, issues the access token and sets the session cookies.
+func (app *App) startSession(c *gin.Context, user *models.User) (string, error) {
+	var (
+		usersService    = app.ServiceContainer.GetService("userService").(*services.UserService)
+		loginProtection = app.ServiceContainer.GetService("loginProtectionService").(*services.LoginProtectionService)
+		ctx             = c.Request.Context()
+	)
+	if err := loginProtection.RecordSuccess(ctx, user.Email, RequestIP(c)); err != nil {
+		logging.FromContext(ctx, app.Logger).Errorf("Could not record successful login %s", err)
+	}
+	token, err := usersService.IssueJWTToken(user)
+	if err != nil {
+		return "", err
+	}
+	targetType, targetID := services.UserTarget(user.ID)
+	app.recordAudit(c, services.AuditEvent{
+		Action:     "login.succeeded",
+		ActorID:    user.ID,
+		TargetType: targetType,
+		TargetID:   targetID,
 	})
+	setAuthCookie(c, token)
+	return token, nil
 }
 
+func (app *App) ConfirmUser(c *gin.Context) {
+	var (
+		payload           ConfirmUserRequest
+		usersService      = app.ServiceContainer.GetService("userService").(*services.UserService)
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+	)
 
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	if _, err := usersService.ConfirmUser(c.Request.Context(), payload.ID, payload.Token); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "account confirmed successfully")})
+}
 
+func (app *App) UnlockAccount(c *gin.Context) {
+	var (
+		payload           UnlockAccountRequest
+		validationService = app.ServiceContainer.GetService("validationService").(*services.ValidationService)
+		loginProtection   = app.ServiceContainer.GetService("loginProtectionService").(*services.LoginProtectionService)
+	)
 
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		abortWithError(c, errMalformedPayload)
+		return
+	}
+	validate := validationService.GetValidator()
+	if err := validate.StructCtx(c.Request.Context(), payload); err != nil {
+		abortWithError(c, validationService.ValidationFailure(err))
+		return
+	}
+	if err := loginProtection.Unlock(c.Request.Context(), payload.Token, RequestIP(c)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, MessageResponse{Message: app.translate(c, "account unlocked successfully")})
+}
+
+// setAuthCookie hands the token to browsers as an http-only cookie so the frontend never has to
+// store it in javascript accessible storage. It comes with a csrf cookie the frontend can read and
+// has to echo in the X-CSRF-Token header.
+func setAuthCookie(c *gin.Context, token string) {
+	secure := os.Getenv("ENV") != "dev"
+	maxAge := int(services.TokenLifetime.Seconds())
+	c.SetCookie(AuthCookieName, token, maxAge, "/", "", secure, true)
+	csrfToken, err := services.RandomToken()
+	if err != nil {
+		logging.FromContext(c.Request.Context(), nil).Errorf("Could not generate csrf token %s", err)
+		return
+	}
+	c.SetCookie(CSRFCookieName, csrfToken, maxAge, "/", "", secure, false)
+}
+
+// clearAuthCookie ends the browser session set up by setAuthCookie.
+func clearAuthCookie(c *gin.Context) {
+	secure := os.Getenv("ENV") != "dev"
+	c.SetCookie(AuthCookieName, "", -1, "/", "", secure, true)
+	c.SetCookie(CSRFCookieName, "", -1, "/", "", secure, false)
+}
+
+// AdminListUsers returns a page of the active accounts, see services.UserListing for what they can
+// be filtered and sorted by.
+func (app *App) AdminListUsers(c *gin.Context) {
+	directoryService := app.ServiceContainer.GetService("userDirectoryService").(*services.UserDirectoryService)
+	query, err := listQuery(c, services.UserListing)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	page, err := directoryService.List(c.Request.Context(), query)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	users := page.Rows.(models.UserSlice)
+	response := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		response = append(response, newUserResponse(user))
+	}
+	c.JSON(http.StatusOK, page.Envelope(response, c.Request.URL))
+}
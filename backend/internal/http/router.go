@@ -3,35 +3,126 @@ package http
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/ratelimit"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"os"
 )
 
-func GetRouter(app Handlers.App) *gin.Engine {
-	r := gin.Default()
+// GetRouter wires the handlers of app. middleware runs ahead of everything but panic recovery, tests
+// use it to check traffic against the OpenAPI document.
+func GetRouter(app Handlers.App, middleware ...gin.HandlerFunc) *gin.Engine {
+	r := gin.New()
+	// X-Forwarded-For is for ClientIP to judge, gin would believe whatever the client wrote
+	r.ForwardedByClientIP = false
+	r.Use(gin.Recovery())
+	r.Use(middleware...)
+	r.Use(Handlers.ClientIP(Handlers.TrustedProxiesFromEnv()), app.MetricsMiddleware(), TracingMiddleware(), app.RequestLogger(), app.ErrorHandler(), Handlers.AuditContext())
+	r.Use(Handlers.CORS(Handlers.CORSConfigFromEnv()), Handlers.SecurityHeaders(os.Getenv("ENV") != "dev"))
+	r.GET("/healthz", app.Liveness)
+	r.GET("/readyz", app.Readiness)
+	r.GET("/metrics", app.Metrics())
 	api := r.Group("/api")
 	{
 		v1 := api.Group("/v1")
 		{
+			v1.GET("/openapi.json", app.OpenAPISpec)
+			v1.GET("/docs", app.APIDocs)
 
-			v1.POST("/auth", app.AuthenticateUser)
-			v1.POST("/users", app.CreateUser)
+			auth := v1.Group("/auth")
+			{
+				auth.Use(app.RateLimit("auth", ratelimit.PerMinute(10), Handlers.RateLimitByIP))
+				auth.POST("", app.AuthenticateUser)
+				auth.POST("/mfa", app.VerifyMFAChallenge)
+				auth.POST("/magic-link", app.RequestMagicLink)
+				auth.POST("/magic-link/verify", app.VerifyMagicLink)
+				auth.GET("/oidc", app.ListOIDCProviders)
+				auth.GET("/oidc/:provider", app.StartOIDCLogin)
+				auth.GET("/oidc/:provider/callback", app.OIDCCallback)
+				auth.POST("/oidc/:provider/callback", app.OIDCCallback)
+				auth.POST("/unlock", app.UnlockAccount)
+			}
+			users := v1.Group("/users")
+			{
+				// signing up sends mail, keep it tight
+				users.Use(app.RateLimit("signup", ratelimit.PerHour(20), Handlers.RateLimitByIP))
+				users.POST("", app.CreateUser)
+				users.POST("/confirm", app.ConfirmUser)
+				users.POST("/restore", app.RestoreAccount)
+			}
 			// protected end points
 			protected := v1.Group("")
 			{
-				protected.Use(app.AuthMiddleware())
+				protected.Use(
+					// made up credentials cost the client as much as real ones
+					app.RateLimit("api-ip", ratelimit.PerMinute(600), Handlers.RateLimitByIP),
+					app.AuthMiddleware(),
+					Handlers.CSRFProtection(),
+					app.RateLimit("api", ratelimit.PerMinute(300), Handlers.FirstRateLimitKey(
+						Handlers.RateLimitByAPIKey, Handlers.RateLimitByUser, Handlers.RateLimitByIP,
+					)),
+				)
 				protected.GET("/", func(context *gin.Context) {
 					context.JSON(200, "SIENA-API v1")
 				})
 
+				// integrations may read the account and set its preferences with the profile scopes
+				profile := protected.Group("/me")
+				{
+					// the dashboard polls the account, unchanged ones are answered with a 304
+					profile.GET("", Handlers.RequireScope(services.ScopeProfileRead), Handlers.ETag(), app.GetAccount)
+					profile.PUT("/locale", Handlers.RequireScope(services.ScopeProfileWrite), app.SetLocale)
+				}
+				// account management is for the owner in person, not for integrations
+				account := protected.Group("/me", Handlers.DenyAPIKeys())
+				{
+					account.DELETE("", app.DeleteAccount)
+					// building an export is expensive, a few a day is plenty
+					account.POST("/export", app.RateLimit("export", ratelimit.PerHour(3), Handlers.RateLimitByUser), app.ExportAccountData)
+					account.GET("/export", app.DownloadAccountData)
+
+					identities := account.Group("/identities")
+					{
+						identities.GET("", app.ListIdentities)
+						identities.POST("/:provider", app.LinkIdentity)
+						identities.DELETE("/:id", app.UnlinkIdentity)
+					}
+
+					mfa := account.Group("/mfa")
+					{
+						mfa.POST("", app.BeginMFAEnrollment)
+						mfa.POST("/confirm", app.ConfirmMFAEnrollment)
+						mfa.POST("/recovery-codes", app.RegenerateRecoveryCodes)
+						mfa.DELETE("", app.DisableMFA)
+					}
+
+					apiKeys := account.Group("/api-keys")
+					{
+						apiKeys.GET("", app.ListAPIKeys)
+						apiKeys.POST("", app.CreateAPIKey)
+						apiKeys.DELETE("/:key", app.RevokeAPIKey)
+					}
+				}
+
+				admin := protected.Group("/admin")
+				{
+					admin.Use(app.RequireRole(services.MasterRoleSlug))
+					adminRead, adminWrite := Handlers.RequireScope(services.ScopeAdminRead), Handlers.RequireScope(services.ScopeAdminWrite)
+					admin.GET("/log-level", adminRead, app.GetLogLevel)
+					admin.PUT("/log-level", adminWrite, app.SetLogLevel)
+					admin.GET("/users", adminRead, Handlers.ETag(), app.AdminListUsers)
+					admin.DELETE("/users/:id/mfa", adminWrite, app.ResetUserMFA)
+					admin.GET("/users/:id/api-keys", adminRead, app.AdminListAPIKeys)
+					admin.POST("/users/:id/api-keys", adminWrite, app.AdminCreateAPIKey)
+					admin.DELETE("/api-keys/:key", adminWrite, app.AdminRevokeAPIKey)
+					admin.GET("/audit-events", adminRead, app.ListAuditEvents)
+					admin.GET("/audit-events/export", adminRead, app.ExportAuditEvents)
+					admin.GET("/audit-events/verify", adminRead, app.VerifyAuditTrail)
+				}
+
 			}
 
 		}
 	}
 
 	return r
-}
-
-func AuthMiddleWare() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Next()
-	}
 }
\ No newline at end of file
@@ -0,0 +1,95 @@
+package i18n
+
+var french = map[string]string{
+	// errors
+	"An account with this email already exists":                                  "Un compte existe déjà avec cette adresse e-mail",
+	"An account with this email already exists, sign in to link {0}":             "Un compte existe déjà avec cette adresse e-mail, connectez-vous pour lier {0}",
+	"An unexpected error occurred":                                               "Une erreur inattendue s'est produite",
+	"Another account uses this email address now, the account can't be restored": "Un autre compte utilise désormais cette adresse e-mail, le compte ne peut pas être restauré",
+	"Api key not found":                                                       "Clé d'API introuvable",
+	"Authentication required":                                                 "Authentification requise",
+	"Could not sign in with {0}":                                              "Impossible de se connecter avec {0}",
+	"Failed parsing payload":                                                  "Impossible de lire le contenu de la requête",
+	"Identity not found":                                                      "Identité introuvable",
+	"Invalid authentication code":                                             "Code d'authentification invalide",
+	"Invalid email or password":                                               "Adresse e-mail ou mot de passe invalide",
+	"Invalid or expired token":                                                "Jeton invalide ou expiré",
+	"Invalid, expired or revoked api key":                                     "Clé d'API invalide, expirée ou révoquée",
+	"Malformed authorization header":                                          "En-tête d'autorisation mal formé",
+	"Missing or invalid CSRF token":                                           "Jeton CSRF manquant ou invalide",
+	"Profile not found":                                                       "Profil introuvable",
+	"Rate limit exceeded, slow down":                                          "Trop de requêtes, ralentissez",
+	"Start the two-factor enrollment first":                                   "Commencez d'abord l'activation de la double authentification",
+	"The address of this request could not be determined":                     "L'adresse de cette requête n'a pas pu être déterminée",
+	"The confirmation link is invalid":                                        "Le lien de confirmation est invalide",
+	"The export is invalid or has expired":                                    "L'export est invalide ou a expiré",
+	"The login attempt expired, sign in again":                                "La tentative de connexion a expiré, reconnectez-vous",
+	"The login link is invalid or has expired":                                "Le lien de connexion est invalide ou a expiré",
+	"The restore link is invalid or has expired":                              "Le lien de restauration est invalide ou a expiré",
+	"The sign in attempt expired, start again":                                "La tentative de connexion a expiré, recommencez",
+	"The submitted data is invalid":                                           "Les données envoyées sont invalides",
+	"The submitted filters are invalid":                                       "Les filtres envoyés sont invalides",
+	"The unlock link is invalid":                                              "Le lien de déverrouillage est invalide",
+	"This account has been deleted":                                           "Ce compte a été supprimé",
+	"This account has been deleted, use the link we mailed you to restore it": "Ce compte a été supprimé, utilisez le lien que nous vous avons envoyé par e-mail pour le restaurer",
+	"This account has not been confirmed yet":                                 "Ce compte n'a pas encore été confirmé",
+	"This account is already linked to another user":                          "Ce compte est déjà lié à un autre utilisateur",
+	"This account is already scheduled for deletion":                          "La suppression de ce compte est déjà programmée",
+	"This account no longer exists":                                           "Ce compte n'existe plus",
+	"This action can't be performed with an api key":                          "Cette action ne peut pas être effectuée avec une clé d'API",
+	"This api key is missing the {0} scope":                                   "Il manque la portée {0} à cette clé d'API",
+	"This {0} account is already linked to another user":                      "Ce compte {0} est déjà lié à un autre utilisateur",
+	"Too many failed login attempts, try again later":                         "Trop de tentatives de connexion échouées, réessayez plus tard",
+	"Two-factor authentication is already enabled":                            "La double authentification est déjà activée",
+	"Two-factor authentication is not enabled":                                "La double authentification n'est pas activée",
+	"Two-factor authentication required":                                      "Double authentification requise",
+	"Unauthorized":                                                            "Non autorisé",
+	"Unknown identity provider {0}":                                           "Fournisseur d'identité inconnu {0}",
+	"User not found":                                                          "Utilisateur introuvable",
+	"You are not allowed to perform this action":                              "Vous n'êtes pas autorisé à effectuer cette action",
+	"{0} did not share an email address":                                      "{0} n'a pas partagé d'adresse e-mail",
+
+	// fields
+	"cursor is invalid, or was made for another sort":                                            "cursor est invalide, ou a été créé pour un autre tri",
+	"date_of_birth should be formatted as YYYY-MM-DD":                                            "date_of_birth doit être au format AAAA-MM-JJ",
+	"email is already taken":                                                                     "email est déjà utilisé",
+	"email is required":                                                                          "email est obligatoire",
+	"expires_at must be in the future":                                                           "expires_at doit être dans le futur",
+	"level should be one of trace, debug, info, warn, error, fatal or panic":                     "level doit être l'une des valeurs trace, debug, info, warn, error, fatal ou panic",
+	"locale should be one of en, fr, rw":                                                         "locale doit être l'une des valeurs en, fr, rw",
+	"password has appeared in a data breach, choose another one":                                 "password est apparu dans une fuite de données, choisissez-en un autre",
+	"password is too common":                                                                     "password est trop courant",
+	"password is too long, use fewer accented letters or symbols":                                "password est trop long, utilisez moins de lettres accentuées ou de symboles",
+	"password must be at least {0} characters long":                                              "password doit contenir au moins {0} caractères",
+	"password must be at most {0} characters long":                                               "password doit contenir au plus {0} caractères",
+	"password must mix at least {0} of lowercase letters, uppercase letters, digits and symbols": "password doit mélanger au moins {0} types parmi les minuscules, les majuscules, les chiffres et les symboles",
+	"password must not contain your email or name":                                               "password ne doit contenir ni votre e-mail ni votre nom",
+	"scope {0} can not be granted":                                                               "la portée {0} ne peut pas être accordée",
+	"sort should be a comma separated list of {0}, each once":                                    "sort doit être une liste séparée par des virgules de {0}, chacun une seule fois",
+	"token is invalid":                                                                           "token est invalide",
+	"{0} is not a filter, filter by one of {1}":                                                  "{0} n'est pas un filtre, filtrez par l'un de {1}",
+	"{0} is not supported, compare {1} with one of {2}":                                          "{0} n'est pas pris en charge, comparez {1} avec l'un de {2}",
+	"{0} should be a number":                                                                     "{0} doit être un nombre",
+	"{0} should be a positive number":                                                            "{0} doit être un nombre positif",
+	"{0} should be an RFC 3339 timestamp":                                                        "{0} doit être un horodatage RFC 3339",
+	"{0} should be true or false":                                                                "{0} doit valoir true ou false",
+
+	// messages
+	"Authenticate Successfully":                                                     "Connexion réussie",
+	"account confirmed successfully":                                                "compte confirmé avec succès",
+	"account restored successfully":                                                 "compte restauré avec succès",
+	"account unlocked successfully":                                                 "compte déverrouillé avec succès",
+	"api key created, copy it now as it won't be shown again":                       "clé d'API créée, copiez-la maintenant car elle ne sera plus affichée",
+	"api key revoked":                                                               "clé d'API révoquée",
+	"identity unlinked":                                                             "identité dissociée",
+	"if an account exists for this email, a login link is on its way":               "si un compte existe pour cette adresse e-mail, un lien de connexion est en route",
+	"locale updated successfully":                                                   "langue mise à jour avec succès",
+	"recovery codes regenerated":                                                    "codes de récupération régénérés",
+	"scan the provisioning uri and confirm with a code":                             "scannez l'URI de configuration et confirmez avec un code",
+	"two-factor authentication disabled":                                            "double authentification désactivée",
+	"two-factor authentication enabled, store the recovery codes somewhere safe":    "double authentification activée, conservez les codes de récupération en lieu sûr",
+	"two-factor authentication reset":                                               "double authentification réinitialisée",
+	"user created successfully":                                                     "utilisateur créé avec succès",
+	"your account has been deleted, you can restore it from the link we mailed you": "votre compte a été supprimé, vous pouvez le restaurer avec le lien que nous vous avons envoyé par e-mail",
+	"your data export is ready, we mailed you a link to download it":                "l'export de vos données est prêt, nous vous avons envoyé par e-mail un lien pour le télécharger",
+}
@@ -0,0 +1,131 @@
+// Package i18n decides which language the api answers in and holds the translations of its messages.
+// Messages are written in English in the code and translated by looking them up, by their English
+// text, in the catalog of the negotiated locale. Anything a catalog lacks is answered in English.
+// Messages naming something, a provider, a scope or a field, are keyed by their template with {0}
+// standing for it, "Could not sign in with {0}", and filled in once translated.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/fr"
+	"github.com/go-playground/locales/rw"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// Default is the locale of the messages in the code, and of every client that asks for nothing the
+// api speaks.
+const Default = "en"
+
+// Supported lists the locales the api answers in.
+var Supported = []string{Default, "fr", "rw"}
+
+// catalogs hold the translations of api messages by locale, keyed by the English message.
+var catalogs = map[string]map[string]string{
+	"fr": french,
+	"rw": kinyarwanda,
+}
+
+// NewUniversalTranslator returns a translator for every supported locale, loaded with its catalog.
+// Translators are filled in by whoever owns them, the validator adds its own messages, so every
+// owner gets its own.
+func NewUniversalTranslator() (*ut.UniversalTranslator, error) {
+	universal := ut.New(en.New(), en.New(), fr.New(), rw.New())
+	for locale, catalog := range catalogs {
+		translator, _ := universal.GetTranslator(locale)
+		for message, translation := range catalog {
+			if err := translator.Add(message, translation, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return universal, nil
+}
+
+// Translate returns message in the locale of translator, or message itself when its catalog doesn't
+// have it, with params filled in.
+func Translate(translator ut.Translator, message string, params ...string) string {
+	if translated, err := translator.T(message, params...); err == nil && translated != "" {
+		return translated
+	}
+	return Format(message, params...)
+}
+
+// Format fills the placeholders of message in, {0} with the first of params and so on.
+func Format(message string, params ...string) string {
+	for i, param := range params {
+		message = strings.Replace(message, "{"+strconv.Itoa(i)+"}", param, -1)
+	}
+	return message
+}
+
+// IsSupported reports whether the api answers in locale.
+func IsSupported(locale string) bool {
+	for _, supported := range Supported {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the locale to answer in: the preference of the user when the api speaks it, then
+// the languages of the Accept-Language header by weight, then Default. A regional locale the api
+// doesn't know falls back to its language, fr-CA is answered in fr.
+func Negotiate(preference, acceptLanguage string) string {
+	for _, tag := range append([]string{preference}, acceptedLanguages(acceptLanguage)...) {
+		if locale, ok := match(tag); ok {
+			return locale
+		}
+	}
+	return Default
+}
+
+func match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.Replace(strings.TrimSpace(tag), "_", "-", -1))
+	if IsSupported(tag) {
+		return tag, true
+	}
+	if dash := strings.Index(tag, "-"); dash > 0 && IsSupported(tag[:dash]) {
+		return tag[:dash], true
+	}
+	return "", false
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header, the most wanted first.
+// Tags with a weight of 0 are refused by the client and left out.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag    string
+		weight float64
+	}
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		weight := 1.0
+		for _, parameter := range fields[1:] {
+			parameter = strings.TrimSpace(parameter)
+			if strings.HasPrefix(parameter, "q=") {
+				if q, err := strconv.ParseFloat(parameter[2:], 64); err == nil {
+					weight = q
+				}
+			}
+		}
+		if weight > 0 {
+			languages = append(languages, weighted{tag, weight})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool { return languages[i].weight > languages[j].weight })
+	tags := make([]string, len(languages))
+	for i, language := range languages {
+		tags[i] = language.tag
+	}
+	return tags
+}
@@ -0,0 +1,95 @@
+package i18n
+
+var kinyarwanda = map[string]string{
+	// errors
+	"An account with this email already exists":                                  "Hari konti isanzwe ikoresha iyi imeli",
+	"An account with this email already exists, sign in to link {0}":             "Hari konti isanzwe ikoresha iyi imeli, injira kugira ngo uhuze {0}",
+	"An unexpected error occurred":                                               "Habaye ikosa ritunguranye",
+	"Another account uses this email address now, the account can't be restored": "Indi konti isigaye ikoresha iyi imeli, iyi konti ntishobora kugarurwa",
+	"Api key not found":                                                       "Urufunguzo rwa API ntirwabonetse",
+	"Authentication required":                                                 "Ugomba kubanza kwinjira",
+	"Could not sign in with {0}":                                              "Ntibishobotse kwinjira ukoresheje {0}",
+	"Failed parsing payload":                                                  "Ibyoherejwe ntibyashoboye gusomwa",
+	"Identity not found":                                                      "Umwirondoro ntiwabonetse",
+	"Invalid authentication code":                                             "Kode yo kwemeza si yo",
+	"Invalid email or password":                                               "Imeli cyangwa ijambo ry'ibanga si byo",
+	"Invalid or expired token":                                                "Token si yo cyangwa yataye agaciro",
+	"Invalid, expired or revoked api key":                                     "Urufunguzo rwa API si rwo, rwataye agaciro cyangwa rwahagaritswe",
+	"Malformed authorization header":                                          "Umutwe wa Authorization wanditse nabi",
+	"Missing or invalid CSRF token":                                           "Token ya CSRF irabura cyangwa si yo",
+	"Profile not found":                                                       "Porofayili ntiyabonetse",
+	"Rate limit exceeded, slow down":                                          "Wohereje ibisabwa byinshi, gabanya umuvuduko",
+	"Start the two-factor enrollment first":                                   "Banza utangire gushyiraho kwemeza mu ntambwe ebyiri",
+	"The address of this request could not be determined":                     "Aderesi iki cyifuzo giturutseho ntiyabashije kumenyekana",
+	"The confirmation link is invalid":                                        "Umurongo wo kwemeza si wo",
+	"The export is invalid or has expired":                                    "Amakuru wasabye si yo cyangwa yataye agaciro",
+	"The login attempt expired, sign in again":                                "Igerageza ryo kwinjira ryataye agaciro, ongera winjire",
+	"The login link is invalid or has expired":                                "Umurongo wo kwinjira si wo cyangwa wataye agaciro",
+	"The restore link is invalid or has expired":                              "Umurongo wo kugarura konti si wo cyangwa wataye agaciro",
+	"The sign in attempt expired, start again":                                "Igerageza ryo kwinjira ryataye agaciro, ongera utangire",
+	"The submitted data is invalid":                                           "Amakuru yoherejwe si yo",
+	"The submitted filters are invalid":                                       "Ibyo gushunguza byoherejwe si byo",
+	"The unlock link is invalid":                                              "Umurongo wo gufungura konti si wo",
+	"This account has been deleted":                                           "Iyi konti yarasibwe",
+	"This account has been deleted, use the link we mailed you to restore it": "Iyi konti yarasibwe, koresha umurongo twakoherereje kuri imeli kugira ngo uyigarure",
+	"This account has not been confirmed yet":                                 "Iyi konti ntiremezwa",
+	"This account is already linked to another user":                          "Iyi konti isanzwe ihujwe n'undi muntu",
+	"This account is already scheduled for deletion":                          "Iyi konti isanzwe iteganyirijwe gusibwa",
+	"This account no longer exists":                                           "Iyi konti ntikibaho",
+	"This action can't be performed with an api key":                          "Iki gikorwa ntigishobora gukorwa hakoreshejwe urufunguzo rwa API",
+	"This api key is missing the {0} scope":                                   "Uru rufunguzo rwa API ntirufite uburenganzira bwa {0}",
+	"This {0} account is already linked to another user":                      "Iyi konti ya {0} isanzwe ihujwe n'undi muntu",
+	"Too many failed login attempts, try again later":                         "Wagerageje kwinjira kenshi bikanga, ongera ugerageze nyuma",
+	"Two-factor authentication is already enabled":                            "Kwemeza mu ntambwe ebyiri bisanzwe bikora",
+	"Two-factor authentication is not enabled":                                "Kwemeza mu ntambwe ebyiri ntibikora",
+	"Two-factor authentication required":                                      "Kwemeza mu ntambwe ebyiri birakenewe",
+	"Unauthorized":                                                            "Ntubyemerewe",
+	"Unknown identity provider {0}":                                           "Utanga umwirondoro {0} ntazwi",
+	"User not found":                                                          "Ukoresha ntiyabonetse",
+	"You are not allowed to perform this action":                              "Ntiwemerewe gukora iki gikorwa",
+	"{0} did not share an email address":                                      "{0} ntiyatanze imeli",
+
+	// fields
+	"cursor is invalid, or was made for another sort":                                            "cursor si yo, cyangwa yakorewe ubundi buryo bwo gutondeka",
+	"date_of_birth should be formatted as YYYY-MM-DD":                                            "date_of_birth igomba kwandikwa nka YYYY-MM-DD",
+	"email is already taken":                                                                     "email yamaze gukoreshwa",
+	"email is required":                                                                          "email ni ngombwa",
+	"expires_at must be in the future":                                                           "expires_at igomba kuba itaragera",
+	"level should be one of trace, debug, info, warn, error, fatal or panic":                     "level igomba kuba imwe muri trace, debug, info, warn, error, fatal cyangwa panic",
+	"locale should be one of en, fr, rw":                                                         "locale igomba kuba imwe muri en, fr, rw",
+	"password has appeared in a data breach, choose another one":                                 "password yagaragaye mu makuru yibwe, hitamo irindi",
+	"password is too common":                                                                     "password irakoreshwa cyane",
+	"password is too long, use fewer accented letters or symbols":                                "password ni ndende cyane, koresha inyuguti zifite utumenyetso cyangwa ibimenyetso bike",
+	"password must be at least {0} characters long":                                              "password igomba kugira nibura inyuguti {0}",
+	"password must be at most {0} characters long":                                               "password ntigomba kurenza inyuguti {0}",
+	"password must mix at least {0} of lowercase letters, uppercase letters, digits and symbols": "password igomba kuvanga nibura amoko {0} mu nyuguti nto, inyuguti nkuru, imibare n'ibimenyetso",
+	"password must not contain your email or name":                                               "password ntigomba kubamo imeli cyangwa izina ryawe",
+	"scope {0} can not be granted":                                                               "uburenganzira {0} ntibushobora gutangwa",
+	"sort should be a comma separated list of {0}, each once":                                    "sort igomba kuba urutonde rwa {0} rutandukanyijwe n'akitso, buri kimwe rimwe",
+	"token is invalid":                                                                           "token si yo",
+	"{0} is not a filter, filter by one of {1}":                                                  "{0} si uburyo bwo gushunguza, shunguza ukoresheje kimwe muri {1}",
+	"{0} is not supported, compare {1} with one of {2}":                                          "{0} ntishyigikiwe, gereranya {1} ukoresheje kimwe muri {2}",
+	"{0} should be a number":                                                                     "{0} igomba kuba umubare",
+	"{0} should be a positive number":                                                            "{0} igomba kuba umubare utari munsi ya zeru",
+	"{0} should be an RFC 3339 timestamp":                                                        "{0} igomba kuba igihe cyanditse nka RFC 3339",
+	"{0} should be true or false":                                                                "{0} igomba kuba true cyangwa false",
+
+	// messages
+	"Authenticate Successfully":                                                     "Winjiye neza",
+	"account confirmed successfully":                                                "konti yemejwe neza",
+	"account restored successfully":                                                 "konti yagaruwe neza",
+	"account unlocked successfully":                                                 "konti yafunguwe neza",
+	"api key created, copy it now as it won't be shown again":                       "urufunguzo rwa API rwashyizweho, rwandukure ubu kuko rutazongera kugaragara",
+	"api key revoked":                                                               "urufunguzo rwa API rwahagaritswe",
+	"identity unlinked":                                                             "umwirondoro watandukanyijwe",
+	"if an account exists for this email, a login link is on its way":               "niba hari konti ikoresha iyi imeli, umurongo wo kwinjira uri mu nzira",
+	"locale updated successfully":                                                   "ururimi rwahinduwe neza",
+	"recovery codes regenerated":                                                    "kode zo kugarura konti zongeye gukorwa",
+	"scan the provisioning uri and confirm with a code":                             "sikana URI yo gushyiraho hanyuma wemeze ukoresheje kode",
+	"two-factor authentication disabled":                                            "kwemeza mu ntambwe ebyiri byahagaritswe",
+	"two-factor authentication enabled, store the recovery codes somewhere safe":    "kwemeza mu ntambwe ebyiri byatangijwe, bika kode zo kugarura konti ahantu hizewe",
+	"two-factor authentication reset":                                               "kwemeza mu ntambwe ebyiri byasubiwemo",
+	"user created successfully":                                                     "ukoresha yashyizweho neza",
+	"your account has been deleted, you can restore it from the link we mailed you": "konti yawe yasibwe, ushobora kuyigarura ukoresheje umurongo twakoherereje kuri imeli",
+	"your data export is ready, we mailed you a link to download it":                "amakuru yawe yiteguye, twakoherereje kuri imeli umurongo wo kuyamanura",
+}
@@ -0,0 +1,401 @@
+// Package listing turns the query parameters of list endpoints into sqlboiler query mods. Clients
+// filter, sort and page through a resource by the fields its Resource whitelists:
+//
+//	?filter[email][prefix]=ada&filter[created_at][gte]=2020-01-01T00:00:00Z&sort=-created_at&limit=50
+//
+// Pages are keyset paginated, the cursor of the next page holds the sort values of the last row
+// rather than an offset, so pages stay stable while rows are added and deep pages stay cheap.
+package listing
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/i18n"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Type is the type of the values of a field, filter values are parsed as it.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+	// Time values are RFC 3339 timestamps.
+	Time
+)
+
+// Operator compares a field to the value of a filter.
+type Operator string
+
+const (
+	Eq  Operator = "eq"
+	Ne  Operator = "ne"
+	Lt  Operator = "lt"
+	Lte Operator = "lte"
+	Gt  Operator = "gt"
+	Gte Operator = "gte"
+	// In matches any of a comma separated list of values.
+	In Operator = "in"
+	// Prefix matches strings starting with the value.
+	Prefix Operator = "prefix"
+)
+
+// operators are what each type can be filtered with, the first is used when a filter names none.
+var operators = map[Type][]Operator{
+	String: {Eq, Ne, In, Prefix},
+	Int:    {Eq, Ne, Lt, Lte, Gt, Gte, In},
+	Bool:   {Eq},
+	Time:   {Eq, Lt, Lte, Gt, Gte},
+}
+
+var comparisons = map[Operator]string{Eq: "=", Ne: "<>", Lt: "<", Lte: "<=", Gt: ">", Gte: ">="}
+
+// Field is a column clients may see a resource through.
+type Field struct {
+	Column     string
+	Type       Type
+	Filterable bool
+	// Sortable fields must be NOT NULL, keyset pagination can't compare nulls.
+	Sortable bool
+}
+
+// Resource whitelists the fields of a list endpoint, keyed by their name in query parameters.
+type Resource struct {
+	// Table qualifies columns, so queries can join other tables.
+	Table  string
+	Fields map[string]Field
+	// Key names a unique sortable field. It ends every sort, so no two rows tie and every row has
+	// its place between pages.
+	Key string
+	// DefaultSort is used when the client asks for none, e.g. "-created_at".
+	DefaultSort string
+	// DefaultLimit is the page size when the client asks for none, MaxLimit caps what it may ask for.
+	DefaultLimit int
+	MaxLimit     int
+}
+
+func (r *Resource) column(name string) string {
+	if r.Table == "" {
+		return r.Fields[name].Column
+	}
+	return r.Table + "." + r.Fields[name].Column
+}
+
+// SortKey orders rows by a field.
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// Filter keeps the rows whose field compares to one of Values.
+type Filter struct {
+	Field    string
+	Operator Operator
+	Values   []interface{}
+}
+
+// Query is a validated list request, see Parse.
+type Query struct {
+	resource *Resource
+	Limit    int
+	Sort     []SortKey
+	Filters  []Filter
+	cursor   *cursor
+}
+
+// ParamError is a query parameter Parse refused.
+type ParamError struct {
+	Param   string
+	Message string
+	// Template is Message with what it names left as placeholders, {0} for the first of Params, the
+	// key its translations are looked up by.
+	Template string
+	Params   []string
+}
+
+func paramError(param, template string, params ...string) ParamError {
+	return ParamError{Param: param, Message: i18n.Format(template, params...), Template: template, Params: params}
+}
+
+// ParamErrors is every parameter Parse refused.
+type ParamErrors []ParamError
+
+func (e ParamErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "invalid list parameters: " + strings.Join(messages, ", ")
+}
+
+// Parse reads the limit, sort, filter[...] and cursor parameters of a list request against
+// resource. Anything it refuses is reported as ParamErrors, other parameters are ignored.
+func Parse(resource *Resource, params url.Values) (*Query, error) {
+	query := &Query{resource: resource, Limit: resource.DefaultLimit}
+	var errs ParamErrors
+
+	if raw := params.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			errs = append(errs, paramError("limit", "{0} should be a positive number", "limit"))
+		} else {
+			query.Limit = limit
+		}
+	}
+	if query.Limit > resource.MaxLimit {
+		query.Limit = resource.MaxLimit
+	}
+
+	order := params.Get("sort")
+	if order == "" {
+		order = resource.DefaultSort
+	}
+	var err *ParamError
+	if query.Sort, err = parseSort(resource, order); err != nil {
+		errs = append(errs, *err)
+	}
+
+	filters, filterErrs := parseFilters(resource, params)
+	query.Filters = filters
+	errs = append(errs, filterErrs...)
+
+	if raw := params.Get("cursor"); raw != "" && err == nil {
+		cursor, ok := decodeCursor(raw)
+		if ok {
+			ok = cursor.Sort == query.sortParam() && len(cursor.Values) == len(query.Sort)
+		}
+		if ok {
+			ok = cursor.parseValues(resource, query.Sort)
+		}
+		if !ok {
+			errs = append(errs, paramError("cursor", "cursor is invalid, or was made for another sort"))
+		} else {
+			query.cursor = cursor
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return query, nil
+}
+
+func parseSort(resource *Resource, order string) ([]SortKey, *ParamError) {
+	var keys []SortKey
+	seen := map[string]bool{}
+	for _, name := range strings.Split(order, ",") {
+		name = strings.TrimSpace(name)
+		key := SortKey{Field: strings.TrimPrefix(name, "-"), Descending: strings.HasPrefix(name, "-")}
+		if key.Field == "" {
+			continue
+		}
+		if !resource.Fields[key.Field].Sortable || seen[key.Field] {
+			err := paramError("sort", "sort should be a comma separated list of {0}, each once",
+				strings.Join(resource.names(func(f Field) bool { return f.Sortable }), ", "))
+			return nil, &err
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	if !seen[resource.Key] {
+		keys = append(keys, SortKey{Field: resource.Key})
+	}
+	return keys, nil
+}
+
+func parseFilters(resource *Resource, params url.Values) ([]Filter, ParamErrors) {
+	var (
+		filters []Filter
+		errs    ParamErrors
+		names   []string
+	)
+	for param := range params {
+		if strings.HasPrefix(param, "filter[") {
+			names = append(names, param)
+		}
+	}
+	sort.Strings(names)
+	for _, param := range names {
+		values := params[param]
+		name, operator, ok := filterParam(param)
+		field, known := resource.Fields[name]
+		if !ok || !known || !field.Filterable {
+			errs = append(errs, paramError(param, "{0} is not a filter, filter by one of {1}",
+				param, strings.Join(resource.names(func(f Field) bool { return f.Filterable }), ", ")))
+			continue
+		}
+		supported := operators[field.Type]
+		if operator == "" {
+			operator = supported[0]
+		}
+		if !hasOperator(supported, operator) {
+			errs = append(errs, paramError(param, "{0} is not supported, compare {1} with one of {2}", param, name, joinOperators(supported)))
+			continue
+		}
+		for _, value := range values {
+			raw := []string{value}
+			if operator == In {
+				raw = strings.Split(value, ",")
+			}
+			filter := Filter{Field: name, Operator: operator}
+			for _, r := range raw {
+				parsed, err := parseValue(field.Type, strings.TrimSpace(r))
+				if err != nil {
+					errs = append(errs, paramError(param, "{0} should be "+err.Error(), param))
+					filter.Values = nil
+					break
+				}
+				filter.Values = append(filter.Values, parsed)
+			}
+			if filter.Values != nil {
+				filters = append(filters, filter)
+			}
+		}
+	}
+	return filters, errs
+}
+
+// filterParam splits filter[name] and filter[name][operator].
+func filterParam(param string) (string, Operator, bool) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(param, "filter["), "]"), "][")
+	switch {
+	case len(parts) == 1 && parts[0] != "" && !strings.ContainsAny(parts[0], "[]"):
+		return parts[0], "", true
+	case len(parts) == 2 && parts[1] != "":
+		return parts[0], Operator(parts[1]), true
+	}
+	return "", "", false
+}
+
+func hasOperator(operators []Operator, operator Operator) bool {
+	for _, supported := range operators {
+		if supported == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func joinOperators(operators []Operator) string {
+	names := make([]string, len(operators))
+	for i, operator := range operators {
+		names[i] = string(operator)
+	}
+	return strings.Join(names, ", ")
+}
+
+// names lists the fields of r matching keep, sorted, for error messages.
+func (r *Resource) names(keep func(Field) bool) []string {
+	var names []string
+	for name, field := range r.Fields {
+		if keep(field) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// typeErrors describe the values each type expects.
+var typeErrors = map[Type]error{
+	Int:  errors.New("a number"),
+	Bool: errors.New("true or false"),
+	Time: errors.New("an RFC 3339 timestamp"),
+}
+
+func parseValue(t Type, raw string) (interface{}, error) {
+	var (
+		value interface{}
+		err   error
+	)
+	switch t {
+	case Int:
+		value, err = strconv.ParseInt(raw, 10, 64)
+	case Bool:
+		value, err = strconv.ParseBool(raw)
+	case Time:
+		value, err = time.Parse(time.RFC3339Nano, raw)
+	default:
+		value = raw
+	}
+	if err != nil {
+		return nil, typeErrors[t]
+	}
+	return value, nil
+}
+
+// sortParam is the canonical form of the sort, cursors are only good for the sort they were made for.
+func (q *Query) sortParam() string {
+	names := make([]string, len(q.Sort))
+	for i, key := range q.Sort {
+		names[i] = key.Field
+		if key.Descending {
+			names[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// QueryMods filters, orders and limits a query on the resource to the requested page. One row more
+// than the limit is fetched, it tells Page whether there is a page after this one.
+func (q *Query) QueryMods() []qm.QueryMod {
+	var mods []qm.QueryMod
+	for _, filter := range q.Filters {
+		column := q.resource.column(filter.Field)
+		switch filter.Operator {
+		case In:
+			mods = append(mods, qm.WhereIn(column+" IN ?", filter.Values...))
+		case Prefix:
+			mods = append(mods, qm.Where(column+" LIKE ?", EscapeLike(filter.Values[0].(string))+"%"))
+		default:
+			mods = append(mods, qm.Where(column+" "+comparisons[filter.Operator]+" ?", filter.Values[0]))
+		}
+	}
+	backwards := q.cursor != nil && q.cursor.Backwards
+	if q.cursor != nil {
+		mods = append(mods, q.keyset())
+	}
+	order := make([]string, len(q.Sort))
+	for i, key := range q.Sort {
+		order[i] = q.resource.column(key.Field)
+		if key.Descending != backwards {
+			order[i] += " DESC"
+		}
+	}
+	return append(mods, qm.OrderBy(strings.Join(order, ", ")), qm.Limit(q.Limit+1))
+}
+
+// keyset keeps the rows after the cursor in the order of the sort, or before it when paging
+// backwards: (a > ?) OR (a = ? AND b > ?) OR ...
+func (q *Query) keyset() qm.QueryMod {
+	var (
+		terms []string
+		args  []interface{}
+		equal []string
+	)
+	for i, key := range q.Sort {
+		column := q.resource.column(key.Field)
+		comparison := ">"
+		if key.Descending != q.cursor.Backwards {
+			comparison = "<"
+		}
+		terms = append(terms, "("+strings.Join(append(equal, column+" "+comparison+" ?"), " AND ")+")")
+		args = append(args, q.cursor.values[:i+1]...)
+		equal = append(equal, column+" = ?")
+	}
+	// sqlboiler puts every where clause in parentheses, the ORs don't leak into other filters
+	return qm.Where(strings.Join(terms, " OR "), args...)
+}
+
+// EscapeLike makes value match literally in a LIKE pattern.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
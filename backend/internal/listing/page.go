@@ -0,0 +1,194 @@
+package listing
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// cursor marks where a page starts: after the row with Values, in the order of Sort, or before it
+// when paging Backwards. Clients get it base64 encoded and are not meant to look inside.
+type cursor struct {
+	Sort      string   `json:"s"`
+	Values    []string `json:"v"`
+	Backwards bool     `json:"b,omitempty"`
+	// values are Values parsed as the types of their fields
+	values []interface{}
+}
+
+func (c *cursor) encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(encoded string) (*cursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+	var c cursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return nil, false
+	}
+	return &c, true
+}
+
+func (c *cursor) parseValues(resource *Resource, keys []SortKey) bool {
+	c.values = make([]interface{}, len(keys))
+	for i, key := range keys {
+		value, err := parseValue(resource.Fields[key.Field].Type, c.Values[i])
+		if err != nil {
+			return false
+		}
+		c.values[i] = value
+	}
+	return true
+}
+
+// Page is a page of a list with the cursors of the pages around it.
+type Page struct {
+	// Rows is the slice given to Query.Page, cut to the page, in the order of the sort.
+	Rows interface{}
+	// Next and Prev are the cursors of the pages after and before this one, empty when there is none.
+	Next string
+	Prev string
+}
+
+// Page cuts rows, the slice of models a query built with QueryMods returned, to the requested page
+// and makes the cursors of its neighbours from its first and last rows.
+func (q *Query) Page(rows interface{}) (*Page, error) {
+	slice := reflect.ValueOf(rows)
+	if slice.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("listing: rows should be a slice, not %T", rows)
+	}
+	more := slice.Len() > q.Limit
+	if more {
+		slice = slice.Slice(0, q.Limit)
+	}
+	backwards := q.cursor != nil && q.cursor.Backwards
+	if backwards {
+		slice = reversed(slice)
+	}
+	page := &Page{Rows: slice.Interface()}
+	if slice.Len() == 0 {
+		return page, nil
+	}
+
+	// a page reached through a cursor has rows on the side it came from
+	hasNext, hasPrev := more, q.cursor != nil
+	if backwards {
+		hasNext, hasPrev = true, more
+	}
+	var err error
+	if hasNext {
+		if page.Next, err = q.cursorAt(slice.Index(slice.Len()-1), false); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = q.cursorAt(slice.Index(0), true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+func reversed(slice reflect.Value) reflect.Value {
+	reversed := reflect.MakeSlice(slice.Type(), slice.Len(), slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		reversed.Index(slice.Len() - 1 - i).Set(slice.Index(i))
+	}
+	return reversed
+}
+
+// cursorAt is the cursor of the page after row, or before it when backwards.
+func (q *Query) cursorAt(row reflect.Value, backwards bool) (string, error) {
+	c := cursor{Sort: q.sortParam(), Backwards: backwards}
+	for _, key := range q.Sort {
+		value, err := columnValue(row, q.resource.Fields[key.Field].Column)
+		if err != nil {
+			return "", err
+		}
+		c.Values = append(c.Values, value)
+	}
+	return c.encode(), nil
+}
+
+// columnValue formats the field of a model mapped to column by its boil tag, the way parseValue
+// reads it back.
+func columnValue(row reflect.Value, column string) (string, error) {
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		row = row.Elem()
+	}
+	if row.Kind() != reflect.Struct {
+		return "", fmt.Errorf("listing: rows should be structs, not %s", row.Type())
+	}
+	for i := 0; i < row.NumField(); i++ {
+		if strings.Split(row.Type().Field(i).Tag.Get("boil"), ",")[0] != column {
+			continue
+		}
+		value := row.Field(i).Interface()
+		if valuer, ok := value.(driver.Valuer); ok {
+			var err error
+			if value, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+		switch value := value.(type) {
+		case time.Time:
+			return value.Format(time.RFC3339Nano), nil
+		case string:
+			return value, nil
+		case int, int64, int32, bool:
+			return fmt.Sprint(value), nil
+		default:
+			return "", fmt.Errorf("listing: can't page by %s, a %T", column, value)
+		}
+	}
+	return "", fmt.Errorf("listing: %s has no %s column", row.Type(), column)
+}
+
+// Links point to a page and its neighbours, they are relative to the host of the api.
+type Links struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// Links returns the links of the page, self is the url it was requested with.
+func (p *Page) Links(self *url.URL) Links {
+	link := func(cursor string) string {
+		query := self.Query()
+		query.Del("cursor")
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		link := url.URL{Path: self.Path, RawQuery: query.Encode()}
+		return link.String()
+	}
+	links := Links{Self: link(self.Query().Get("cursor"))}
+	if p.Next != "" {
+		links.Next = link(p.Next)
+	}
+	if p.Prev != "" {
+		links.Prev = link(p.Prev)
+	}
+	return links
+}
+
+// Envelope is the body every list endpoint answers with, Data holds the rows of the page as the
+// endpoint renders them.
+type Envelope struct {
+	Data  interface{} `json:"data"`
+	Links Links       `json:"links"`
+}
+
+// Envelope wraps data, the rendered rows of the page, with its links.
+func (p *Page) Envelope(data interface{}, self *url.URL) Envelope {
+	return Envelope{Data: data, Links: p.Links(self)}
+}
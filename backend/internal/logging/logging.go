@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+type contextKey struct{}
+
+const (
+	defaultMaxSizeMB  = 100
+	defaultMaxAgeDays = 14
+	defaultMaxBackups = 10
+)
+
+// RotationConfig controls when the log file is rotated and how long old files are retained.
+type RotationConfig struct {
+	Directory  string
+	MaxSizeMB  int
+	MaxAgeDays int
+	MaxBackups int
+}
+
+// RotationConfigFromEnv reads LOG_MAX_SIZE_MB, LOG_MAX_AGE_DAYS and LOG_MAX_BACKUPS,
+// falling back to sane defaults.
+func RotationConfigFromEnv(directory string) RotationConfig {
+	return RotationConfig{
+		Directory:  directory,
+		MaxSizeMB:  intFromEnv("LOG_MAX_SIZE_MB", defaultMaxSizeMB),
+		MaxAgeDays: intFromEnv("LOG_MAX_AGE_DAYS", defaultMaxAgeDays),
+		MaxBackups: intFromEnv("LOG_MAX_BACKUPS", defaultMaxBackups),
+	}
+}
+
+// NewLogger builds a JSON logger that writes to stderr and to a rotating file.
+// The returned closer flushes and closes the file.
+func NewLogger(config RotationConfig, level string) (*logrus.Logger, io.Closer, error) {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	if err := SetLevel(logger, level); err != nil {
+		return nil, nil, err
+	}
+	if err := os.MkdirAll(config.Directory, 0755); err != nil {
+		return nil, nil, err
+	}
+	file := &lumberjack.Logger{
+		Filename:   filepath.Join(config.Directory, "siena.log"),
+		MaxSize:    config.MaxSizeMB,
+		MaxAge:     config.MaxAgeDays,
+		MaxBackups: config.MaxBackups,
+		Compress:   true,
+	}
+	logger.SetOutput(io.MultiWriter(os.Stderr, file))
+	return logger, file, nil
+}
+
+// SetLevel changes the level of logger at runtime, an empty level keeps the current one.
+func SetLevel(logger *logrus.Logger, level string) error {
+	if level == "" {
+		return nil
+	}
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(parsed)
+	return nil
+}
+
+// WithLogger stores a request scoped logger in ctx.
+func WithLogger(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the logger stored in ctx by WithLogger. When there is none, fallback is used
+// and the entry is still decorated with the trace of ctx if any.
+func FromContext(ctx context.Context, fallback *logrus.Logger) *logrus.Entry {
+	if ctx != nil {
+		if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+			return entry
+		}
+	}
+	if fallback == nil {
+		fallback = logrus.StandardLogger()
+	}
+	entry := logrus.NewEntry(fallback)
+	if ctx == nil {
+		return entry
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		entry = entry.WithField("trace_id", spanContext.TraceID().String())
+	}
+	return entry
+}
+
+func intFromEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
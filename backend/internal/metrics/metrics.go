@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const namespace = "siena"
+
+// Registry holds every collector exposed on /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests handled, partitioned by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests, partitioned by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	MailsSentTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mailer",
+		Name:      "mails_total",
+		Help:      "Number of mails handed to the mail transport, partitioned by message type and result.",
+	}, []string{"type", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		MailsSentTotal,
+	)
+}
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB) {
+	Registry.MustRegister(&dbStatsCollector{db: db})
+}
+
+// RegisterMailerLogStatus exposes the number of mailer_logs rows in each status.
+func RegisterMailerLogStatus(ctx context.Context, db *sql.DB, logger *logrus.Logger) {
+	Registry.MustRegister(&mailerLogStatusCollector{db: db, ctx: ctx, logger: logger})
+}
+
+// RegisterNSQConsumer exposes the message counters of an nsq consumer under the given topic label.
+func RegisterNSQConsumer(topic string, consumer *nsq.Consumer) error {
+	return Registry.Register(&nsqConsumerCollector{topic: topic, consumer: consumer})
+}
+
+var (
+	dbOpenConnectionsDesc = prometheus.NewDesc(namespace+"_db_open_connections",
+		"Number of established connections, both in use and idle.", nil, nil)
+	dbInUseDesc = prometheus.NewDesc(namespace+"_db_in_use_connections",
+		"Number of connections currently in use.", nil, nil)
+	dbIdleDesc = prometheus.NewDesc(namespace+"_db_idle_connections",
+		"Number of idle connections.", nil, nil)
+	dbWaitCountDesc = prometheus.NewDesc(namespace+"_db_wait_count_total",
+		"Total number of connections waited for.", nil, nil)
+	dbWaitDurationDesc = prometheus.NewDesc(namespace+"_db_wait_duration_seconds_total",
+		"Total time blocked waiting for a new connection.", nil, nil)
+)
+
+type dbStatsCollector struct {
+	db *sql.DB
+}
+
+func (c *dbStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbOpenConnectionsDesc
+	ch <- dbInUseDesc
+	ch <- dbIdleDesc
+	ch <- dbWaitCountDesc
+	ch <- dbWaitDurationDesc
+}
+
+func (c *dbStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(dbOpenConnectionsDesc, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(dbInUseDesc, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(dbIdleDesc, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(dbWaitCountDesc, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(dbWaitDurationDesc, prometheus.CounterValue, stats.WaitDuration.Seconds())
+}
+
+var mailerLogStatusDesc = prometheus.NewDesc(namespace+"_mailer_logs",
+	"Number of mailer_logs rows, partitioned by status.", []string{"status"}, nil)
+
+type mailerLogStatusCollector struct {
+	db     *sql.DB
+	ctx    context.Context
+	logger *logrus.Logger
+}
+
+func (c *mailerLogStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- mailerLogStatusDesc
+}
+
+func (c *mailerLogStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(c.ctx, 2*time.Second)
+	defer cancel()
+	rows, err := c.db.QueryContext(ctx, "SELECT status, COUNT(*) FROM mailer_logs GROUP BY status")
+	if err != nil {
+		c.logger.Errorf("Could not collect mailer log metrics %s", err)
+		return
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var (
+			status string
+			count  float64
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			c.logger.Errorf("Could not collect mailer log metrics %s", err)
+			return
+		}
+		ch <- prometheus.MustNewConstMetric(mailerLogStatusDesc, prometheus.GaugeValue, count, status)
+	}
+}
+
+var (
+	nsqReceivedDesc = prometheus.NewDesc(namespace+"_nsq_messages_received_total",
+		"Number of messages received by the consumer.", []string{"topic"}, nil)
+	nsqFinishedDesc = prometheus.NewDesc(namespace+"_nsq_messages_finished_total",
+		"Number of messages finished by the consumer.", []string{"topic"}, nil)
+	nsqRequeuedDesc = prometheus.NewDesc(namespace+"_nsq_messages_requeued_total",
+		"Number of messages requeued by the consumer.", []string{"topic"}, nil)
+	nsqInFlightDesc = prometheus.NewDesc(namespace+"_nsq_messages_in_flight",
+		"Number of messages received but neither finished nor requeued yet.", []string{"topic"}, nil)
+	nsqConnectionsDesc = prometheus.NewDesc(namespace+"_nsq_connections",
+		"Number of nsqd connections held by the consumer.", []string{"topic"}, nil)
+)
+
+type nsqConsumerCollector struct {
+	topic    string
+	consumer *nsq.Consumer
+}
+
+func (c *nsqConsumerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nsqReceivedDesc
+	ch <- nsqFinishedDesc
+	ch <- nsqRequeuedDesc
+	ch <- nsqInFlightDesc
+	ch <- nsqConnectionsDesc
+}
+
+func (c *nsqConsumerCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.consumer.Stats()
+	inFlight := float64(stats.MessagesReceived) - float64(stats.MessagesFinished) - float64(stats.MessagesRequeued)
+	if inFlight < 0 {
+		inFlight = 0
+	}
+	ch <- prometheus.MustNewConstMetric(nsqReceivedDesc, prometheus.CounterValue, float64(stats.MessagesReceived), c.topic)
+	ch <- prometheus.MustNewConstMetric(nsqFinishedDesc, prometheus.CounterValue, float64(stats.MessagesFinished), c.topic)
+	ch <- prometheus.MustNewConstMetric(nsqRequeuedDesc, prometheus.CounterValue, float64(stats.MessagesRequeued), c.topic)
+	ch <- prometheus.MustNewConstMetric(nsqInFlightDesc, prometheus.GaugeValue, inFlight, c.topic)
+	ch <- prometheus.MustNewConstMetric(nsqConnectionsDesc, prometheus.GaugeValue, float64(stats.Connections), c.topic)
+}
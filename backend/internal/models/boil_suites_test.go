@@ -12,89 +12,232 @@ import "testing"
 // It does NOT run each operation group in parallel.
 // Separating the tests thusly grants avoidance of Postgres deadlocks.
 func TestParent(t *testing.T) {
+	t.Run("APIKeys", testAPIKeys)
+	t.Run("AccountDeletions", testAccountDeletions)
+	t.Run("AccountLockouts", testAccountLockouts)
+	t.Run("AuditEvents", testAuditEvents)
+	t.Run("DataExports", testDataExports)
+	t.Run("LoginAttempts", testLoginAttempts)
+	t.Run("MagicLinks", testMagicLinks)
 	t.Run("MailerLogs", testMailerLogs)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodes)
+	t.Run("OidcLoginStates", testOidcLoginStates)
 	t.Run("Profiles", testProfiles)
 	t.Run("Roles", testRoles)
+	t.Run("UserIdentities", testUserIdentities)
+	t.Run("UserMfas", testUserMfas)
 	t.Run("Users", testUsers)
 }
 
 func TestDelete(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysDelete)
+	t.Run("AccountDeletions", testAccountDeletionsDelete)
+	t.Run("AccountLockouts", testAccountLockoutsDelete)
+	t.Run("AuditEvents", testAuditEventsDelete)
+	t.Run("DataExports", testDataExportsDelete)
+	t.Run("LoginAttempts", testLoginAttemptsDelete)
+	t.Run("MagicLinks", testMagicLinksDelete)
 	t.Run("MailerLogs", testMailerLogsDelete)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesDelete)
+	t.Run("OidcLoginStates", testOidcLoginStatesDelete)
 	t.Run("Profiles", testProfilesDelete)
 	t.Run("Roles", testRolesDelete)
+	t.Run("UserIdentities", testUserIdentitiesDelete)
+	t.Run("UserMfas", testUserMfasDelete)
 	t.Run("Users", testUsersDelete)
 }
 
 func TestQueryDeleteAll(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysQueryDeleteAll)
+	t.Run("AccountDeletions", testAccountDeletionsQueryDeleteAll)
+	t.Run("AccountLockouts", testAccountLockoutsQueryDeleteAll)
+	t.Run("AuditEvents", testAuditEventsQueryDeleteAll)
+	t.Run("DataExports", testDataExportsQueryDeleteAll)
+	t.Run("LoginAttempts", testLoginAttemptsQueryDeleteAll)
+	t.Run("MagicLinks", testMagicLinksQueryDeleteAll)
 	t.Run("MailerLogs", testMailerLogsQueryDeleteAll)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesQueryDeleteAll)
+	t.Run("OidcLoginStates", testOidcLoginStatesQueryDeleteAll)
 	t.Run("Profiles", testProfilesQueryDeleteAll)
 	t.Run("Roles", testRolesQueryDeleteAll)
+	t.Run("UserIdentities", testUserIdentitiesQueryDeleteAll)
+	t.Run("UserMfas", testUserMfasQueryDeleteAll)
 	t.Run("Users", testUsersQueryDeleteAll)
 }
 
 func TestSliceDeleteAll(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysSliceDeleteAll)
+	t.Run("AccountDeletions", testAccountDeletionsSliceDeleteAll)
+	t.Run("AccountLockouts", testAccountLockoutsSliceDeleteAll)
+	t.Run("AuditEvents", testAuditEventsSliceDeleteAll)
+	t.Run("DataExports", testDataExportsSliceDeleteAll)
+	t.Run("LoginAttempts", testLoginAttemptsSliceDeleteAll)
+	t.Run("MagicLinks", testMagicLinksSliceDeleteAll)
 	t.Run("MailerLogs", testMailerLogsSliceDeleteAll)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesSliceDeleteAll)
+	t.Run("OidcLoginStates", testOidcLoginStatesSliceDeleteAll)
 	t.Run("Profiles", testProfilesSliceDeleteAll)
 	t.Run("Roles", testRolesSliceDeleteAll)
+	t.Run("UserIdentities", testUserIdentitiesSliceDeleteAll)
+	t.Run("UserMfas", testUserMfasSliceDeleteAll)
 	t.Run("Users", testUsersSliceDeleteAll)
 }
 
 func TestExists(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysExists)
+	t.Run("AccountDeletions", testAccountDeletionsExists)
+	t.Run("AccountLockouts", testAccountLockoutsExists)
+	t.Run("AuditEvents", testAuditEventsExists)
+	t.Run("DataExports", testDataExportsExists)
+	t.Run("LoginAttempts", testLoginAttemptsExists)
+	t.Run("MagicLinks", testMagicLinksExists)
 	t.Run("MailerLogs", testMailerLogsExists)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesExists)
+	t.Run("OidcLoginStates", testOidcLoginStatesExists)
 	t.Run("Profiles", testProfilesExists)
 	t.Run("Roles", testRolesExists)
+	t.Run("UserIdentities", testUserIdentitiesExists)
+	t.Run("UserMfas", testUserMfasExists)
 	t.Run("Users", testUsersExists)
 }
 
 func TestFind(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysFind)
+	t.Run("AccountDeletions", testAccountDeletionsFind)
+	t.Run("AccountLockouts", testAccountLockoutsFind)
+	t.Run("AuditEvents", testAuditEventsFind)
+	t.Run("DataExports", testDataExportsFind)
+	t.Run("LoginAttempts", testLoginAttemptsFind)
+	t.Run("MagicLinks", testMagicLinksFind)
 	t.Run("MailerLogs", testMailerLogsFind)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesFind)
+	t.Run("OidcLoginStates", testOidcLoginStatesFind)
 	t.Run("Profiles", testProfilesFind)
 	t.Run("Roles", testRolesFind)
+	t.Run("UserIdentities", testUserIdentitiesFind)
+	t.Run("UserMfas", testUserMfasFind)
 	t.Run("Users", testUsersFind)
 }
 
 func TestBind(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysBind)
+	t.Run("AccountDeletions", testAccountDeletionsBind)
+	t.Run("AccountLockouts", testAccountLockoutsBind)
+	t.Run("AuditEvents", testAuditEventsBind)
+	t.Run("DataExports", testDataExportsBind)
+	t.Run("LoginAttempts", testLoginAttemptsBind)
+	t.Run("MagicLinks", testMagicLinksBind)
 	t.Run("MailerLogs", testMailerLogsBind)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesBind)
+	t.Run("OidcLoginStates", testOidcLoginStatesBind)
 	t.Run("Profiles", testProfilesBind)
 	t.Run("Roles", testRolesBind)
+	t.Run("UserIdentities", testUserIdentitiesBind)
+	t.Run("UserMfas", testUserMfasBind)
 	t.Run("Users", testUsersBind)
 }
 
 func TestOne(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysOne)
+	t.Run("AccountDeletions", testAccountDeletionsOne)
+	t.Run("AccountLockouts", testAccountLockoutsOne)
+	t.Run("AuditEvents", testAuditEventsOne)
+	t.Run("DataExports", testDataExportsOne)
+	t.Run("LoginAttempts", testLoginAttemptsOne)
+	t.Run("MagicLinks", testMagicLinksOne)
 	t.Run("MailerLogs", testMailerLogsOne)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesOne)
+	t.Run("OidcLoginStates", testOidcLoginStatesOne)
 	t.Run("Profiles", testProfilesOne)
 	t.Run("Roles", testRolesOne)
+	t.Run("UserIdentities", testUserIdentitiesOne)
+	t.Run("UserMfas", testUserMfasOne)
 	t.Run("Users", testUsersOne)
 }
 
 func TestAll(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysAll)
+	t.Run("AccountDeletions", testAccountDeletionsAll)
+	t.Run("AccountLockouts", testAccountLockoutsAll)
+	t.Run("AuditEvents", testAuditEventsAll)
+	t.Run("DataExports", testDataExportsAll)
+	t.Run("LoginAttempts", testLoginAttemptsAll)
+	t.Run("MagicLinks", testMagicLinksAll)
 	t.Run("MailerLogs", testMailerLogsAll)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesAll)
+	t.Run("OidcLoginStates", testOidcLoginStatesAll)
 	t.Run("Profiles", testProfilesAll)
 	t.Run("Roles", testRolesAll)
+	t.Run("UserIdentities", testUserIdentitiesAll)
+	t.Run("UserMfas", testUserMfasAll)
 	t.Run("Users", testUsersAll)
 }
 
 func TestCount(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysCount)
+	t.Run("AccountDeletions", testAccountDeletionsCount)
+	t.Run("AccountLockouts", testAccountLockoutsCount)
+	t.Run("AuditEvents", testAuditEventsCount)
+	t.Run("DataExports", testDataExportsCount)
+	t.Run("LoginAttempts", testLoginAttemptsCount)
+	t.Run("MagicLinks", testMagicLinksCount)
 	t.Run("MailerLogs", testMailerLogsCount)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesCount)
+	t.Run("OidcLoginStates", testOidcLoginStatesCount)
 	t.Run("Profiles", testProfilesCount)
 	t.Run("Roles", testRolesCount)
+	t.Run("UserIdentities", testUserIdentitiesCount)
+	t.Run("UserMfas", testUserMfasCount)
 	t.Run("Users", testUsersCount)
 }
 
 func TestHooks(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysHooks)
+	t.Run("AccountDeletions", testAccountDeletionsHooks)
+	t.Run("AccountLockouts", testAccountLockoutsHooks)
+	t.Run("AuditEvents", testAuditEventsHooks)
+	t.Run("DataExports", testDataExportsHooks)
+	t.Run("LoginAttempts", testLoginAttemptsHooks)
+	t.Run("MagicLinks", testMagicLinksHooks)
 	t.Run("MailerLogs", testMailerLogsHooks)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesHooks)
+	t.Run("OidcLoginStates", testOidcLoginStatesHooks)
 	t.Run("Profiles", testProfilesHooks)
 	t.Run("Roles", testRolesHooks)
+	t.Run("UserIdentities", testUserIdentitiesHooks)
+	t.Run("UserMfas", testUserMfasHooks)
 	t.Run("Users", testUsersHooks)
 }
 
 func TestInsert(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysInsert)
+	t.Run("APIKeys", testAPIKeysInsertWhitelist)
+	t.Run("AccountDeletions", testAccountDeletionsInsert)
+	t.Run("AccountDeletions", testAccountDeletionsInsertWhitelist)
+	t.Run("AccountLockouts", testAccountLockoutsInsert)
+	t.Run("AccountLockouts", testAccountLockoutsInsertWhitelist)
+	t.Run("AuditEvents", testAuditEventsInsert)
+	t.Run("AuditEvents", testAuditEventsInsertWhitelist)
+	t.Run("DataExports", testDataExportsInsert)
+	t.Run("DataExports", testDataExportsInsertWhitelist)
+	t.Run("LoginAttempts", testLoginAttemptsInsert)
+	t.Run("LoginAttempts", testLoginAttemptsInsertWhitelist)
+	t.Run("MagicLinks", testMagicLinksInsert)
+	t.Run("MagicLinks", testMagicLinksInsertWhitelist)
 	t.Run("MailerLogs", testMailerLogsInsert)
 	t.Run("MailerLogs", testMailerLogsInsertWhitelist)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesInsert)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesInsertWhitelist)
+	t.Run("OidcLoginStates", testOidcLoginStatesInsert)
+	t.Run("OidcLoginStates", testOidcLoginStatesInsertWhitelist)
 	t.Run("Profiles", testProfilesInsert)
 	t.Run("Profiles", testProfilesInsertWhitelist)
 	t.Run("Roles", testRolesInsert)
 	t.Run("Roles", testRolesInsertWhitelist)
+	t.Run("UserIdentities", testUserIdentitiesInsert)
+	t.Run("UserIdentities", testUserIdentitiesInsertWhitelist)
+	t.Run("UserMfas", testUserMfasInsert)
+	t.Run("UserMfas", testUserMfasInsertWhitelist)
 	t.Run("Users", testUsersInsert)
 	t.Run("Users", testUsersInsertWhitelist)
 }
@@ -152,36 +295,91 @@ func TestToManySet(t *testing.T) {}
 func TestToManyRemove(t *testing.T) {}
 
 func TestReload(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysReload)
+	t.Run("AccountDeletions", testAccountDeletionsReload)
+	t.Run("AccountLockouts", testAccountLockoutsReload)
+	t.Run("AuditEvents", testAuditEventsReload)
+	t.Run("DataExports", testDataExportsReload)
+	t.Run("LoginAttempts", testLoginAttemptsReload)
+	t.Run("MagicLinks", testMagicLinksReload)
 	t.Run("MailerLogs", testMailerLogsReload)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesReload)
+	t.Run("OidcLoginStates", testOidcLoginStatesReload)
 	t.Run("Profiles", testProfilesReload)
 	t.Run("Roles", testRolesReload)
+	t.Run("UserIdentities", testUserIdentitiesReload)
+	t.Run("UserMfas", testUserMfasReload)
 	t.Run("Users", testUsersReload)
 }
 
 func TestReloadAll(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysReloadAll)
+	t.Run("AccountDeletions", testAccountDeletionsReloadAll)
+	t.Run("AccountLockouts", testAccountLockoutsReloadAll)
+	t.Run("AuditEvents", testAuditEventsReloadAll)
+	t.Run("DataExports", testDataExportsReloadAll)
+	t.Run("LoginAttempts", testLoginAttemptsReloadAll)
+	t.Run("MagicLinks", testMagicLinksReloadAll)
 	t.Run("MailerLogs", testMailerLogsReloadAll)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesReloadAll)
+	t.Run("OidcLoginStates", testOidcLoginStatesReloadAll)
 	t.Run("Profiles", testProfilesReloadAll)
 	t.Run("Roles", testRolesReloadAll)
+	t.Run("UserIdentities", testUserIdentitiesReloadAll)
+	t.Run("UserMfas", testUserMfasReloadAll)
 	t.Run("Users", testUsersReloadAll)
 }
 
 func TestSelect(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysSelect)
+	t.Run("AccountDeletions", testAccountDeletionsSelect)
+	t.Run("AccountLockouts", testAccountLockoutsSelect)
+	t.Run("AuditEvents", testAuditEventsSelect)
+	t.Run("DataExports", testDataExportsSelect)
+	t.Run("LoginAttempts", testLoginAttemptsSelect)
+	t.Run("MagicLinks", testMagicLinksSelect)
 	t.Run("MailerLogs", testMailerLogsSelect)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesSelect)
+	t.Run("OidcLoginStates", testOidcLoginStatesSelect)
 	t.Run("Profiles", testProfilesSelect)
 	t.Run("Roles", testRolesSelect)
+	t.Run("UserIdentities", testUserIdentitiesSelect)
+	t.Run("UserMfas", testUserMfasSelect)
 	t.Run("Users", testUsersSelect)
 }
 
 func TestUpdate(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysUpdate)
+	t.Run("AccountDeletions", testAccountDeletionsUpdate)
+	t.Run("AccountLockouts", testAccountLockoutsUpdate)
+	t.Run("AuditEvents", testAuditEventsUpdate)
+	t.Run("DataExports", testDataExportsUpdate)
+	t.Run("LoginAttempts", testLoginAttemptsUpdate)
+	t.Run("MagicLinks", testMagicLinksUpdate)
 	t.Run("MailerLogs", testMailerLogsUpdate)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesUpdate)
+	t.Run("OidcLoginStates", testOidcLoginStatesUpdate)
 	t.Run("Profiles", testProfilesUpdate)
 	t.Run("Roles", testRolesUpdate)
+	t.Run("UserIdentities", testUserIdentitiesUpdate)
+	t.Run("UserMfas", testUserMfasUpdate)
 	t.Run("Users", testUsersUpdate)
 }
 
 func TestSliceUpdateAll(t *testing.T) {
+	t.Run("APIKeys", testAPIKeysSliceUpdateAll)
+	t.Run("AccountDeletions", testAccountDeletionsSliceUpdateAll)
+	t.Run("AccountLockouts", testAccountLockoutsSliceUpdateAll)
+	t.Run("AuditEvents", testAuditEventsSliceUpdateAll)
+	t.Run("DataExports", testDataExportsSliceUpdateAll)
+	t.Run("LoginAttempts", testLoginAttemptsSliceUpdateAll)
+	t.Run("MagicLinks", testMagicLinksSliceUpdateAll)
 	t.Run("MailerLogs", testMailerLogsSliceUpdateAll)
+	t.Run("MfaRecoveryCodes", testMfaRecoveryCodesSliceUpdateAll)
+	t.Run("OidcLoginStates", testOidcLoginStatesSliceUpdateAll)
 	t.Run("Profiles", testProfilesSliceUpdateAll)
 	t.Run("Roles", testRolesSliceUpdateAll)
+	t.Run("UserIdentities", testUserIdentitiesSliceUpdateAll)
+	t.Run("UserMfas", testUserMfasSliceUpdateAll)
 	t.Run("Users", testUsersSliceUpdateAll)
 }
@@ -4,13 +4,35 @@
 package models
 
 var TableNames = struct {
-	MailerLogs string
-	Profiles   string
-	Roles      string
-	Users      string
+	APIKeys          string
+	AccountDeletions string
+	AccountLockouts  string
+	AuditEvents      string
+	DataExports      string
+	LoginAttempts    string
+	MagicLinks       string
+	MailerLogs       string
+	MfaRecoveryCodes string
+	OidcLoginStates  string
+	Profiles         string
+	Roles            string
+	UserIdentities   string
+	UserMfas         string
+	Users            string
 }{
-	MailerLogs: "mailer_logs",
-	Profiles:   "profiles",
-	Roles:      "roles",
-	Users:      "users",
+	APIKeys:          "api_keys",
+	AccountDeletions: "account_deletions",
+	AccountLockouts:  "account_lockouts",
+	AuditEvents:      "audit_events",
+	DataExports:      "data_exports",
+	LoginAttempts:    "login_attempts",
+	MagicLinks:       "magic_links",
+	MailerLogs:       "mailer_logs",
+	MfaRecoveryCodes: "mfa_recovery_codes",
+	OidcLoginStates:  "oidc_login_states",
+	Profiles:         "profiles",
+	Roles:            "roles",
+	UserIdentities:   "user_identities",
+	UserMfas:         "user_mfa",
+	Users:            "users",
 }
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"context"
+
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+// RunUserHooks runs the hooks added with AddUserHook for one of the after hook points. Stores that
+// keep users outside of postgres, like memstore, call it so the hooks see their writes too.
+func RunUserHooks(ctx context.Context, exec boil.ContextExecutor, hookPoint boil.HookPoint, user *User) error {
+	switch hookPoint {
+	case boil.AfterInsertHook:
+		return user.doAfterInsertHooks(ctx, exec)
+	case boil.AfterUpdateHook:
+		return user.doAfterUpdateHooks(ctx, exec)
+	case boil.AfterDeleteHook:
+		return user.doAfterDeleteHooks(ctx, exec)
+	case boil.AfterUpsertHook:
+		return user.doAfterUpsertHooks(ctx, exec)
+	}
+	return nil
+}
+
+// RunProfileHooks is RunUserHooks for the hooks added with AddProfileHook.
+func RunProfileHooks(ctx context.Context, exec boil.ContextExecutor, hookPoint boil.HookPoint, profile *Profile) error {
+	switch hookPoint {
+	case boil.AfterInsertHook:
+		return profile.doAfterInsertHooks(ctx, exec)
+	case boil.AfterUpdateHook:
+		return profile.doAfterUpdateHooks(ctx, exec)
+	case boil.AfterDeleteHook:
+		return profile.doAfterDeleteHooks(ctx, exec)
+	case boil.AfterUpsertHook:
+		return profile.doAfterUpsertHooks(ctx, exec)
+	}
+	return nil
+}
@@ -2,8 +2,12 @@ package models
 
 // SupportedMessageType is the types of messages we can send out
 var SupportedMessageType = map[string]string{
-	"CONFIRMATION":   "confirmation_mail",
-	"PASSWORD_RESET": "password_reset_mail",
+	"CONFIRMATION":     "confirmation_mail",
+	"PASSWORD_RESET":   "password_reset_mail",
+	"ACCOUNT_UNLOCK":   "account_unlock_mail",
+	"MAGIC_LINK":       "magic_link_mail",
+	"ACCOUNT_DELETION": "account_deletion_mail",
+	"DATA_EXPORT":      "data_export_mail",
 }
 // SupportedStatus message statuses a message can have
 var SupportedStatus = map[string]string{
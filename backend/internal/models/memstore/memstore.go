@@ -0,0 +1,364 @@
+// Package memstore implements models.DataLayer in memory, so services can be exercised without
+// postgres. Rows are copied in and out, callers never share them with the store.
+package memstore
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+// Store keeps every table in a map keyed by id, ids are handed out like a postgres serial.
+type Store struct {
+	mutex  sync.RWMutex
+	nextID int
+	tables
+}
+
+type tables struct {
+	users    map[int]models.User
+	profiles map[int]models.Profile
+	roles    map[int]models.Role
+	mailLogs map[int]models.MailerLog
+}
+
+// New returns an empty store seeded with the roles the database migrations create.
+func New() *Store {
+	s := &Store{tables: tables{
+		users:    map[int]models.User{},
+		profiles: map[int]models.Profile{},
+		roles:    map[int]models.Role{},
+		mailLogs: map[int]models.MailerLog{},
+	}}
+	for _, role := range []models.Role{
+		{Name: "Master", Slug: "master"},
+		{Name: "Client", Slug: "client"},
+		{Name: "User", Slug: "user"},
+	} {
+		s.AddRole(role)
+	}
+	return s
+}
+
+// AddRole stores role and returns it with its id set.
+func (s *Store) AddRole(role models.Role) models.Role {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	role.ID = s.id()
+	role.CreatedAt, role.UpdatedAt = stamp(role.CreatedAt)
+	s.roles[role.ID] = role
+	return role
+}
+
+func (s *Store) Users() models.UserRepository {
+	return userRepository{s}
+}
+
+func (s *Store) Profiles() models.ProfileRepository {
+	return profileRepository{s}
+}
+
+func (s *Store) Roles() models.RoleRepository {
+	return roleRepository{s}
+}
+
+func (s *Store) MailLogs() models.MailLogRepository {
+	return mailLogRepository{s}
+}
+
+// InTransaction puts back a copy of the tables taken before fn ran when it fails. Unlike postgres it
+// doesn't isolate fn from concurrent writes, ids aren't reused either, like a serial.
+func (s *Store) InTransaction(_ context.Context, fn func(models.DataLayer) error) error {
+	s.mutex.RLock()
+	snapshot := tables{
+		users:    make(map[int]models.User, len(s.users)),
+		profiles: make(map[int]models.Profile, len(s.profiles)),
+		roles:    make(map[int]models.Role, len(s.roles)),
+		mailLogs: make(map[int]models.MailerLog, len(s.mailLogs)),
+	}
+	for id, user := range s.users {
+		snapshot.users[id] = user
+	}
+	for id, profile := range s.profiles {
+		snapshot.profiles[id] = profile
+	}
+	for id, role := range s.roles {
+		snapshot.roles[id] = role
+	}
+	for id, log := range s.mailLogs {
+		snapshot.mailLogs[id] = log
+	}
+	s.mutex.RUnlock()
+	if err := fn(s); err != nil {
+		s.mutex.Lock()
+		s.tables = snapshot
+		s.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// MailLogList returns every mail log, in insertion order.
+func (s *Store) MailLogList() []models.MailerLog {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	logs := make([]models.MailerLog, 0, len(s.mailLogs))
+	for id := 1; id <= s.nextID; id++ {
+		if log, ok := s.mailLogs[id]; ok {
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
+// id hands out the next id, ids are unique across tables. Callers hold the lock.
+func (s *Store) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+// stamp fills in created_at the way the database does and returns it along with a fresh updated_at.
+func stamp(createdAt time.Time) (time.Time, time.Time) {
+	now := time.Now()
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	return createdAt, now
+}
+
+type userRepository struct {
+	store *Store
+}
+
+func (r userRepository) FindByID(_ context.Context, id int, scope ...models.Scope) (*models.User, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	user, ok := r.store.users[id]
+	if !ok || !inScope(scope, user.Deleted) {
+		return nil, sql.ErrNoRows
+	}
+	return &user, nil
+}
+
+// FindByEmail prefers the active user, then the latest one, like the ordering of the sql query.
+func (r userRepository) FindByEmail(_ context.Context, email string, scope ...models.Scope) (*models.User, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	var found *models.User
+	for _, user := range r.store.users {
+		if !strings.EqualFold(user.Email, email) || !inScope(scope, user.Deleted) {
+			continue
+		}
+		if found == nil || found.Deleted && !user.Deleted || found.Deleted == user.Deleted && user.ID > found.ID {
+			user := user
+			found = &user
+		}
+	}
+	if found == nil {
+		return nil, sql.ErrNoRows
+	}
+	return found, nil
+}
+
+func (r userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := r.FindByEmail(ctx, email)
+	return err == nil, nil
+}
+
+// Insert and Update run the after hooks of users, like the generated methods do, once the store is
+// unlocked so hooks can read it.
+func (r userRepository) Insert(ctx context.Context, user *models.User) error {
+	if err := r.insert(user); err != nil {
+		return err
+	}
+	return models.RunUserHooks(ctx, nil, boil.AfterInsertHook, user)
+}
+
+func (r userRepository) insert(user *models.User) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	if r.emailTaken(user) {
+		return errUniqueViolation(models.UniqueEmailIndex)
+	}
+	user.ID = r.store.id()
+	user.CreatedAt, user.UpdatedAt = stamp(user.CreatedAt)
+	if !user.Confirmed.Valid {
+		user.Confirmed.Valid = true
+	}
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+// Update saves the whole row, columns only matter to the sql implementation.
+func (r userRepository) Update(ctx context.Context, user *models.User, _ ...string) error {
+	if err := r.update(user); err != nil {
+		return err
+	}
+	return models.RunUserHooks(ctx, nil, boil.AfterUpdateHook, user)
+}
+
+func (r userRepository) update(user *models.User) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	if _, ok := r.store.users[user.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	if r.emailTaken(user) {
+		return errUniqueViolation(models.UniqueEmailIndex)
+	}
+	user.UpdatedAt = time.Now()
+	r.store.users[user.ID] = *user
+	return nil
+}
+
+func (r userRepository) SoftDelete(ctx context.Context, user *models.User) error {
+	user.Deleted = true
+	return r.Update(ctx, user)
+}
+
+func (r userRepository) Restore(ctx context.Context, user *models.User) error {
+	user.Deleted = false
+	return r.Update(ctx, user)
+}
+
+// emailTaken enforces the partial unique index on the emails of active users. Callers hold the lock.
+func (r userRepository) emailTaken(user *models.User) bool {
+	if user.Deleted {
+		return false
+	}
+	for _, existing := range r.store.users {
+		if existing.ID != user.ID && !existing.Deleted && strings.EqualFold(existing.Email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+type profileRepository struct {
+	store *Store
+}
+
+func (r profileRepository) FindByID(_ context.Context, id int) (*models.Profile, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	profile, ok := r.store.profiles[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &profile, nil
+}
+
+func (r profileRepository) Insert(ctx context.Context, profile *models.Profile) error {
+	r.store.mutex.Lock()
+	profile.ID = r.store.id()
+	r.store.profiles[profile.ID] = *profile
+	r.store.mutex.Unlock()
+	return models.RunProfileHooks(ctx, nil, boil.AfterInsertHook, profile)
+}
+
+type roleRepository struct {
+	store *Store
+}
+
+func (r roleRepository) FindByID(_ context.Context, id int, scope ...models.Scope) (*models.Role, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	role, ok := r.store.roles[id]
+	if !ok || !inScope(scope, role.Deleted) {
+		return nil, sql.ErrNoRows
+	}
+	return &role, nil
+}
+
+func (r roleRepository) FindBySlug(_ context.Context, slug string, scope ...models.Scope) (*models.Role, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	var found *models.Role
+	for _, role := range r.store.roles {
+		if role.Slug != slug || !inScope(scope, role.Deleted) {
+			continue
+		}
+		if found == nil || found.Deleted && !role.Deleted || found.Deleted == role.Deleted && role.ID < found.ID {
+			role := role
+			found = &role
+		}
+	}
+	if found == nil {
+		return nil, sql.ErrNoRows
+	}
+	return found, nil
+}
+
+func (r roleRepository) SoftDelete(_ context.Context, role *models.Role) error {
+	role.Deleted = true
+	return r.update(role)
+}
+
+func (r roleRepository) Restore(_ context.Context, role *models.Role) error {
+	role.Deleted = false
+	return r.update(role)
+}
+
+func (r roleRepository) update(role *models.Role) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	if _, ok := r.store.roles[role.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	role.UpdatedAt = time.Now()
+	r.store.roles[role.ID] = *role
+	return nil
+}
+
+type mailLogRepository struct {
+	store *Store
+}
+
+func (r mailLogRepository) FindByID(_ context.Context, id int) (*models.MailerLog, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	log, ok := r.store.mailLogs[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &log, nil
+}
+
+func (r mailLogRepository) Insert(_ context.Context, log *models.MailerLog) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	log.ID = r.store.id()
+	log.CreatedAt, log.UpdatedAt = stamp(log.CreatedAt)
+	r.store.mailLogs[log.ID] = *log
+	return nil
+}
+
+func (r mailLogRepository) Update(_ context.Context, log *models.MailerLog) error {
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+	if _, ok := r.store.mailLogs[log.ID]; !ok {
+		return sql.ErrNoRows
+	}
+	log.UpdatedAt = time.Now()
+	r.store.mailLogs[log.ID] = *log
+	return nil
+}
+
+// inScope reports whether a row with the given deleted flag is seen by a finder given scope.
+func inScope(scope []models.Scope, deleted bool) bool {
+	if len(scope) == 0 {
+		return models.Active.Includes(deleted)
+	}
+	return scope[0].Includes(deleted)
+}
+
+// errUniqueViolation is what postgres reports when constraint is violated.
+func errUniqueViolation(constraint string) error {
+	return &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint", Constraint: constraint}
+}
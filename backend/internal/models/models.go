@@ -1,14 +1,26 @@
 package models
 
 import (
+	"context"
 	"database/sql"
+	"github.com/volatiletech/sqlboiler/boil"
 )
 
+// DataLayer is how services reach storage without depending on how it is done. DataStore is the
+// postgres implementation, memstore keeps everything in memory for tests.
 type DataLayer interface {
-
+	Users() UserRepository
+	Profiles() ProfileRepository
+	Roles() RoleRepository
+	MailLogs() MailLogRepository
+	// InTransaction runs fn with a data layer whose writes are committed when fn succeeds and rolled
+	// back otherwise.
+	InTransaction(ctx context.Context, fn func(DataLayer) error) error
 }
 type DataStore struct {
 	DB *sql.DB
+	// Executor is what queries should run against, it wraps DB with instrumentation.
+	Executor boil.ContextExecutor
 }
 
 
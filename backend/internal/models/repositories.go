@@ -0,0 +1,191 @@
+package models
+
+import (
+	"context"
+
+	"github.com/ntwarijoshua/siena/internal/tracing"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Repositories report missing rows with sql.ErrNoRows, possibly wrapped, like the generated finders.
+// Finders of soft deletable rows take an optional Scope, Active when left out.
+
+// UniqueEmailIndex keeps the emails of active accounts unique regardless of case. A soft deleted
+// account doesn't hold on to its email, which is why restoring one can fail with a unique violation
+// on this index.
+const UniqueEmailIndex = "users_email_active_key"
+
+// UserEmailIs matches users by email the way UniqueEmailIndex compares them, regardless of case.
+func UserEmailIs(email string) qm.QueryMod {
+	return qm.Where("lower(users.email) = lower(?)", email)
+}
+
+// UserRepository stores user accounts.
+type UserRepository interface {
+	FindByID(ctx context.Context, id int, scope ...Scope) (*User, error)
+	// FindByEmail prefers the active account when deleted ones are in scope, then the latest.
+	FindByEmail(ctx context.Context, email string, scope ...Scope) (*User, error)
+	// EmailExists reports whether an active account uses email, deleted ones don't block it.
+	EmailExists(ctx context.Context, email string) (bool, error)
+	Insert(ctx context.Context, user *User) error
+	// Update saves the given columns of user, all of them when none are given. updated_at is
+	// always refreshed.
+	Update(ctx context.Context, user *User, columns ...string) error
+	SoftDelete(ctx context.Context, user *User) error
+	Restore(ctx context.Context, user *User) error
+}
+
+// ProfileRepository stores the public profiles of users.
+type ProfileRepository interface {
+	FindByID(ctx context.Context, id int) (*Profile, error)
+	Insert(ctx context.Context, profile *Profile) error
+}
+
+// RoleRepository reads the roles users are given.
+type RoleRepository interface {
+	FindByID(ctx context.Context, id int, scope ...Scope) (*Role, error)
+	FindBySlug(ctx context.Context, slug string, scope ...Scope) (*Role, error)
+	SoftDelete(ctx context.Context, role *Role) error
+	Restore(ctx context.Context, role *Role) error
+}
+
+// MailLogRepository keeps track of the mails we send.
+type MailLogRepository interface {
+	FindByID(ctx context.Context, id int) (*MailerLog, error)
+	Insert(ctx context.Context, log *MailerLog) error
+	Update(ctx context.Context, log *MailerLog) error
+}
+
+func (s *DataStore) Users() UserRepository {
+	return userRepository{s.Executor}
+}
+
+func (s *DataStore) Profiles() ProfileRepository {
+	return profileRepository{s.Executor}
+}
+
+func (s *DataStore) Roles() RoleRepository {
+	return roleRepository{s.Executor}
+}
+
+func (s *DataStore) MailLogs() MailLogRepository {
+	return mailLogRepository{s.Executor}
+}
+
+// InTransaction runs fn on a DataStore whose executor is a transaction.
+func (s *DataStore) InTransaction(ctx context.Context, fn func(DataLayer) error) error {
+	return s.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		return fn(&DataStore{DB: s.DB, Executor: tx})
+	})
+}
+
+// Transaction runs fn in a database transaction, committed when fn succeeds and rolled back
+// otherwise. The statements of fn are traced like those of the pool.
+func (s *DataStore) Transaction(ctx context.Context, fn func(tx boil.ContextExecutor) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(&tracing.Executor{DB: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// updateColumns whitelists columns for an update, letting boil infer them when there are none.
+func updateColumns(columns []string) boil.Columns {
+	if len(columns) == 0 {
+		return boil.Infer()
+	}
+	return boil.Whitelist(append(columns, "updated_at")...)
+}
+
+type userRepository struct {
+	exec boil.ContextExecutor
+}
+
+func (r userRepository) FindByID(ctx context.Context, id int, scope ...Scope) (*User, error) {
+	return ScopedUsers(scopeOf(scope), qm.Where("id = ?", id)).One(ctx, r.exec)
+}
+
+func (r userRepository) FindByEmail(ctx context.Context, email string, scope ...Scope) (*User, error) {
+	return ScopedUsers(scopeOf(scope), UserEmailIs(email), qm.OrderBy("deleted, id DESC")).One(ctx, r.exec)
+}
+
+func (r userRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	return ScopedUsers(Active, UserEmailIs(email)).Exists(ctx, r.exec)
+}
+
+func (r userRepository) Insert(ctx context.Context, user *User) error {
+	return user.Insert(ctx, r.exec, boil.Infer())
+}
+
+func (r userRepository) Update(ctx context.Context, user *User, columns ...string) error {
+	_, err := user.Update(ctx, r.exec, updateColumns(columns))
+	return err
+}
+
+func (r userRepository) SoftDelete(ctx context.Context, user *User) error {
+	user.Deleted = true
+	return r.Update(ctx, user, UserColumns.Deleted)
+}
+
+func (r userRepository) Restore(ctx context.Context, user *User) error {
+	user.Deleted = false
+	return r.Update(ctx, user, UserColumns.Deleted)
+}
+
+type profileRepository struct {
+	exec boil.ContextExecutor
+}
+
+func (r profileRepository) FindByID(ctx context.Context, id int) (*Profile, error) {
+	return FindProfile(ctx, r.exec, id)
+}
+
+func (r profileRepository) Insert(ctx context.Context, profile *Profile) error {
+	return profile.Insert(ctx, r.exec, boil.Infer())
+}
+
+type roleRepository struct {
+	exec boil.ContextExecutor
+}
+
+func (r roleRepository) FindByID(ctx context.Context, id int, scope ...Scope) (*Role, error) {
+	return ScopedRoles(scopeOf(scope), qm.Where("id = ?", id)).One(ctx, r.exec)
+}
+
+func (r roleRepository) FindBySlug(ctx context.Context, slug string, scope ...Scope) (*Role, error) {
+	return ScopedRoles(scopeOf(scope), qm.Where("slug = ?", slug), qm.OrderBy("deleted, id")).One(ctx, r.exec)
+}
+
+func (r roleRepository) SoftDelete(ctx context.Context, role *Role) error {
+	role.Deleted = true
+	_, err := role.Update(ctx, r.exec, updateColumns([]string{RoleColumns.Deleted}))
+	return err
+}
+
+func (r roleRepository) Restore(ctx context.Context, role *Role) error {
+	role.Deleted = false
+	_, err := role.Update(ctx, r.exec, updateColumns([]string{RoleColumns.Deleted}))
+	return err
+}
+
+type mailLogRepository struct {
+	exec boil.ContextExecutor
+}
+
+func (r mailLogRepository) FindByID(ctx context.Context, id int) (*MailerLog, error) {
+	return FindMailerLog(ctx, r.exec, id)
+}
+
+func (r mailLogRepository) Insert(ctx context.Context, log *MailerLog) error {
+	return log.Insert(ctx, r.exec, boil.Infer())
+}
+
+func (r mailLogRepository) Update(ctx context.Context, log *MailerLog) error {
+	_, err := log.Update(ctx, r.exec, boil.Infer())
+	return err
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Scope selects rows of soft deletable tables, users and roles, by their deleted flag. Repositories
+// and ScopedUsers/ScopedRoles leave deleted rows out unless given another scope. The generated
+// finders, FindUser and friends, don't know about scopes and see every row.
+type Scope int
+
+const (
+	// Active rows only, the default.
+	Active Scope = iota
+	// WithDeleted includes soft deleted rows.
+	WithDeleted
+	// OnlyDeleted is soft deleted rows alone, for restoring them.
+	OnlyDeleted
+)
+
+// scopeOf is the scope given to a method taking an optional one.
+func scopeOf(scopes []Scope) Scope {
+	if len(scopes) == 0 {
+		return Active
+	}
+	return scopes[0]
+}
+
+// Includes reports whether a row with the given deleted flag is in scope.
+func (s Scope) Includes(deleted bool) bool {
+	switch s {
+	case WithDeleted:
+		return true
+	case OnlyDeleted:
+		return deleted
+	default:
+		return !deleted
+	}
+}
+
+// QueryMods restricts a query on table to the scope.
+func (s Scope) QueryMods(table string) []qm.QueryMod {
+	switch s {
+	case WithDeleted:
+		return nil
+	case OnlyDeleted:
+		return []qm.QueryMod{qm.Where(table + ".deleted = TRUE")}
+	default:
+		return []qm.QueryMod{qm.Where(table + ".deleted = FALSE")}
+	}
+}
+
+// ScopedUsers queries the users in scope.
+func ScopedUsers(scope Scope, mods ...qm.QueryMod) userQuery {
+	return Users(append(scope.QueryMods(TableNames.Users), mods...)...)
+}
+
+// ScopedRoles queries the roles in scope.
+func ScopedRoles(scope Scope, mods ...qm.QueryMod) roleQuery {
+	return Roles(append(scope.QueryMods(TableNames.Roles), mods...)...)
+}
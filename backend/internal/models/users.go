@@ -24,15 +24,16 @@ import (
 
 // User is an object representing the database table.
 type User struct {
-	ID        int       `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Email     string    `boil:"email" json:"email" toml:"email" yaml:"email"`
-	Password  string    `boil:"password" json:"password" toml:"password" yaml:"password"`
-	Confirmed null.Bool `boil:"confirmed" json:"confirmed,omitempty" toml:"confirmed" yaml:"confirmed,omitempty"`
-	ProfileID int       `boil:"profile_id" json:"profile_id" toml:"profile_id" yaml:"profile_id"`
-	RoleID    int       `boil:"role_id" json:"role_id" toml:"role_id" yaml:"role_id"`
-	CreatedAt time.Time `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
-	UpdatedAt time.Time `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
-	Deleted   bool      `boil:"deleted" json:"deleted" toml:"deleted" yaml:"deleted"`
+	ID        int         `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Email     string      `boil:"email" json:"email" toml:"email" yaml:"email"`
+	Password  string      `boil:"password" json:"password" toml:"password" yaml:"password"`
+	Confirmed null.Bool   `boil:"confirmed" json:"confirmed,omitempty" toml:"confirmed" yaml:"confirmed,omitempty"`
+	ProfileID int         `boil:"profile_id" json:"profile_id" toml:"profile_id" yaml:"profile_id"`
+	RoleID    int         `boil:"role_id" json:"role_id" toml:"role_id" yaml:"role_id"`
+	CreatedAt time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	UpdatedAt time.Time   `boil:"updated_at" json:"updated_at" toml:"updated_at" yaml:"updated_at"`
+	Deleted   bool        `boil:"deleted" json:"deleted" toml:"deleted" yaml:"deleted"`
+	Locale    null.String `boil:"locale" json:"locale,omitempty" toml:"locale" yaml:"locale,omitempty"`
 
 	R *userR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L userL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -48,6 +49,7 @@ var UserColumns = struct {
 	CreatedAt string
 	UpdatedAt string
 	Deleted   string
+	Locale    string
 }{
 	ID:        "id",
 	Email:     "email",
@@ -58,6 +60,7 @@ var UserColumns = struct {
 	CreatedAt: "created_at",
 	UpdatedAt: "updated_at",
 	Deleted:   "deleted",
+	Locale:    "locale",
 }
 
 // Generated where
@@ -95,6 +98,7 @@ var UserWhere = struct {
 	CreatedAt whereHelpertime_Time
 	UpdatedAt whereHelpertime_Time
 	Deleted   whereHelperbool
+	Locale    whereHelpernull_String
 }{
 	ID:        whereHelperint{field: "\"users\".\"id\""},
 	Email:     whereHelperstring{field: "\"users\".\"email\""},
@@ -105,6 +109,7 @@ var UserWhere = struct {
 	CreatedAt: whereHelpertime_Time{field: "\"users\".\"created_at\""},
 	UpdatedAt: whereHelpertime_Time{field: "\"users\".\"updated_at\""},
 	Deleted:   whereHelperbool{field: "\"users\".\"deleted\""},
+	Locale:    whereHelpernull_String{field: "\"users\".\"locale\""},
 }
 
 // UserRels is where relationship names are stored.
@@ -131,8 +136,8 @@ func (*userR) NewStruct() *userR {
 type userL struct{}
 
 var (
-	userAllColumns            = []string{"id", "email", "password", "confirmed", "profile_id", "role_id", "created_at", "updated_at", "deleted"}
-	userColumnsWithoutDefault = []string{"email", "password", "profile_id", "role_id", "created_at"}
+	userAllColumns            = []string{"id", "email", "password", "confirmed", "profile_id", "role_id", "created_at", "updated_at", "deleted", "locale"}
+	userColumnsWithoutDefault = []string{"email", "password", "profile_id", "role_id", "created_at", "locale"}
 	userColumnsWithDefault    = []string{"id", "confirmed", "updated_at", "deleted"}
 	userPrimaryKeyColumns     = []string{"id"}
 )
@@ -785,7 +785,7 @@ func testUsersSelect(t *testing.T) {
 }
 
 var (
-	userDBTypes = map[string]string{`ID`: `integer`, `Email`: `character varying`, `Password`: `character varying`, `Confirmed`: `boolean`, `ProfileID`: `integer`, `RoleID`: `integer`, `CreatedAt`: `timestamp with time zone`, `UpdatedAt`: `timestamp with time zone`, `Deleted`: `boolean`}
+	userDBTypes = map[string]string{`ID`: `integer`, `Email`: `character varying`, `Password`: `character varying`, `Confirmed`: `boolean`, `ProfileID`: `integer`, `RoleID`: `integer`, `CreatedAt`: `timestamp with time zone`, `UpdatedAt`: `timestamp with time zone`, `Deleted`: `boolean`, `Locale`: `character varying`}
 	_           = bytes.MinRead
 )
 
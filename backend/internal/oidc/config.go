@@ -0,0 +1,58 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+)
+
+// wellKnownIssuers lets the common providers be configured with a client id and secret only.
+var wellKnownIssuers = map[string]string{
+	"google": "https://accounts.google.com",
+	"apple":  "https://appleid.apple.com",
+}
+
+// ConfigsFromEnv reads the providers listed in OIDC_PROVIDERS, e.g. "google,apple,keycloak". Each
+// provider is configured through OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET
+// and optionally OIDC_<NAME>_SCOPES. Providers redirect back to callbackBase/<name>/callback.
+//
+// Apple expects a signed JWT as client secret, it has to be generated out of band and rotated
+// before it expires.
+func ConfigsFromEnv(callbackBase string) []Config {
+	var configs []Config
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		issuer := os.Getenv(prefix + "ISSUER")
+		if issuer == "" {
+			issuer = wellKnownIssuers[name]
+		}
+		config := Config{
+			Name:         name,
+			Issuer:       issuer,
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimRight(callbackBase, "/") + "/" + name + "/callback",
+		}
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			config.Scopes = strings.Fields(scopes)
+		} else if name == "apple" {
+			config.Scopes = []string{"openid", "email", "name"}
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			continue
+		}
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+// ResponseMode is form_post for apple, which refuses query responses when email or name are asked for.
+func (p *Provider) ResponseMode() string {
+	if p.config.Name == "apple" {
+		return "form_post"
+	}
+	return ""
+}
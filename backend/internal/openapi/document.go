@@ -0,0 +1,215 @@
+// Package openapi describes the http api as an OpenAPI 3 document built from the Go types handlers
+// bind requests to and render responses from, and checks traffic against it so the document can't
+// silently drift from what the handlers do.
+package openapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the OpenAPI version documents are written in.
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations of a path keyed by lower case http method, as OpenAPI lays them out.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+// Route describes an endpoint in the terms handlers are written in. Request and the Responses values
+// are zero values of the types bound and rendered, their schemas are derived from them.
+type Route struct {
+	Method string
+	// Path is in gin's syntax, /users/:id.
+	Path string
+	// Query names the optional query parameters, they are strings as far as the document goes.
+	Query   []string
+	Summary string
+	Tag     string
+	// Security names the schemes any of which authenticates the request, none for public routes.
+	Security []string
+	// Request is the json body, nil when there is none.
+	Request interface{}
+	// Responses are the json bodies by status, nil for responses without one such as redirects.
+	// AnyOf describes bodies that take one of several shapes.
+	Responses map[int]interface{}
+}
+
+// Build generates the document describing routes. Responses every route can answer with, like
+// errors, are given as defaults.
+func Build(info Info, servers []Server, schemes map[string]*SecurityScheme, defaults map[int]interface{}, routes []Route) *Document {
+	generator := newGenerator()
+	document := &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Servers:    servers,
+		Paths:      map[string]PathItem{},
+		Components: Components{SecuritySchemes: schemes},
+	}
+	for _, route := range routes {
+		path, parameters := convertPath(route.Path)
+		for _, name := range route.Query {
+			parameters = append(parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+		}
+		operation := &Operation{
+			OperationID: operationID(route.Method, path),
+			Summary:     route.Summary,
+			Parameters:  parameters,
+			Responses:   map[string]*Response{},
+		}
+		if route.Tag != "" {
+			operation.Tags = []string{route.Tag}
+		}
+		for _, scheme := range route.Security {
+			operation.Security = append(operation.Security, map[string][]string{scheme: {}})
+		}
+		if route.Request != nil {
+			operation.RequestBody = &RequestBody{
+				Required: true,
+				Content:  jsonContent(generator.schema(route.Request, true)),
+			}
+		}
+		for status, body := range defaults {
+			if _, overridden := route.Responses[status]; !overridden {
+				operation.Responses[strconv.Itoa(status)] = generator.response(status, body)
+			}
+		}
+		for status, body := range route.Responses {
+			operation.Responses[strconv.Itoa(status)] = generator.response(status, body)
+		}
+
+		if document.Paths[path] == nil {
+			document.Paths[path] = PathItem{}
+		}
+		document.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+	document.Components.Schemas = generator.components
+	return document
+}
+
+// Operation returns the operation serving method on path, given in gin's syntax, nil if none does.
+func (d *Document) Operation(method, path string) *Operation {
+	openAPIPath, _ := convertPath(path)
+	return d.Paths[openAPIPath][strings.ToLower(method)]
+}
+
+// Routes lists the documented method and path pairs, paths in gin's syntax, sorted.
+func (d *Document) Routes() [][2]string {
+	var routes [][2]string
+	for path, item := range d.Paths {
+		for method := range item {
+			routes = append(routes, [2]string{strings.ToUpper(method), ginPath(path)})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i][1] != routes[j][1] {
+			return routes[i][1] < routes[j][1]
+		}
+		return routes[i][0] < routes[j][0]
+	})
+	return routes
+}
+
+func (g *generator) response(status int, body interface{}) *Response {
+	response := &Response{Description: http.StatusText(status)}
+	if body != nil {
+		response.Content = jsonContent(g.schema(body, false))
+	}
+	return response
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+// convertPath turns /users/:id into /users/{id} along with the parameter it declares.
+func convertPath(path string) (string, []Parameter) {
+	segments := strings.Split(path, "/")
+	var parameters []Parameter
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			name := segment[1:]
+			segments[i] = "{" + name + "}"
+			parameters = append(parameters, Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+	return strings.Join(segments, "/"), parameters
+}
+
+func ginPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID derives a stable id from the method and path, e.g. post_api_v1_auth_magic_link.
+func operationID(method, path string) string {
+	replacer := strings.NewReplacer("/", "_", "-", "_", "{", "", "}", "")
+	return strings.ToLower(method) + strings.TrimRight(replacer.Replace(path), "_")
+}
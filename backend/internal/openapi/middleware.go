@@ -0,0 +1,96 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Violation is a request or response that doesn't match the document.
+type Violation struct {
+	Method string
+	Path   string
+	// Status is the response status, 0 when the request is at fault.
+	Status int
+	Err    error
+}
+
+func (v Violation) Error() string {
+	if v.Status == 0 {
+		return fmt.Sprintf("%s %s request: %s", v.Method, v.Path, v.Err)
+	}
+	return fmt.Sprintf("%s %s %d response: %s", v.Method, v.Path, v.Status, v.Err)
+}
+
+// Middleware checks the traffic of the routes against document and hands every mismatch to report,
+// requests are served either way. It has to run before the handlers that write error bodies after
+// the fact, ErrorHandler among them, to see what they write. Serving a route the document doesn't
+// cover is a mismatch too, only requests no route matches are let through unchecked.
+func Middleware(document *Document, report func(Violation)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method, path := c.Request.Method, c.FullPath()
+		if path == "" {
+			c.Next()
+			return
+		}
+		operation := document.Operation(method, path)
+		if operation == nil {
+			report(Violation{Method: method, Path: path, Err: fmt.Errorf("route is not documented")})
+			c.Next()
+			return
+		}
+
+		if operation.RequestBody != nil && c.Request.Body != nil {
+			raw, err := ioutil.ReadAll(c.Request.Body)
+			c.Request.Body = ioutil.NopCloser(bytes.NewReader(raw))
+			if err == nil {
+				err = document.ValidateJSON(operation.RequestBody.Content["application/json"].Schema, raw)
+			}
+			if err != nil {
+				report(Violation{Method: method, Path: path, Err: err})
+			}
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		response, documented := operation.Responses[strconv.Itoa(status)]
+		if !documented {
+			report(Violation{Method: method, Path: path, Status: status, Err: fmt.Errorf("status is not documented")})
+			return
+		}
+		media, hasBody := response.Content["application/json"]
+		switch {
+		case !hasBody:
+			return
+		case !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/json"):
+			report(Violation{Method: method, Path: path, Status: status, Err: fmt.Errorf("body is not json")})
+		default:
+			if err := document.ValidateJSON(media.Schema, recorder.body.Bytes()); err != nil {
+				report(Violation{Method: method, Path: path, Status: status, Err: err})
+			}
+		}
+	}
+}
+
+// bodyRecorder keeps a copy of what is written to the response.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
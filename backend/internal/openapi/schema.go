@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+// Schema is the subset of the OpenAPI schema object the generator produces and the validator checks.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+}
+
+// AnyOf is a body that takes the shape of one of its values, e.g. a login answered with either a
+// token or a second factor challenge.
+type AnyOf []interface{}
+
+// schemaRefPrefix is where component schemas are referenced from.
+const schemaRefPrefix = "#/components/schemas/"
+
+// scalarTypes maps types that don't serialize the way their kind suggests.
+var scalarTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):             {Type: "string", Format: "date-time"},
+	reflect.TypeOf(json.RawMessage{}):       {},
+	reflect.TypeOf(null.String{}):           {Type: "string", Nullable: true},
+	reflect.TypeOf(null.Int{}):              {Type: "integer", Nullable: true},
+	reflect.TypeOf(null.Int64{}):            {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(null.Bool{}):             {Type: "boolean", Nullable: true},
+	reflect.TypeOf(null.Float64{}):          {Type: "number", Nullable: true},
+	reflect.TypeOf(null.Time{}):             {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(null.JSON{}):             {Nullable: true},
+	reflect.TypeOf(sql.NullString{}):        {Type: "string", Nullable: true},
+	reflect.TypeOf(sql.NullInt64{}):         {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(sql.NullBool{}):          {Type: "boolean", Nullable: true},
+	reflect.TypeOf(new(interface{})).Elem(): {},
+}
+
+// generator turns Go types into schemas, named structs become components referenced by name.
+type generator struct {
+	components map[string]*Schema
+}
+
+func newGenerator() *generator {
+	return &generator{components: map[string]*Schema{}}
+}
+
+// schema describes the json encoding of value. In requests, fields are required when their validate
+// tag requires them, in responses when they are always encoded.
+func (g *generator) schema(value interface{}, request bool) *Schema {
+	if anyOf, ok := value.(AnyOf); ok {
+		schema := &Schema{}
+		for _, alternative := range anyOf {
+			schema.AnyOf = append(schema.AnyOf, g.schema(alternative, request))
+		}
+		return schema
+	}
+	return g.typeSchema(reflect.TypeOf(value), request)
+}
+
+func (g *generator) typeSchema(t reflect.Type, request bool) *Schema {
+	if scalar, ok := scalarTypes[t]; ok {
+		return &scalar
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := g.typeSchema(t.Elem(), request)
+		if schema.Ref != "" {
+			// siblings of $ref are ignored, the reference has to be wrapped to be nullable
+			return &Schema{Nullable: true, AnyOf: []*Schema{schema}}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem(), request)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem(), request)}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, request)
+		}
+		if _, done := g.components[t.Name()]; !done {
+			// registered before recursing so self referencing types terminate
+			g.components[t.Name()] = &Schema{}
+			*g.components[t.Name()] = *g.structSchema(t, request)
+		}
+		return &Schema{Ref: schemaRefPrefix + t.Name()}
+	}
+	return &Schema{}
+}
+
+func (g *generator) structSchema(t reflect.Type, request bool) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t, request)
+	return schema
+}
+
+// addFields adds the fields of t to schema the way encoding/json would encode them, embedded structs
+// are flattened.
+func (g *generator) addFields(schema *Schema, t reflect.Type, request bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, options := splitTag(tag)
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.addFields(schema, field.Type, request)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := g.typeSchema(field.Type, request)
+		rules := parseValidateTag(field.Tag.Get("validate"))
+		if property.Ref == "" {
+			rules.apply(property)
+		}
+		schema.Properties[name] = property
+
+		required := rules.required
+		if !request {
+			required = !strings.Contains(options, "omitempty")
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func splitTag(tag string) (string, string) {
+	if comma := strings.Index(tag, ","); comma >= 0 {
+		return tag[:comma], tag[comma+1:]
+	}
+	return tag, ""
+}
+
+// validateRules is what the schema can express of a validator.v9 tag.
+type validateRules struct {
+	required bool
+	format   string
+	enum     []string
+	min      *float64
+	max      *float64
+}
+
+func parseValidateTag(tag string) validateRules {
+	var rules validateRules
+	if tag == "" {
+		return rules
+	}
+	optional := false
+parse:
+	for _, rule := range strings.Split(tag, ",") {
+		name, param := splitRule(rule)
+		switch name {
+		case "dive":
+			// what follows applies to the elements
+			break parse
+		case "omitempty":
+			optional = true
+		case "required":
+			rules.required = true
+		case "email":
+			rules.format = "email"
+		case "url":
+			rules.format = "uri"
+		case "oneof":
+			rules.enum = strings.Fields(param)
+		case "min", "gte":
+			rules.min = parseBound(param)
+		case "max", "lte":
+			rules.max = parseBound(param)
+		case "len":
+			rules.min, rules.max = parseBound(param), parseBound(param)
+		}
+	}
+	// a value has to be there to be checked, unless the tag allows it to be left out
+	if rules.format != "" && !optional {
+		rules.required = true
+	}
+	return rules
+}
+
+func splitRule(rule string) (string, string) {
+	if equals := strings.Index(rule, "="); equals >= 0 {
+		return rule[:equals], rule[equals+1:]
+	}
+	return rule, ""
+}
+
+func parseBound(param string) *float64 {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil
+	}
+	return &bound
+}
+
+// apply sets the constraints of the rules on schema, bounds are lengths for strings and arrays.
+func (r validateRules) apply(schema *Schema) {
+	if r.format != "" {
+		schema.Format = r.format
+	}
+	if len(r.enum) > 0 {
+		schema.Enum = r.enum
+	}
+	switch schema.Type {
+	case "string":
+		schema.MinLength, schema.MaxLength = intBound(r.min), intBound(r.max)
+		if r.required && schema.MinLength == nil {
+			// validator's required rejects empty strings
+			schema.MinLength = intBound(&one)
+		}
+	case "integer", "number":
+		schema.Minimum, schema.Maximum = r.min, r.max
+	}
+}
+
+var one = 1.0
+
+func intBound(bound *float64) *int {
+	if bound == nil {
+		return nil
+	}
+	value := int(*bound)
+	return &value
+}
@@ -0,0 +1,176 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every way a body differs from its schema.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return strings.Join(e.Problems, "; ")
+}
+
+// ValidateJSON checks raw against schema. Objects are closed: properties the schema doesn't declare
+// are reported too, a field a handler starts sending is drift just as much as one it stops sending.
+func (d *Document) ValidateJSON(schema *Schema, raw []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Problems: []string{"body is not json: " + err.Error()}}
+	}
+	var problems []string
+	d.validate(schema, value, "$", &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (d *Document) resolve(schema *Schema) *Schema {
+	for schema.Ref != "" {
+		resolved, ok := d.Components.Schemas[strings.TrimPrefix(schema.Ref, schemaRefPrefix)]
+		if !ok {
+			return &Schema{}
+		}
+		schema = resolved
+	}
+	return schema
+}
+
+func (d *Document) validate(schema *Schema, value interface{}, path string, problems *[]string) {
+	schema = d.resolve(schema)
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			report("is null")
+		}
+		return
+	}
+	if len(schema.AnyOf) > 0 {
+		for _, alternative := range schema.AnyOf {
+			var alternativeProblems []string
+			d.validate(alternative, value, path, &alternativeProblems)
+			if len(alternativeProblems) == 0 {
+				return
+			}
+		}
+		report("matches none of the %d alternatives", len(schema.AnyOf))
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			report("should be an object")
+			return
+		}
+		for _, name := range schema.Required {
+			if _, present := object[name]; !present {
+				report("%s is missing", name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, declared := schema.Properties[name]
+			if !declared {
+				property = schema.AdditionalProperties
+			}
+			if property == nil {
+				report("%s is not documented", name)
+				continue
+			}
+			d.validate(property, object[name], path+"."+name, problems)
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			report("should be an array")
+			return
+		}
+		for i, item := range array {
+			d.validate(schema.Items, item, path+"["+strconv.Itoa(i)+"]", problems)
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			report("should be a string")
+			return
+		}
+		if schema.MinLength != nil && len([]rune(text)) < *schema.MinLength {
+			report("should be at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && len([]rune(text)) > *schema.MaxLength {
+			report("should be at most %d characters", *schema.MaxLength)
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, text) {
+			report("should be one of %s", strings.Join(schema.Enum, ", "))
+		}
+		if err := checkFormat(schema.Format, text); err != nil {
+			report("is not a valid %s", schema.Format)
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			report("should be a %s", schema.Type)
+			return
+		}
+		float, err := number.Float64()
+		if err != nil {
+			report("should be a %s", schema.Type)
+			return
+		}
+		if _, err = number.Int64(); schema.Type == "integer" && err != nil {
+			report("should be an integer")
+		}
+		if schema.Minimum != nil && float < *schema.Minimum {
+			report("should be at least %v", *schema.Minimum)
+		}
+		if schema.Maximum != nil && float > *schema.Maximum {
+			report("should be at most %v", *schema.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			report("should be a boolean")
+		}
+	}
+}
+
+func checkFormat(format, value string) error {
+	var err error
+	switch format {
+	case "email":
+		_, err = mail.ParseAddress(value)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "date":
+		_, err = time.Parse("2006-01-02", value)
+	}
+	return err
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package passwords
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BreachList tells how often a password appeared in known breaches.
+type BreachList interface {
+	Occurrences(ctx context.Context, password string) (int, error)
+}
+
+// RangeDirectory is a local copy of the Pwned Passwords k-anonymity range files, as produced by the
+// haveibeenpwned downloader: one file per 5 character SHA-1 prefix, e.g. 21BD1.txt, holding
+// "SUFFIX:COUNT" lines. Only the file of the password's prefix is read, so the list can be huge
+// while a lookup stays cheap.
+type RangeDirectory struct {
+	Dir string
+}
+
+func (d RangeDirectory) Occurrences(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+
+	file, err := os.Open(filepath.Join(d.Dir, prefix+".txt"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		separator := strings.IndexByte(line, ':')
+		if separator < 0 || !strings.EqualFold(line[:separator], suffix) {
+			continue
+		}
+		count, err := strconv.Atoi(line[separator+1:])
+		if err != nil {
+			return 1, nil
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,122 @@
+package passwords
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ntwarijoshua/siena/internal/i18n"
+)
+
+// Policy describes what a password must look like. Checks are ordered from cheap to expensive.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// MaxBytes caps the encoded length on top of MaxLength, for hashers that only read so many bytes.
+	// See Hasher.MaxPasswordBytes.
+	MaxBytes int
+	// MinCharacterClasses out of lowercase, uppercase, digits and symbols.
+	MinCharacterClasses int
+	// Denylist holds lowercased passwords that are refused outright, on top of CommonPasswords.
+	Denylist map[string]bool
+	// Breaches is optional, passwords seen in breaches at least MaxBreachOccurrences times are refused.
+	Breaches             BreachList
+	MaxBreachOccurrences int
+}
+
+// Violation explains why a password was refused.
+type Violation struct {
+	Rule    string
+	Message string
+	// Template is Message with the limits it names left as placeholders, {0} for the first of Params,
+	// the key its translations are looked up by.
+	Template string
+	Params   []string
+}
+
+func violation(rule, template string, params ...int) *Violation {
+	v := &Violation{Rule: rule, Template: template}
+	for _, param := range params {
+		v.Params = append(v.Params, strconv.Itoa(param))
+	}
+	v.Message = i18n.Format(template, v.Params...)
+	return v
+}
+
+// Check returns the first rule password breaks. personal holds things like the email and names of
+// the account which the password must not be built from.
+func (p Policy) Check(ctx context.Context, password string, personal ...string) (*Violation, error) {
+	length := len([]rune(password))
+	if length < p.MinLength {
+		return violation("min_length", "password must be at least {0} characters long", p.MinLength), nil
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return violation("max_length", "password must be at most {0} characters long", p.MaxLength), nil
+	}
+	if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		// accented letters and symbols take several bytes, the password is short enough in characters
+		return violation("max_length", "password is too long, use fewer accented letters or symbols"), nil
+	}
+	if characterClasses(password) < p.MinCharacterClasses {
+		return violation("complexity",
+			"password must mix at least {0} of lowercase letters, uppercase letters, digits and symbols",
+			p.MinCharacterClasses), nil
+	}
+	lowered := strings.ToLower(password)
+	if CommonPasswords[lowered] || p.Denylist[lowered] {
+		return violation("common", "password is too common"), nil
+	}
+	if similarToPersonalInfo(lowered, personal) {
+		return violation("personal_info", "password must not contain your email or name"), nil
+	}
+	if p.Breaches != nil {
+		occurrences, err := p.Breaches.Occurrences(ctx, password)
+		if err != nil {
+			return nil, err
+		}
+		if occurrences >= p.MaxBreachOccurrences {
+			return violation("breached", "password has appeared in a data breach, choose another one"), nil
+		}
+	}
+	return nil, nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// similarToPersonalInfo looks for the local part of emails and every name of at least 3 characters
+// in the password.
+func similarToPersonalInfo(password string, personal []string) bool {
+	for _, info := range personal {
+		info = strings.ToLower(strings.TrimSpace(info))
+		if at := strings.IndexByte(info, '@'); at >= 0 {
+			info = info[:at]
+		}
+		for _, token := range strings.FieldsFunc(info, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len(token) >= 3 && strings.Contains(password, token) {
+				return true
+			}
+		}
+		if len(info) >= 3 && strings.Contains(password, info) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryWindow struct {
+	start    time.Time
+	previous int
+	current  int
+}
+
+// MemoryStore keeps counters in process. It is only accurate when a single api instance is running.
+type MemoryStore struct {
+	mutex     sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: map[string]*memoryWindow{}}
+}
+
+func (s *MemoryStore) Hit(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	windowStart := now.Truncate(limit.Period)
+	window, ok := s.windows[key]
+	switch {
+	case !ok:
+		window = &memoryWindow{start: windowStart}
+		s.windows[key] = window
+	case window.start.Equal(windowStart.Add(-limit.Period)):
+		window.previous, window.current, window.start = window.current, 0, windowStart
+	case !window.start.Equal(windowStart):
+		window.previous, window.current, window.start = 0, 0, windowStart
+	}
+	window.current++
+	s.sweep(now, limit.Period)
+	return evaluate(limit, now, window.previous, window.current), nil
+}
+
+// sweep drops keys that haven't been hit for two periods so the map doesn't grow forever.
+func (s *MemoryStore) sweep(now time.Time, period time.Duration) {
+	if now.Sub(s.lastSweep) < period {
+		return
+	}
+	s.lastSweep = now
+	for key, window := range s.windows {
+		if now.Sub(window.start) > 2*period {
+			delete(s.windows, key)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows Requests per Period for a single key.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+func PerMinute(requests int) Limit {
+	return Limit{Requests: requests, Period: time.Minute}
+}
+
+func PerHour(requests int) Limit {
+	return Limit{Requests: requests, Period: time.Hour}
+}
+
+// Result describes the state of a key after a hit, in the terms of the RateLimit-* headers.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the current window ends.
+	Reset time.Duration
+}
+
+// Store records hits for a key and decides whether they are within limit. Stores implement a
+// sliding window counter: the hits of the current fixed window are added to the hits of the
+// previous one weighted by how much of it still overlaps the sliding window.
+//
+// MemoryStore keeps its counters in process, SQLStore shares them between instances through
+// postgres. Anything able to atomically increment a counter with an expiry, e.g. redis INCR and
+// EXPIRE, can implement the same contract.
+type Store interface {
+	Hit(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// evaluate applies the sliding window estimate once the store has counted the hit.
+func evaluate(limit Limit, now time.Time, previous, current int) Result {
+	windowStart := now.Truncate(limit.Period)
+	elapsed := now.Sub(windowStart)
+	weight := float64(limit.Period-elapsed) / float64(limit.Period)
+	estimated := int(math.Ceil(float64(previous)*weight)) + current
+
+	remaining := limit.Requests - estimated
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   estimated <= limit.Requests,
+		Limit:     limit.Requests,
+		Remaining: remaining,
+		Reset:     limit.Period - elapsed,
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+// SQLStore shares counters between api instances through the rate_limits table.
+type SQLStore struct {
+	Executor boil.ContextExecutor
+	// Retention is how long windows are kept before being purged, it must exceed twice the longest period.
+	Retention time.Duration
+
+	mutex     sync.Mutex
+	lastPurge time.Time
+}
+
+func NewSQLStore(executor boil.ContextExecutor) *SQLStore {
+	return &SQLStore{Executor: executor, Retention: 48 * time.Hour}
+}
+
+func (s *SQLStore) Hit(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	windowStart := now.Truncate(limit.Period)
+	var current int
+	err := s.Executor.QueryRowContext(ctx, `
+		INSERT INTO rate_limits (key, window_start, hits) VALUES ($1, $2, 1)
+		ON CONFLICT (key, window_start) DO UPDATE SET hits = rate_limits.hits + 1
+		RETURNING hits`, key, windowStart).Scan(&current)
+	if err != nil {
+		return Result{}, err
+	}
+	var previous int
+	err = s.Executor.QueryRowContext(ctx,
+		`SELECT hits FROM rate_limits WHERE key = $1 AND window_start = $2`,
+		key, windowStart.Add(-limit.Period)).Scan(&previous)
+	if err != nil && err != sql.ErrNoRows {
+		return Result{}, err
+	}
+	s.purge(ctx, now)
+	return evaluate(limit, now, previous, current), nil
+}
+
+// purge deletes old windows at most once a minute per instance.
+func (s *SQLStore) purge(ctx context.Context, now time.Time) {
+	s.mutex.Lock()
+	if now.Sub(s.lastPurge) < time.Minute {
+		s.mutex.Unlock()
+		return
+	}
+	s.lastPurge = now
+	s.mutex.Unlock()
+	_, _ = s.Executor.ExecContext(ctx, `DELETE FROM rate_limits WHERE window_start < $1`, now.Add(-s.Retention))
+}
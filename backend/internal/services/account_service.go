@@ -0,0 +1,537 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// AccountOptions decides how long a deleted account can still be restored and how data exports
+// are kept.
+type AccountOptions struct {
+	// DeletionGracePeriod is how long a deleted account stays restorable before it is purged.
+	DeletionGracePeriod time.Duration
+	// ExportDir is where export archives are written until they expire.
+	ExportDir string
+	// ExportLifetime is how long the download link of an export works.
+	ExportLifetime time.Duration
+}
+
+// AccountOptionsFromEnv reads ACCOUNT_DELETION_GRACE_DAYS, DATA_EXPORT_DIR and DATA_EXPORT_TTL_HOURS.
+func AccountOptionsFromEnv() AccountOptions {
+	options := AccountOptions{
+		DeletionGracePeriod: time.Duration(envInt("ACCOUNT_DELETION_GRACE_DAYS", 30)) * 24 * time.Hour,
+		ExportDir:           os.Getenv("DATA_EXPORT_DIR"),
+		ExportLifetime:      time.Duration(envInt("DATA_EXPORT_TTL_HOURS", 48)) * time.Hour,
+	}
+	if options.ExportDir == "" {
+		options.ExportDir = filepath.Join(os.TempDir(), "siena-exports")
+	}
+	return options
+}
+
+var (
+	ErrAccountAlreadyDeleted = ConflictError("This account is already scheduled for deletion", nil)
+	ErrInvalidRestoreLink    = ValidationError("The restore link is invalid or has expired",
+		FieldError{Field: "token", Message: "token is invalid"})
+	ErrInvalidExportLink = NotFoundError("The export is invalid or has expired", nil)
+	ErrEmailReused       = ConflictError("Another account uses this email address now, the account can't be restored", nil)
+)
+
+// AccountPurgeLock is the advisory lock held while purging, so that instances don't purge the same
+// accounts at once.
+const AccountPurgeLock = 0xacc0de1
+
+// purgedMailPayload replaces the payload of the mails sent to a purged account.
+const purgedMailPayload = `{"purged":true}`
+
+type AccountService struct {
+	options   AccountOptions
+	mails     *MailQueue
+	auditor   *AuditService
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+}
+
+// RequestDeletion deletes the account right away as far as logging in is concerned and schedules it
+// for purging once the grace period is over. Until then it can be restored with the link mailed to
+// the user.
+func (s *AccountService) RequestDeletion(ctx context.Context, user *models.User) (*models.AccountDeletion, error) {
+	if user.Deleted {
+		return nil, ErrAccountAlreadyDeleted
+	}
+	token, err := RandomToken()
+	if err != nil {
+		return nil, err
+	}
+	deletion := models.AccountDeletion{
+		UserID:           user.ID,
+		RestoreTokenHash: null.StringFrom(hashToken(token)),
+		PurgeAfter:       time.Now().Add(s.options.DeletionGracePeriod),
+		CreatedAt:        time.Now(),
+	}
+	err = s.dataLayer.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		user.Deleted = true
+		if _, err := user.Update(ctx, tx, boil.Whitelist(models.UserColumns.Deleted, models.UserColumns.UpdatedAt)); err != nil {
+			return err
+		}
+		return deletion.Insert(ctx, tx, boil.Infer())
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, "account.deletion_requested", user.ID)
+
+	message := UserTransactionMessage{
+		EmailAddress: user.Email,
+		Token:        token,
+		Subject:      "Your Siena account will be deleted",
+		Type:         models.SupportedMessageType["ACCOUNT_DELETION"],
+	}
+	if err = s.mails.Queue(ctx, &message); err != nil {
+		// the account is deleted either way, the user just can't change their mind by mail
+		logging.FromContext(ctx, s.logger).Errorf("Could not queue the account deletion mail %s", err)
+	}
+	return &deletion, nil
+}
+
+// Restore cancels a pending deletion given the token from the deletion mail.
+func (s *AccountService) Restore(ctx context.Context, token string) (*models.User, error) {
+	deletion, err := models.AccountDeletions(
+		qm.Where("restore_token_hash = ?", hashToken(token)),
+		qm.And("restored_at IS NULL AND purged_at IS NULL"),
+		qm.And("purge_after > ?", time.Now()),
+	).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidRestoreLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	user, err := models.ScopedUsers(models.OnlyDeleted, qm.Where("id = ?", deletion.UserID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidRestoreLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = s.dataLayer.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		deletion.RestoredAt = null.TimeFrom(time.Now())
+		deletion.RestoreTokenHash = null.String{}
+		if _, err := deletion.Update(ctx, tx, boil.Infer()); err != nil {
+			return err
+		}
+		user.Deleted = false
+		_, err := user.Update(ctx, tx, boil.Whitelist(models.UserColumns.Deleted, models.UserColumns.UpdatedAt))
+		return err
+	})
+	if violation, ok := violatedUnique(err); ok && violation.Constraint == models.UniqueEmailIndex {
+		// someone signed up with the address during the grace period, the deletion stays pending
+		return nil, ErrEmailReused
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, "account.restored", user.ID)
+	return user, nil
+}
+
+// PurgeDue purges every account whose grace period is over and removes expired exports. It returns
+// the number of accounts purged. Every instance of the api runs the purger, the ones that find
+// another purging return right away.
+func (s *AccountService) PurgeDue(ctx context.Context) (int, error) {
+	conn, err := s.dataLayer.DB.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	var locked bool
+	if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", AccountPurgeLock).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+	// the lock belongs to the connection, it has to be released before the connection goes back to the pool
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", AccountPurgeLock)
+	}()
+
+	if err := s.removeExpiredExports(ctx); err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("Could not remove expired data exports %s", err)
+	}
+	deletions, err := models.AccountDeletions(
+		qm.Where("restored_at IS NULL AND purged_at IS NULL"),
+		qm.And("purge_after <= ?", time.Now()),
+	).All(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, deletion := range deletions {
+		if err = s.purge(ctx, deletion); err != nil {
+			return purged, errors.Wrapf(err, "purging user %d", deletion.UserID)
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// purge removes the user and their profile, everything hanging off the user goes with it through
+// the foreign keys. Records keyed by email address are removed and the mails we sent are kept for
+// our statistics with their content wiped. Only the deletion record is left, without anything
+// that identifies the person.
+//
+// The audit trail is the exception: it is append-only and chained, so its events stay. They name the
+// account by id and its email address only by pseudonym (see EmailPseudonym), but keep the ip
+// addresses requests came from, as any security log does.
+func (s *AccountService) purge(ctx context.Context, deletion *models.AccountDeletion) error {
+	user, err := models.FindUser(ctx, s.dataLayer.Executor, deletion.UserID)
+	if err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return err
+	}
+	if user != nil {
+		if err = s.removeExportFiles(ctx, qm.Where("user_id = ?", user.ID)); err != nil {
+			return err
+		}
+	}
+	err = s.dataLayer.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		if user != nil {
+			if err := s.purgeEmailRecords(ctx, tx, user.Email); err != nil {
+				return err
+			}
+			if _, err := user.Delete(ctx, tx); err != nil {
+				return err
+			}
+			if _, err := models.Profiles(qm.Where("id = ?", user.ProfileID)).DeleteAll(ctx, tx); err != nil {
+				return err
+			}
+		}
+		deletion.PurgedAt = null.TimeFrom(time.Now())
+		deletion.RestoreTokenHash = null.String{}
+		_, err := deletion.Update(ctx, tx, boil.Infer())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if user != nil {
+		invalidateProfiles(ctx, user.ProfileID)
+	}
+	s.audit(ctx, "account.purged", deletion.UserID)
+	return nil
+}
+
+// purgeEmailRecords wipes what is keyed by the email address of a purged account. Once someone else
+// signed up with the address, those records are theirs too and are left alone.
+func (s *AccountService) purgeEmailRecords(ctx context.Context, tx boil.ContextExecutor, email string) error {
+	reused, err := models.ScopedUsers(models.Active, models.UserEmailIs(email)).Exists(ctx, tx)
+	if err != nil || reused {
+		return err
+	}
+	if _, err = models.MailerLogs(sentTo(email)).UpdateAll(ctx, tx, models.M{
+		models.MailerLogColumns.Payload:   purgedMailPayload,
+		models.MailerLogColumns.UpdatedAt: time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err = models.LoginAttempts(qm.Where("email = ?", email)).DeleteAll(ctx, tx); err != nil {
+		return err
+	}
+	_, err = models.AccountLockouts(
+		qm.Where("scope = ? AND subject = ?", LockoutScopeAccount, email),
+	).DeleteAll(ctx, tx)
+	return err
+}
+
+// Export assembles an archive of everything stored about user and mails them a link to download it.
+func (s *AccountService) Export(ctx context.Context, user *models.User) (*models.DataExport, error) {
+	archive, err := s.collect(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(s.options.ExportDir, 0700); err != nil {
+		return nil, err
+	}
+	file, err := ioutil.TempFile(s.options.ExportDir, "export-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	err = archive.WriteZip(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+
+	token, err := RandomToken()
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	export := models.DataExport{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		FilePath:  file.Name(),
+		ExpiresAt: time.Now().Add(s.options.ExportLifetime),
+		CreatedAt: time.Now(),
+	}
+	if err = export.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		_ = os.Remove(file.Name())
+		return nil, err
+	}
+	s.audit(ctx, "account.exported", user.ID)
+
+	message := UserTransactionMessage{
+		EmailAddress: user.Email,
+		Token:        token,
+		Subject:      "Your Siena data export is ready",
+		Type:         models.SupportedMessageType["DATA_EXPORT"],
+	}
+	if err = s.mails.Queue(ctx, &message); err != nil {
+		// the export is built, the user can ask for another one when the link doesn't arrive
+		logging.FromContext(ctx, s.logger).Errorf("Could not queue the data export mail %s", err)
+	}
+	return &export, nil
+}
+
+// OpenExport finds the export a download link points to. Links only work for the user the export
+// was made for, while it hasn't expired.
+func (s *AccountService) OpenExport(ctx context.Context, userID int, token string) (*models.DataExport, error) {
+	export, err := models.DataExports(
+		qm.Where("token_hash = ? AND user_id = ?", hashToken(token), userID),
+		qm.And("expires_at > ?", time.Now()),
+	).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidExportLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = os.Stat(export.FilePath); err != nil {
+		return nil, ErrInvalidExportLink
+	}
+	export.DownloadedAt = null.TimeFrom(time.Now())
+	_, err = export.Update(ctx, s.dataLayer.Executor, boil.Whitelist(models.DataExportColumns.DownloadedAt, models.DataExportColumns.UpdatedAt))
+	return export, err
+}
+
+// collect gathers the archive for user. Secrets such as the password hash, the MFA secret or api
+// key hashes are left out, they are ours rather than the user's data.
+func (s *AccountService) collect(ctx context.Context, user *models.User) (*AccountArchive, error) {
+	exec := s.dataLayer.Executor
+	archive := AccountArchive{
+		Account: ArchivedAccount{
+			ID:        user.ID,
+			Email:     user.Email,
+			Confirmed: user.Confirmed.Bool,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+		},
+		APIKeys:       []ArchivedAPIKey{},
+		LoginAttempts: []ArchivedLoginAttempt{},
+		Mails:         []ArchivedMail{},
+	}
+	role, err := models.FindRole(ctx, exec, user.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	archive.Account.Role = role.Slug
+	if archive.Profile, err = models.FindProfile(ctx, exec, user.ProfileID); err != nil {
+		return nil, err
+	}
+	if archive.Identities, err = models.UserIdentities(qm.Where("user_id = ?", user.ID), qm.OrderBy("id")).All(ctx, exec); err != nil {
+		return nil, err
+	}
+	if archive.Identities == nil {
+		archive.Identities = models.UserIdentitySlice{}
+	}
+	mfa, err := models.UserMfas(qm.Where("user_id = ? AND enabled = ?", user.ID, true)).One(ctx, exec)
+	if err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return nil, err
+	}
+	if mfa != nil {
+		archive.Account.MFAEnabledAt = null.TimeFrom(mfa.CreatedAt)
+	}
+
+	keys, err := models.APIKeys(qm.Where("user_id = ?", user.ID), qm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		archive.APIKeys = append(archive.APIKeys, ArchivedAPIKey{
+			Name:       key.Name,
+			Prefix:     APIKeyPrefix + key.Prefix,
+			Scopes:     strings.Fields(key.Scopes),
+			LastUsedAt: key.LastUsedAt,
+			LastUsedIP: key.LastUsedIP,
+			ExpiresAt:  key.ExpiresAt,
+			RevokedAt:  key.RevokedAt,
+			CreatedAt:  key.CreatedAt,
+		})
+	}
+
+	attempts, err := models.LoginAttempts(qm.Where("email = ?", user.Email), qm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, attempt := range attempts {
+		archive.LoginAttempts = append(archive.LoginAttempts, ArchivedLoginAttempt{
+			IPAddress:  attempt.IPAddress,
+			Successful: attempt.Successful,
+			CreatedAt:  attempt.CreatedAt,
+		})
+	}
+
+	mails, err := models.MailerLogs(sentTo(user.Email), qm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		return nil, err
+	}
+	for _, mail := range mails {
+		archive.Mails = append(archive.Mails, ArchivedMail{
+			Type:      mail.Type,
+			Status:    mail.Status,
+			CreatedAt: mail.CreatedAt,
+		})
+	}
+	return &archive, nil
+}
+
+// sentTo selects the mails sent to an address, mailer_logs only know it from the payload.
+func sentTo(email string) qm.QueryMod {
+	return qm.Where("payload::jsonb ->> 'email_address' = ?", email)
+}
+
+func (s *AccountService) removeExpiredExports(ctx context.Context) error {
+	if err := s.removeExportFiles(ctx, qm.Where("expires_at <= ?", time.Now())); err != nil {
+		return err
+	}
+	_, err := models.DataExports(qm.Where("expires_at <= ?", time.Now())).DeleteAll(ctx, s.dataLayer.Executor)
+	return err
+}
+
+func (s *AccountService) removeExportFiles(ctx context.Context, filter qm.QueryMod) error {
+	exports, err := models.DataExports(filter).All(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if err = os.Remove(export.FilePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *AccountService) audit(ctx context.Context, action string, userID int) {
+	targetType, targetID := UserTarget(userID)
+	s.auditor.Record(ctx, AuditEvent{Action: action, TargetType: targetType, TargetID: targetID})
+}
+
+// StartAccountPurger runs PurgeDue every interval until ctx is done.
+func StartAccountPurger(ctx context.Context, logger *logrus.Logger, accountService *AccountService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := accountService.PurgeDue(ctx)
+		if err != nil {
+			logger.Errorf("Account purge failed %s", err)
+		} else if purged > 0 {
+			logger.Infof("Purged %d deleted accounts", purged)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AccountArchive is everything a data export contains, one json file per field.
+type AccountArchive struct {
+	Account       ArchivedAccount
+	Profile       *models.Profile
+	Identities    models.UserIdentitySlice
+	APIKeys       []ArchivedAPIKey
+	LoginAttempts []ArchivedLoginAttempt
+	Mails         []ArchivedMail
+}
+
+type ArchivedAccount struct {
+	ID           int       `json:"id"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Confirmed    bool      `json:"confirmed"`
+	MFAEnabledAt null.Time `json:"mfa_enabled_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type ArchivedAPIKey struct {
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix"`
+	Scopes     []string    `json:"scopes"`
+	LastUsedAt null.Time   `json:"last_used_at"`
+	LastUsedIP null.String `json:"last_used_ip"`
+	ExpiresAt  null.Time   `json:"expires_at"`
+	RevokedAt  null.Time   `json:"revoked_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+type ArchivedLoginAttempt struct {
+	IPAddress  string    `json:"ip_address"`
+	Successful bool      `json:"successful"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ArchivedMail struct {
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WriteZip writes the archive to w as a zip of indented json files.
+func (a *AccountArchive) WriteZip(w io.Writer) error {
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"account.json", a.Account},
+		{"profile.json", a.Profile},
+		{"identities.json", a.Identities},
+		{"api_keys.json", a.APIKeys},
+		{"login_attempts.json", a.LoginAttempts},
+		{"mails.json", a.Mails},
+	}
+	archive := zip.NewWriter(w)
+	for _, file := range files {
+		entry, err := archive.Create(file.name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(entry)
+		encoder.SetIndent("", "  ")
+		if err = encoder.Encode(file.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
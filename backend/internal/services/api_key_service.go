@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Scopes an api key can be granted.
+const (
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+	ScopeAdminRead    = "admin:read"
+	ScopeAdminWrite   = "admin:write"
+)
+
+// RoleScopes maps every role onto the scopes its members may grant to their keys, a key can never
+// do more than its owner.
+var RoleScopes = map[string][]string{
+	MasterRoleSlug: {ScopeProfileRead, ScopeProfileWrite, ScopeAdminRead, ScopeAdminWrite},
+	ClientRoleSlug: {ScopeProfileRead, ScopeProfileWrite},
+	"user":         {ScopeProfileRead, ScopeProfileWrite},
+}
+
+const (
+	// APIKeyPrefix starts every key so they are easy to recognise, e.g. by secret scanners.
+	APIKeyPrefix = "siena_"
+	// apiKeyUsageResolution limits how often last use is written for a busy key.
+	apiKeyUsageResolution = time.Minute
+)
+
+var ErrInvalidAPIKey = UnauthorizedError("Invalid, expired or revoked api key", nil)
+
+// IssuedAPIKey is a newly created key. Secret holds the full key and is only available right after
+// creation, afterwards only its hash is known.
+type IssuedAPIKey struct {
+	Key    *models.APIKey
+	Secret string
+}
+
+type APIKeyService struct {
+	auditor   *AuditService
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+}
+
+// Create issues a key for owner, on behalf of issuer which is either the owner or an admin.
+// expiresAt is optional.
+func (s *APIKeyService) Create(ctx context.Context, owner, issuer *models.User, name string, scopes []string, expiresAt null.Time) (*IssuedAPIKey, error) {
+	// keys of an owner whose role was deleted can't be granted any scope
+	var roleSlug string
+	role, err := s.dataLayer.Roles().FindByID(ctx, owner.RoleID)
+	switch {
+	case err == nil:
+		roleSlug = role.Slug
+	case errors.Cause(err) != sql.ErrNoRows:
+		return nil, err
+	}
+	scopes, err = grantableScopes(roleSlug, scopes)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		return nil, ValidationError("The submitted data is invalid",
+			FieldError{Field: "expires_at", Message: "expires_at must be in the future"})
+	}
+
+	prefixBytes := make([]byte, 6)
+	if _, err = rand.Read(prefixBytes); err != nil {
+		return nil, err
+	}
+	secret, err := RandomToken()
+	if err != nil {
+		return nil, err
+	}
+	key := models.APIKey{
+		UserID:     owner.ID,
+		Name:       name,
+		Prefix:     hex.EncodeToString(prefixBytes),
+		SecretHash: hashToken(secret),
+		Scopes:     strings.Join(scopes, " "),
+		CreatedBy:  null.IntFrom(issuer.ID),
+		ExpiresAt:  expiresAt,
+	}
+	if err = key.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, "api_key.created", &key, issuer.ID)
+	return &IssuedAPIKey{Key: &key, Secret: APIKeyPrefix + key.Prefix + "_" + secret}, nil
+}
+
+// List returns the keys of a user, revoked ones included.
+func (s *APIKeyService) List(ctx context.Context, userID int) (models.APIKeySlice, error) {
+	return models.APIKeys(qm.Where("user_id = ?", userID), qm.OrderBy("created_at DESC")).All(ctx, s.dataLayer.Executor)
+}
+
+// Revoke disables a key for good. ownerID restricts the lookup to keys of that user, admins pass 0.
+func (s *APIKeyService) Revoke(ctx context.Context, keyID, ownerID, revokedBy int) error {
+	mods := []qm.QueryMod{qm.Where("id = ?", keyID)}
+	if ownerID != 0 {
+		mods = append(mods, qm.And("user_id = ?", ownerID))
+	}
+	key, err := models.APIKeys(mods...).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return NotFoundError("Api key not found", err)
+	}
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt.Valid {
+		return nil
+	}
+	key.RevokedAt = null.TimeFrom(time.Now())
+	if _, err = key.Update(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return err
+	}
+	s.audit(ctx, "api_key.revoked", key, revokedBy)
+	return nil
+}
+
+// VerifyAPIKey resolves a raw key to its owner and the stored key, and records its use.
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, rawKey, ip string) (*models.User, *models.APIKey, error) {
+	prefix, secret, ok := ParseAPIKey(rawKey)
+	if !ok {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	key, err := models.APIKeys(qm.Where("prefix = ?", prefix)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(key.SecretHash), []byte(hashToken(secret))) != 1 ||
+		key.RevokedAt.Valid || (key.ExpiresAt.Valid && !key.ExpiresAt.Time.After(now)) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	user, err := models.Users(qm.Where("id = ?", key.UserID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if user.Deleted || !user.Confirmed.Bool {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	if !key.LastUsedAt.Valid || now.Sub(key.LastUsedAt.Time) >= apiKeyUsageResolution || key.LastUsedIP.String != ip {
+		key.LastUsedAt = null.TimeFrom(now)
+		key.LastUsedIP = null.NewString(ip, ip != "")
+		if _, err = key.Update(ctx, s.dataLayer.Executor, boil.Whitelist(
+			models.APIKeyColumns.LastUsedAt, models.APIKeyColumns.LastUsedIP, models.APIKeyColumns.UpdatedAt,
+		)); err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("Could not record api key usage %s", err)
+		}
+	}
+	return user, key, nil
+}
+
+// ParseAPIKey splits a key of the form siena_<prefix>_<secret>.
+func ParseAPIKey(rawKey string) (prefix string, secret string, ok bool) {
+	if !strings.HasPrefix(rawKey, APIKeyPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(rawKey, APIKeyPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// grantableScopes validates the requested scopes against the role and returns them deduplicated.
+func grantableScopes(roleSlug string, requested []string) ([]string, error) {
+	allowed := map[string]bool{}
+	for _, scope := range RoleScopes[roleSlug] {
+		allowed[scope] = true
+	}
+	seen := map[string]bool{}
+	var scopes []string
+	for _, scope := range requested {
+		if !allowed[scope] {
+			return nil, ValidationError("The submitted data is invalid",
+				FieldError{Field: "scopes", Message: "scope {0} can not be granted"}.Naming(scope))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	sort.Strings(scopes)
+	return scopes, nil
+}
+
+func (s *APIKeyService) audit(ctx context.Context, action string, key *models.APIKey, actorID int) {
+	s.auditor.Record(ctx, AuditEvent{
+		Action:     action,
+		ActorID:    actorID,
+		TargetType: AuditTargetAPIKey,
+		TargetID:   strconv.Itoa(key.ID),
+		Metadata:   map[string]interface{}{"owner_id": key.UserID, "prefix": key.Prefix},
+	})
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/audit"
+	"github.com/ntwarijoshua/siena/internal/listing"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// Kinds of things an audit event can target.
+const (
+	AuditTargetUser     = "user"
+	AuditTargetEmail    = "email"
+	AuditTargetIP       = "ip"
+	AuditTargetAPIKey   = "api_key"
+	AuditTargetIdentity = "identity"
+	AuditTargetSystem   = "system"
+)
+
+const (
+	// auditChainLock is the advisory lock serializing appends, every event needs the hash of the one
+	// before it.
+	auditChainLock = 0x5a1e7a
+	// auditBatchSize is how many events are read at a time when walking the whole trail.
+	auditBatchSize = 1000
+	// MaxAuditPageSize caps how many events a single query returns.
+	MaxAuditPageSize = 500
+	// maxAuditTargetType and maxAuditTargetID are the sizes of the target columns.
+	maxAuditTargetType = 50
+	maxAuditTargetID   = 100
+)
+
+// AuditEvent is a security relevant action to record. The actor, ip address and user agent are taken
+// from the request in ctx, see audit.WithRequest, ActorID only needs to be set to override it.
+type AuditEvent struct {
+	Action     string
+	ActorID    int
+	TargetType string
+	TargetID   string
+	Metadata   map[string]interface{}
+}
+
+// EmailPseudonym is what audit events about an email address store as their target. The trail is
+// append-only and chained, so events can't be wiped when an account is purged and login failures
+// name addresses that never had an account: addresses are only stored hashed. Query hashes email
+// filters the same way.
+func EmailPseudonym(email string) string {
+	return hashToken(NormalizeEmail(email))
+}
+
+// UserTarget is the target of an event about a user account.
+func UserTarget(userID int) (string, string) {
+	return AuditTargetUser, strconv.Itoa(userID)
+}
+
+// AuditFilter narrows down audit queries, zero values don't filter.
+type AuditFilter struct {
+	ActorID int
+	// Action matches exactly, or as a prefix when it ends with a *, e.g. "login.*".
+	Action     string
+	TargetType string
+	TargetID   string
+	IPAddress  string
+	From       time.Time
+	To         time.Time
+	// BeforeID pages through results, it is the id of the last event of the previous page.
+	BeforeID int
+	Limit    int
+}
+
+func (f AuditFilter) queryMods() []qm.QueryMod {
+	var mods []qm.QueryMod
+	if f.ActorID != 0 {
+		mods = append(mods, qm.Where("actor_id = ?", f.ActorID))
+	}
+	if prefix := strings.TrimSuffix(f.Action, "*"); prefix != f.Action {
+		mods = append(mods, qm.Where("action LIKE ?", listing.EscapeLike(prefix)+"%"))
+	} else if f.Action != "" {
+		mods = append(mods, qm.Where("action = ?", f.Action))
+	}
+	if f.TargetType != "" {
+		mods = append(mods, qm.Where("target_type = ?", f.TargetType))
+	}
+	if f.TargetID != "" {
+		targetID := f.TargetID
+		if f.TargetType == AuditTargetEmail || strings.Contains(targetID, "@") {
+			targetID = EmailPseudonym(targetID)
+		}
+		mods = append(mods, qm.Where("target_id = ?", targetID))
+	}
+	if f.IPAddress != "" {
+		mods = append(mods, qm.Where("ip_address = ?", f.IPAddress))
+	}
+	if !f.From.IsZero() {
+		mods = append(mods, qm.Where("created_at >= ?", f.From))
+	}
+	if !f.To.IsZero() {
+		mods = append(mods, qm.Where("created_at < ?", f.To))
+	}
+	return mods
+}
+
+type AuditService struct {
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+}
+
+// Record appends event to the audit trail. Failing to do so is logged with the event but doesn't fail
+// the action being audited. A nil AuditService records nothing, for services tested on their own.
+func (s *AuditService) Record(ctx context.Context, event AuditEvent) {
+	if s == nil {
+		return
+	}
+	if err := s.append(ctx, event); err != nil {
+		logging.FromContext(ctx, s.logger).WithFields(logrus.Fields{
+			"audit_action": event.Action,
+			"actor_id":     event.ActorID,
+			"target_type":  event.TargetType,
+			"target_id":    event.TargetID,
+			"metadata":     event.Metadata,
+		}).Errorf("Could not record audit event %s", err)
+	}
+}
+
+func (s *AuditService) append(ctx context.Context, event AuditEvent) error {
+	request := audit.RequestFromContext(ctx)
+	if event.ActorID == 0 {
+		event.ActorID = request.ActorID
+	}
+	metadata := "{}"
+	if len(event.Metadata) > 0 {
+		raw, err := json.Marshal(event.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(raw)
+	}
+	if event.TargetType == AuditTargetEmail && event.TargetID != "" {
+		event.TargetID = EmailPseudonym(event.TargetID)
+	}
+	// an address that doesn't fit the column would lose the whole event, it is left out instead
+	ip, _ := NormalizeIP(request.IPAddress)
+	record := audit.Record{
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: truncate(event.TargetType, maxAuditTargetType),
+		TargetID:   truncate(event.TargetID, maxAuditTargetID),
+		IPAddress:  ip,
+		UserAgent:  request.UserAgent,
+		Metadata:   metadata,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	return s.dataLayer.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", auditChainLock); err != nil {
+			return err
+		}
+		prevHash := audit.GenesisHash
+		last, err := models.AuditEvents(qm.Select("hash"), qm.OrderBy("id DESC"), qm.Limit(1)).One(ctx, tx)
+		if err == nil {
+			prevHash = last.Hash
+		} else if errors.Cause(err) != sql.ErrNoRows {
+			return err
+		}
+		row := models.AuditEvent{
+			ActorID:    optionalInt(record.ActorID),
+			Action:     record.Action,
+			TargetType: optionalString(record.TargetType),
+			TargetID:   optionalString(record.TargetID),
+			IPAddress:  optionalString(record.IPAddress),
+			UserAgent:  optionalString(record.UserAgent),
+			Metadata:   record.Metadata,
+			PrevHash:   prevHash,
+			Hash:       audit.Hash(prevHash, record),
+			CreatedAt:  record.CreatedAt,
+		}
+		return row.Insert(ctx, tx, boil.Infer())
+	})
+}
+
+// Query returns a page of events matching filter, newest first.
+func (s *AuditService) Query(ctx context.Context, filter AuditFilter) (models.AuditEventSlice, error) {
+	if filter.Limit <= 0 || filter.Limit > MaxAuditPageSize {
+		filter.Limit = MaxAuditPageSize
+	}
+	mods := filter.queryMods()
+	if filter.BeforeID > 0 {
+		mods = append(mods, qm.Where("id < ?", filter.BeforeID))
+	}
+	mods = append(mods, qm.OrderBy("id DESC"), qm.Limit(filter.Limit))
+	return models.AuditEvents(mods...).All(ctx, s.dataLayer.Executor)
+}
+
+// Each calls fn with every event matching filter, oldest first. Events are read in batches so the
+// whole trail never has to fit in memory.
+func (s *AuditService) Each(ctx context.Context, filter AuditFilter, fn func(*models.AuditEvent) error) error {
+	afterID := 0
+	for {
+		mods := append(filter.queryMods(), qm.Where("id > ?", afterID), qm.OrderBy("id"), qm.Limit(auditBatchSize))
+		events, err := models.AuditEvents(mods...).All(ctx, s.dataLayer.Executor)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err = fn(event); err != nil {
+				return err
+			}
+		}
+		if len(events) < auditBatchSize {
+			return nil
+		}
+		afterID = events[len(events)-1].ID
+	}
+}
+
+// Verify recomputes the whole hash chain and reports the first event that doesn't match, if any.
+func (s *AuditService) Verify(ctx context.Context) (*audit.Verifier, error) {
+	verifier := audit.NewVerifier()
+	err := s.Each(ctx, AuditFilter{}, func(event *models.AuditEvent) error {
+		verifier.Check(AuditLink(event))
+		return nil
+	})
+	return verifier, err
+}
+
+// AuditLink is the hash chain link of a stored event.
+func AuditLink(event *models.AuditEvent) audit.Link {
+	return audit.Link{
+		ID:       event.ID,
+		PrevHash: event.PrevHash,
+		Hash:     event.Hash,
+		Record: audit.Record{
+			ActorID:    event.ActorID.Int,
+			Action:     event.Action,
+			TargetType: event.TargetType.String,
+			TargetID:   event.TargetID.String,
+			IPAddress:  event.IPAddress.String,
+			UserAgent:  event.UserAgent.String,
+			Metadata:   event.Metadata,
+			CreatedAt:  event.CreatedAt,
+		},
+	}
+}
+
+func optionalInt(value int) null.Int {
+	return null.NewInt(value, value != 0)
+}
+
+func optionalString(value string) null.String {
+	return null.NewString(value, value != "")
+}
+
+// truncate cuts value to max characters, as postgres counts them for VARCHAR columns.
+func truncate(value string, max int) string {
+	if runes := []rune(value); len(runes) > max {
+		return string(runes[:max])
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/cache"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/tracing"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+const (
+	// defaultCacheSize is how many entries the in-process cache holds unless CACHE_SIZE says otherwise.
+	defaultCacheSize = 10000
+	// lookupCacheTTL bounds how stale a cached row can get when it is written behind the api's back.
+	lookupCacheTTL = 10 * time.Minute
+)
+
+// NewCache returns the cache services read through: an in-process LRU of CACHE_SIZE entries, or
+// postgres when CACHE_STORE=postgres so every api instance sees the invalidations of the others.
+func NewCache(store *models.DataStore) cache.Cache {
+	if os.Getenv("CACHE_STORE") == "postgres" {
+		return cache.NewSQLCache(store.Executor)
+	}
+	size, err := strconv.Atoi(os.Getenv("CACHE_SIZE"))
+	if err != nil || size <= 0 {
+		size = defaultCacheSize
+	}
+	return cache.NewLRU(size)
+}
+
+func profileCacheKey(id int) string {
+	return "profiles:" + strconv.Itoa(id)
+}
+
+// writeHookPoints are the hook points rows are written at.
+var writeHookPoints = []boil.HookPoint{boil.AfterInsertHook, boil.AfterUpdateHook, boil.AfterDeleteHook, boil.AfterUpsertHook}
+
+// invalidation holds the caches model hooks drop written rows from. Hooks are global to the models
+// package, they are added once and go through every cache registered here.
+var invalidation struct {
+	once   sync.Once
+	mutex  sync.RWMutex
+	caches []cache.Cache
+	logger *logrus.Logger
+}
+
+// InvalidateOnWrite drops profiles from c as they are written through the models, so lookups never
+// serve a row older than the last write the api made. Hooks run before a transaction commits, and a
+// lookup in between would cache the old row again, so the hooks leave the rows written in one to
+// whoever commits it, see invalidateProfiles. Deleting with DeleteAll skips the hooks altogether.
+func InvalidateOnWrite(c cache.Cache, logger *logrus.Logger) {
+	invalidation.mutex.Lock()
+	invalidation.caches = append(invalidation.caches, c)
+	invalidation.logger = logger
+	invalidation.mutex.Unlock()
+
+	invalidation.once.Do(func() {
+		for _, hookPoint := range writeHookPoints {
+			models.AddProfileHook(hookPoint, func(ctx context.Context, exec boil.ContextExecutor, profile *models.Profile) error {
+				if !inTransaction(exec) {
+					invalidateProfiles(ctx, profile.ID)
+				}
+				return nil
+			})
+		}
+	})
+}
+
+// inTransaction reports whether exec is a transaction, traced or not.
+func inTransaction(exec boil.ContextExecutor) bool {
+	if traced, ok := exec.(*tracing.Executor); ok {
+		exec = traced.DB
+	}
+	_, ok := exec.(*sql.Tx)
+	return ok
+}
+
+// invalidateProfiles deletes the profiles with ids from every registered cache, transactions call it
+// once they commit. A failure doesn't fail the write that was made, the entries live until their ttl.
+func invalidateProfiles(ctx context.Context, ids ...int) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = profileCacheKey(id)
+	}
+	invalidation.mutex.RLock()
+	defer invalidation.mutex.RUnlock()
+	for _, c := range invalidation.caches {
+		if err := c.Delete(ctx, keys...); err != nil {
+			logging.FromContext(ctx, invalidation.logger).Errorf("Could not invalidate %v %s", keys, err)
+		}
+	}
+}
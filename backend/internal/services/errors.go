@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/ntwarijoshua/siena/internal/i18n"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// ErrorKind classifies service errors so the transport layer can decide how to report them.
+type ErrorKind string
+
+const (
+	KindNotFound     ErrorKind = "not_found"
+	KindConflict     ErrorKind = "conflict"
+	KindValidation   ErrorKind = "validation_failed"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindForbidden    ErrorKind = "forbidden"
+	KindTooMany      ErrorKind = "too_many_requests"
+	KindInternal     ErrorKind = "internal_error"
+)
+
+// FieldError describes what is wrong with a single input field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// source is the validator's error, which translates itself
+	source validator.FieldError
+	// template is the catalog key of messages naming something, see Naming
+	template string
+	params   []string
+}
+
+// Naming is ServiceError.Naming for the message of a field, "{0} should be a positive number".
+func (f FieldError) Naming(params ...string) FieldError {
+	f.template, f.params = f.Message, params
+	f.Message = i18n.Format(f.Message, params...)
+	return f
+}
+
+// ServiceError is the error every service returns for conditions the caller is expected to handle.
+// Message is safe to show to clients, Err holds the underlying cause and is never exposed.
+type ServiceError struct {
+	Kind    ErrorKind
+	Message string
+	Fields  []FieldError
+	Err     error
+	// template is the catalog key of messages naming something, see Naming
+	template string
+	params   []string
+}
+
+func (e *ServiceError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %s", e.Kind, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Naming fills the placeholders of the message of e in with params, {0} with the first, and returns
+// e. The message is translated by its template, "Could not sign in with {0}", rather than by what
+// it reads once filled in.
+func (e *ServiceError) Naming(params ...string) *ServiceError {
+	e.template, e.params = e.Message, params
+	e.Message = i18n.Format(e.Message, params...)
+	return e
+}
+
+func NotFoundError(message string, err error) *ServiceError {
+	return &ServiceError{Kind: KindNotFound, Message: message, Err: err}
+}
+
+func ConflictError(message string, err error, fields ...FieldError) *ServiceError {
+	return &ServiceError{Kind: KindConflict, Message: message, Fields: fields, Err: err}
+}
+
+func ValidationError(message string, fields ...FieldError) *ServiceError {
+	return &ServiceError{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+func UnauthorizedError(message string, err error) *ServiceError {
+	return &ServiceError{Kind: KindUnauthorized, Message: message, Err: err}
+}
+
+func ForbiddenError(message string) *ServiceError {
+	return &ServiceError{Kind: KindForbidden, Message: message}
+}
+
+func TooManyRequestsError(message string) *ServiceError {
+	return &ServiceError{Kind: KindTooMany, Message: message}
+}
+
+// AsServiceError unwraps err into a ServiceError. Unique violations no service mapped are still
+// conflicts, anything else unknown is reported as an internal error.
+func AsServiceError(err error) *ServiceError {
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr
+	}
+	if errors.As(constraintViolation(err), &serviceErr) {
+		return serviceErr
+	}
+	return &ServiceError{Kind: KindInternal, Message: "An unexpected error occurred", Err: err}
+}
+
+// IsKind reports whether err is a ServiceError of the given kind.
+func IsKind(err error, kind ErrorKind) bool {
+	var serviceErr *ServiceError
+	return errors.As(err, &serviceErr) && serviceErr.Kind == kind
+}
+
+// ErrEmailTaken is what signing up with the email of an active account fails with, whether the
+// is_unique validator or the unique index caught it.
+var ErrEmailTaken = ConflictError("An account with this email already exists", nil, emailTakenField)
+
+var emailTakenField = FieldError{Field: "email", Message: "email is already taken"}
+
+// uniqueConflicts are the conflicts the unique constraints clients can run into are reported with,
+// by constraint name.
+var uniqueConflicts = map[string]*ServiceError{
+	models.UniqueEmailIndex:                ErrEmailTaken,
+	"user_identities_provider_subject_key": ConflictError("This account is already linked to another user", nil),
+	"user_mfa_user_id_key":                 ConflictError("Two-factor authentication is already enabled", nil),
+}
+
+// violatedUnique returns the unique constraint postgres refused the write of err for.
+func violatedUnique(err error) (*pq.Error, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code.Name() == "unique_violation" {
+		return pqErr, true
+	}
+	return nil, false
+}
+
+// constraintViolation turns a violation of one of uniqueConflicts into the conflict it is reported
+// with, which holds err as its cause. A check done before writing can always be raced, the constraint
+// has the last word. Other errors, the violations of other constraints among them, are returned as
+// they are: they are bugs rather than something clients can fix, and their details name our columns.
+func constraintViolation(err error) error {
+	pqErr, ok := violatedUnique(err)
+	if !ok {
+		return err
+	}
+	if conflict, known := uniqueConflicts[pqErr.Constraint]; known {
+		return &ServiceError{Kind: KindConflict, Message: conflict.Message, Fields: conflict.Fields, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mailgun/mailgun-go/v3"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+
+	defaultHealthCheckTimeout = 2 * time.Second
+	defaultHealthCacheTTL     = 10 * time.Second
+)
+
+// HealthCheck probes a single dependency and returns an error when it is unavailable.
+type HealthCheck func(ctx context.Context) error
+
+type HealthCheckResult struct {
+	Status   string `json:"status"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status    string                       `json:"status"`
+	CheckedAt time.Time                    `json:"checked_at"`
+	Checks    map[string]HealthCheckResult `json:"checks"`
+}
+
+// Healthy reports whether every dependency check passed.
+func (r HealthReport) Healthy() bool {
+	return r.Status == HealthStatusUp
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+type HealthService struct {
+	checks   []namedHealthCheck
+	timeout  time.Duration
+	cacheTTL time.Duration
+	mutex    sync.Mutex
+	report   *HealthReport
+	store    *models.DataStore
+	logger   *logrus.Logger
+	context  context.Context
+}
+
+// RegisterCheck adds a named dependency check to the readiness report.
+func (hs *HealthService) RegisterCheck(name string, check HealthCheck) {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+	hs.checks = append(hs.checks, namedHealthCheck{name: name, check: check})
+	hs.report = nil
+}
+
+// Readiness runs every registered check concurrently, each bounded by its own timeout.
+// Results are cached for cacheTTL so that frequent probes don't hammer the dependencies. The checks
+// don't run on ctx: a probe that gives up would otherwise cache its own cancellation as "down".
+func (hs *HealthService) Readiness(ctx context.Context) HealthReport {
+	hs.mutex.Lock()
+	defer hs.mutex.Unlock()
+	if hs.report != nil && time.Since(hs.report.CheckedAt) < durationOr(hs.cacheTTL, defaultHealthCacheTTL) {
+		return *hs.report
+	}
+
+	report := HealthReport{
+		Status:    HealthStatusUp,
+		CheckedAt: time.Now(),
+		Checks:    make(map[string]HealthCheckResult, len(hs.checks)),
+	}
+	checkCtx := hs.context
+	if checkCtx == nil {
+		checkCtx = context.Background()
+	}
+	results := make([]HealthCheckResult, len(hs.checks))
+	var wg sync.WaitGroup
+	for i, c := range hs.checks {
+		wg.Add(1)
+		go func(i int, c namedHealthCheck) {
+			defer wg.Done()
+			results[i] = hs.runCheck(checkCtx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, c := range hs.checks {
+		if results[i].Status != HealthStatusUp {
+			report.Status = HealthStatusDown
+			logging.FromContext(ctx, hs.logger).Warnf("Health check %s failed: %s", c.name, results[i].Error)
+		}
+		report.Checks[c.name] = results[i]
+	}
+	hs.report = &report
+	return report
+}
+
+func (hs *HealthService) runCheck(ctx context.Context, c namedHealthCheck) HealthCheckResult {
+	timeout := durationOr(hs.timeout, defaultHealthCheckTimeout)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	errChan := make(chan error, 1)
+	go func() { errChan <- c.check(checkCtx) }()
+
+	var err error
+	select {
+	case err = <-errChan:
+	case <-checkCtx.Done():
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	result := HealthCheckResult{Status: HealthStatusUp, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// durationOr falls back to the default of an unset duration, a HealthService built without
+// NewHealthService only runs the checks registered on it.
+func durationOr(duration, fallback time.Duration) time.Duration {
+	if duration <= 0 {
+		return fallback
+	}
+	return duration
+}
+
+// DatabaseCheck pings the postgres connection pool.
+func DatabaseCheck(store *models.DataStore) HealthCheck {
+	return func(ctx context.Context) error {
+		return store.DB.PingContext(ctx)
+	}
+}
+
+// MigrationCheck verifies that liquibase has applied the changelog and isn't holding its lock.
+func MigrationCheck(store *models.DataStore) HealthCheck {
+	return func(ctx context.Context) error {
+		var applied int
+		if err := store.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM databasechangelog").Scan(&applied); err != nil {
+			return fmt.Errorf("could not read migration history: %s", err)
+		}
+		if applied == 0 {
+			return fmt.Errorf("no migrations have been applied")
+		}
+		var locked bool
+		err := store.DB.QueryRowContext(ctx, "SELECT COALESCE(bool_or(locked), false) FROM databasechangeloglock").Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("could not read migration lock: %s", err)
+		}
+		if locked {
+			return fmt.Errorf("migrations are currently running")
+		}
+		return nil
+	}
+}
+
+// HTTPPingCheck expects a 200 from the given url, e.g. nsqlookupd's /ping endpoint.
+func HTTPPingCheck(rawURL string) HealthCheck {
+	return func(ctx context.Context) error {
+		if rawURL == "" {
+			return fmt.Errorf("address is not configured")
+		}
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// TCPDialCheck verifies that a TCP connection can be opened to address.
+func TCPDialCheck(address string) HealthCheck {
+	return func(ctx context.Context) error {
+		if address == "" {
+			return fmt.Errorf("address is not configured")
+		}
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// mailTransportAddress turns the mailgun api base into a host:port pair we can dial.
+func mailTransportAddress(apiBase string) string {
+	parsed, err := url.Parse(apiBase)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+		if parsed.Scheme == "http" {
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(parsed.Hostname(), port)
+}
+
+func (hs *HealthService) registerDefaultChecks() {
+	hs.RegisterCheck("database", DatabaseCheck(hs.store))
+	hs.RegisterCheck("migrations", MigrationCheck(hs.store))
+	hs.RegisterCheck("nsqd", TCPDialCheck(os.Getenv("NSQD")))
+	hs.RegisterCheck("nsqlookupd", HTTPPingCheck(nsqLookupdPingURL(os.Getenv("NSQLOOKUPD"))))
+	hs.RegisterCheck("mail_transport", TCPDialCheck(mailTransportAddress(mailgun.APIBase)))
+}
+
+func nsqLookupdPingURL(address string) string {
+	if address == "" {
+		return ""
+	}
+	return fmt.Sprintf("http://%s/ping", address)
+}
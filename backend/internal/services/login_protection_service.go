@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+const (
+	LockoutScopeAccount = "account"
+	LockoutScopeIP      = "ip"
+)
+
+// LoginProtectionPolicy describes how many failures are tolerated and how hard we push back.
+type LoginProtectionPolicy struct {
+	// Window is how far back failed attempts are counted.
+	Window             time.Duration
+	MaxAccountFailures int
+	MaxIPFailures      int
+	// LockoutDuration doubles with every lockout of the same subject in the last day, up to MaxLockout.
+	LockoutDuration time.Duration
+	MaxLockout      time.Duration
+	// Failures past FreeAttempts are answered after DelayStep per extra failure, up to MaxDelay.
+	FreeAttempts int
+	DelayStep    time.Duration
+	MaxDelay     time.Duration
+}
+
+var DefaultLoginProtectionPolicy = LoginProtectionPolicy{
+	Window:             15 * time.Minute,
+	MaxAccountFailures: 5,
+	MaxIPFailures:      20,
+	LockoutDuration:    15 * time.Minute,
+	MaxLockout:         24 * time.Hour,
+	FreeAttempts:       2,
+	DelayStep:          500 * time.Millisecond,
+	MaxDelay:           5 * time.Second,
+}
+
+var ErrAccountLocked = TooManyRequestsError("Too many failed login attempts, try again later")
+
+// ErrUnknownClientIP refuses attempts whose address can't be told apart from others, they could
+// not be counted against it.
+var ErrUnknownClientIP = ForbiddenError("The address of this request could not be determined")
+
+// NormalizeIP is the form client addresses are stored and compared in, ok is false for anything
+// that isn't an address.
+func NormalizeIP(ip string) (string, bool) {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+type LoginProtectionService struct {
+	policy    LoginProtectionPolicy
+	mails     *MailQueue
+	auditor   *AuditService
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+	sleep     func(time.Duration)
+}
+
+// CheckAllowed refuses the attempt while the account or the client ip is locked out.
+func (s *LoginProtectionService) CheckAllowed(ctx context.Context, email, ip string) error {
+	email = NormalizeEmail(email)
+	ip, ok := NormalizeIP(ip)
+	if !ok {
+		return ErrUnknownClientIP
+	}
+	locked, err := models.AccountLockouts(
+		qm.Where("((scope = ? AND subject = ?) OR (scope = ? AND subject = ?))",
+			LockoutScopeAccount, email, LockoutScopeIP, ip),
+		qm.And("locked_until > ?", time.Now()),
+		qm.And("released_at IS NULL"),
+	).Exists(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// RecordSuccess stores a successful attempt, which resets the failure count of the account.
+func (s *LoginProtectionService) RecordSuccess(ctx context.Context, email, ip string) error {
+	email = NormalizeEmail(email)
+	ip, ok := NormalizeIP(ip)
+	if !ok {
+		return ErrUnknownClientIP
+	}
+	attempt := models.LoginAttempt{Email: email, IPAddress: ip, Successful: true}
+	return attempt.Insert(ctx, s.dataLayer.Executor, boil.Infer())
+}
+
+// RecordFailure stores a failed attempt, locks the account or ip once they cross the policy
+// thresholds and slows the caller down progressively. An error means the failure wasn't counted,
+// the attempt should be refused rather than answered.
+func (s *LoginProtectionService) RecordFailure(ctx context.Context, email, ip string) error {
+	email = NormalizeEmail(email)
+	ip, ok := NormalizeIP(ip)
+	if !ok {
+		return ErrUnknownClientIP
+	}
+	attempt := models.LoginAttempt{Email: email, IPAddress: ip}
+	if err := attempt.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return err
+	}
+	since := time.Now().Add(-s.policy.Window)
+
+	accountFailures, err := s.failuresSince(ctx, qm.Where("email = ?", email), since, email)
+	if err != nil {
+		return err
+	}
+	ipFailures, err := s.failuresSince(ctx, qm.Where("ip_address = ?", ip), since, "")
+	if err != nil {
+		return err
+	}
+
+	if accountFailures >= int64(s.policy.MaxAccountFailures) {
+		if err = s.lock(ctx, LockoutScopeAccount, email, ip); err != nil {
+			return err
+		}
+	}
+	if ipFailures >= int64(s.policy.MaxIPFailures) {
+		if err = s.lock(ctx, LockoutScopeIP, ip, ip); err != nil {
+			return err
+		}
+	}
+	s.sleep(s.delayFor(accountFailures))
+	return nil
+}
+
+// Unlock releases an account lockout using the token mailed to the account owner. Only the hash of
+// the token is stored so it is looked up by hash. Following the link proves the owner reads the
+// mailbox of the account, which counts as a successful login from ip: the failures that led to the
+// lockout are forgiven rather than locking the account again on the next typo.
+func (s *LoginProtectionService) Unlock(ctx context.Context, token, ip string) error {
+	ip, ok := NormalizeIP(ip)
+	if !ok {
+		return ErrUnknownClientIP
+	}
+	lockout, err := models.AccountLockouts(
+		qm.Where("scope = ? AND unlock_token = ?", LockoutScopeAccount, hashToken(token)),
+		qm.And("released_at IS NULL"),
+	).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return ValidationError("The unlock link is invalid", FieldError{Field: "token", Message: "token is invalid"})
+	}
+	if err != nil {
+		return err
+	}
+	err = s.dataLayer.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		lockout.ReleasedAt = null.TimeFrom(time.Now())
+		lockout.UnlockToken = null.String{}
+		if _, err := lockout.Update(ctx, tx, boil.Infer()); err != nil {
+			return err
+		}
+		reset := models.LoginAttempt{Email: lockout.Subject, IPAddress: ip, Successful: true}
+		return reset.Insert(ctx, tx, boil.Infer())
+	})
+	if err != nil {
+		return err
+	}
+	s.auditor.Record(ctx, AuditEvent{Action: "login.unlocked", TargetType: AuditTargetEmail, TargetID: lockout.Subject})
+	return nil
+}
+
+// SlowDownWith replaces time.Sleep as the way clients are held back after failing, tests use it to
+// see the delays without waiting for them.
+func (s *LoginProtectionService) SlowDownWith(sleep func(time.Duration)) {
+	s.sleep = sleep
+}
+
+// failuresSince counts failed attempts matching filter after since. When email is set, only failures
+// after the last successful login of that account are counted.
+func (s *LoginProtectionService) failuresSince(ctx context.Context, filter qm.QueryMod, since time.Time, email string) (int64, error) {
+	if email != "" {
+		lastSuccess, err := models.LoginAttempts(
+			qm.Where("email = ? AND successful = ?", email, true),
+			qm.OrderBy("created_at DESC"),
+		).One(ctx, s.dataLayer.Executor)
+		if err != nil && errors.Cause(err) != sql.ErrNoRows {
+			return 0, err
+		}
+		if lastSuccess != nil && lastSuccess.CreatedAt.After(since) {
+			since = lastSuccess.CreatedAt
+		}
+	}
+	return models.LoginAttempts(
+		filter,
+		qm.And("successful = ?", false),
+		qm.And("created_at > ?", since),
+	).Count(ctx, s.dataLayer.Executor)
+}
+
+func (s *LoginProtectionService) lock(ctx context.Context, scope, subject, ip string) error {
+	previous, err := models.AccountLockouts(
+		qm.Where("scope = ? AND subject = ?", scope, subject),
+		qm.And("created_at > ?", time.Now().Add(-24*time.Hour)),
+	).Count(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return err
+	}
+	duration := s.policy.LockoutDuration << uint(previous)
+	if duration > s.policy.MaxLockout || duration <= 0 {
+		duration = s.policy.MaxLockout
+	}
+
+	lockout := models.AccountLockout{
+		Scope:       scope,
+		Subject:     subject,
+		LockedUntil: time.Now().Add(duration),
+	}
+	var unlockToken string
+	if scope == LockoutScopeAccount {
+		if unlockToken, err = RandomToken(); err != nil {
+			return err
+		}
+		lockout.UnlockToken = null.StringFrom(hashToken(unlockToken))
+	}
+	if err = lockout.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return err
+	}
+
+	targetType := AuditTargetEmail
+	if scope == LockoutScopeIP {
+		targetType = AuditTargetIP
+	}
+	s.auditor.Record(ctx, AuditEvent{
+		Action:     "login.lockout",
+		TargetType: targetType,
+		TargetID:   subject,
+		Metadata:   map[string]interface{}{"locked_until": lockout.LockedUntil},
+	})
+
+	if scope == LockoutScopeAccount {
+		s.mailUnlockLink(ctx, subject, unlockToken)
+	}
+	return nil
+}
+
+// mailUnlockLink lets the owner of a locked account unlock it. Nothing is sent for unknown emails.
+func (s *LoginProtectionService) mailUnlockLink(ctx context.Context, email string, token string) {
+	exists, err := models.ScopedUsers(models.Active, models.UserEmailIs(email)).Exists(ctx, s.dataLayer.Executor)
+	if err != nil || !exists {
+		return
+	}
+	message := UserTransactionMessage{
+		EmailAddress: email,
+		Token:        token,
+		Subject:      "Your account has been locked",
+		Type:         models.SupportedMessageType["ACCOUNT_UNLOCK"],
+	}
+	if err = s.mails.Queue(ctx, &message); err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("Could not queue account unlock mail %s", err)
+	}
+}
+
+func (s *LoginProtectionService) delayFor(failures int64) time.Duration {
+	extra := failures - int64(s.policy.FreeAttempts)
+	if extra <= 0 {
+		return 0
+	}
+	delay := time.Duration(extra) * s.policy.DelayStep
+	if delay > s.policy.MaxDelay {
+		return s.policy.MaxDelay
+	}
+	return delay
+}
+
+// RandomToken returns 32 random bytes, hex encoded.
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken is used for single use tokens we mail out so that a database leak doesn't expose them.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MagicLinkOptions decides how long a link is valid and who may redeem it. Binding is off by default
+// since people often request the link on one device and open the mail on another.
+type MagicLinkOptions struct {
+	Lifetime time.Duration
+	// BindIP only accepts the link from the ip address that requested it.
+	BindIP bool
+	// BindDevice only accepts the link from the device that requested it, see DeviceID in the handlers.
+	BindDevice bool
+}
+
+// MagicLinkOptionsFromEnv reads MAGIC_LINK_TTL_MINUTES, MAGIC_LINK_BIND_IP and MAGIC_LINK_BIND_DEVICE.
+func MagicLinkOptionsFromEnv() MagicLinkOptions {
+	options := MagicLinkOptions{Lifetime: 15 * time.Minute}
+	if minutes, err := strconv.Atoi(os.Getenv("MAGIC_LINK_TTL_MINUTES")); err == nil && minutes > 0 {
+		options.Lifetime = time.Duration(minutes) * time.Minute
+	}
+	options.BindIP, _ = strconv.ParseBool(os.Getenv("MAGIC_LINK_BIND_IP"))
+	options.BindDevice, _ = strconv.ParseBool(os.Getenv("MAGIC_LINK_BIND_DEVICE"))
+	return options
+}
+
+var ErrInvalidMagicLink = UnauthorizedError("The login link is invalid or has expired", nil)
+
+type MagicLinkService struct {
+	options   MagicLinkOptions
+	mails     *MailQueue
+	auditor   *AuditService
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+	dispatch  func(task func())
+}
+
+// RequestLink mails a single use login link to email. Unknown, deleted and unconfirmed accounts are
+// silently ignored, and the account is only looked up once the request has been answered: returning
+// sooner for addresses without an account would tell who has one as surely as an error.
+func (s *MagicLinkService) RequestLink(ctx context.Context, email, ip, deviceID string) {
+	detached := logging.WithLogger(trace.ContextWithSpan(s.context, trace.SpanFromContext(ctx)), logging.FromContext(ctx, s.logger))
+	s.dispatch(func() {
+		if err := s.sendLink(detached, email, ip, deviceID); err != nil {
+			logging.FromContext(detached, s.logger).Errorf("Could not send the login link %s", err)
+		}
+	})
+}
+
+// DispatchWith replaces the goroutine links are sent from, tests use it to send them before
+// RequestLink returns.
+func (s *MagicLinkService) DispatchWith(dispatch func(task func())) {
+	s.dispatch = dispatch
+}
+
+func (s *MagicLinkService) sendLink(ctx context.Context, email, ip, deviceID string) error {
+	user, err := models.ScopedUsers(models.Active, models.UserEmailIs(NormalizeEmail(email))).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Confirmed.Bool {
+		return nil
+	}
+	if err = s.revokeOutstanding(ctx, user.ID); err != nil {
+		return err
+	}
+
+	token, err := RandomToken()
+	if err != nil {
+		return err
+	}
+	link := models.MagicLink{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.options.Lifetime),
+	}
+	if s.options.BindIP {
+		link.IPAddress = null.StringFrom(ip)
+	}
+	if s.options.BindDevice && deviceID != "" {
+		link.DeviceHash = null.StringFrom(hashToken(deviceID))
+	}
+	if err = link.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return err
+	}
+
+	message := UserTransactionMessage{
+		EmailAddress: user.Email,
+		Token:        token,
+		Subject:      "Your Siena login link",
+		Type:         models.SupportedMessageType["MAGIC_LINK"],
+	}
+	return s.mails.Queue(ctx, &message)
+}
+
+// Redeem exchanges a link token for the user it was issued to. The link can't be used again, even
+// when the binding checks fail, so a leaked link is burnt by the first attempt.
+func (s *MagicLinkService) Redeem(ctx context.Context, token, ip, deviceID string) (*models.User, error) {
+	// claimed in a single statement, concurrent attempts with the same token can't all get the link
+	var link models.MagicLink
+	err := queries.Raw(`
+		UPDATE magic_links SET used_at = now(), updated_at = now()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING *`, hashToken(token),
+	).Bind(ctx, s.dataLayer.Executor, &link)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidMagicLink
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if link.IPAddress.Valid && (ip == "" || link.IPAddress.String != ip) {
+		s.logRejected(ctx, &link, "ip_mismatch")
+		return nil, ErrInvalidMagicLink
+	}
+	if link.DeviceHash.Valid &&
+		subtle.ConstantTimeCompare([]byte(link.DeviceHash.String), []byte(hashToken(deviceID))) != 1 {
+		s.logRejected(ctx, &link, "device_mismatch")
+		return nil, ErrInvalidMagicLink
+	}
+
+	user, err := models.ScopedUsers(models.Active, qm.Where("id = ?", link.UserID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidMagicLink
+	}
+	return user, err
+}
+
+// revokeOutstanding burns the links still pending for a user, only the latest one mailed works.
+func (s *MagicLinkService) revokeOutstanding(ctx context.Context, userID int) error {
+	_, err := models.MagicLinks(
+		qm.Where("user_id = ? AND used_at IS NULL", userID),
+	).UpdateAll(ctx, s.dataLayer.Executor, models.M{"used_at": time.Now()})
+	return err
+}
+
+func (s *MagicLinkService) logRejected(ctx context.Context, link *models.MagicLink, reason string) {
+	targetType, targetID := UserTarget(link.UserID)
+	s.auditor.Record(ctx, AuditEvent{
+		Action:     "login.magic_link_rejected",
+		TargetType: targetType,
+		TargetID:   targetID,
+		Metadata:   map[string]interface{}{"reason": reason},
+	})
+}
@@ -6,83 +6,268 @@ import (
 	"fmt"
 	"github.com/mailgun/mailgun-go/v3"
 	"github.com/nsqio/go-nsq"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/metrics"
 	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/tracing"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/volatiletech/sqlboiler/boil"
-	"github.com/volatiletech/sqlboiler/queries/qm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"log"
+	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
 type MailerService struct {
-	MGClient *mailgun.MailgunImpl
-	Message  *mailgun.Message
-	logger   *logrus.Logger
-	store    *models.DataStore
-	context  context.Context
+	transport MailTransport
+	logger    *logrus.Logger
+	store     models.DataLayer
+	context   context.Context
+}
+
+// Mail is a templated mail ready to be handed to a MailTransport.
+type Mail struct {
+	From      string
+	To        string
+	Subject   string
+	Template  string
+	Variables map[string]string
+}
+
+// MailTransport delivers mails, mailgun in production. Tests swap it for a fake that keeps them.
+type MailTransport interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+type mailgunTransport struct {
+	client *mailgun.MailgunImpl
+}
+
+// NewMailgunTransport sends mails through the mailgun domain, using mailgun's stored templates.
+func NewMailgunTransport(domain string, apiKey string) MailTransport {
+	return mailgunTransport{client: mailgun.NewMailgun(domain, apiKey)}
+}
+
+func (t mailgunTransport) Send(ctx context.Context, mail Mail) error {
+	message := t.client.NewMessage(mail.From, mail.Subject, "", mail.To)
+	message.SetTemplate(mail.Template)
+	for name, value := range mail.Variables {
+		_ = message.AddVariable(name, value)
+	}
+	_, _, err := t.client.Send(ctx, message)
+	return err
+}
+
+// MessageBroker publishes messages for the consumers of a topic, nsqd in production.
+type MessageBroker interface {
+	Publish(topic string, body []byte) error
+}
+
+// NSQBroker publishes to the nsqd listening on Address.
+type NSQBroker struct {
+	Address string
+}
+
+func (b NSQBroker) Publish(topic string, body []byte) error {
+	producer, err := nsq.NewProducer(b.Address, nsq.NewConfig())
+	if err != nil {
+		return err
+	}
+	defer producer.Stop()
+	return producer.Publish(topic, body)
+}
+
+// MailQueue records outgoing mails in mailer_logs and hands them to the broker, the mailer consumer
+// sends them from there.
+type MailQueue struct {
+	store  models.DataLayer
+	broker MessageBroker
+}
+
+// mailTemplates maps every message type we send to its mailgun template and the link the template expects.
+var mailTemplates = map[string]struct {
+	template string
+	linkVar  string
+	linkPath string
+}{
+	models.SupportedMessageType["CONFIRMATION"]:     {"account-confirmation-email", "confirmation_link", ""},
+	models.SupportedMessageType["ACCOUNT_UNLOCK"]:   {"account-unlock-email", "unlock_link", "/unlock"},
+	models.SupportedMessageType["MAGIC_LINK"]:       {"magic-link-email", "login_link", "/login/magic"},
+	models.SupportedMessageType["ACCOUNT_DELETION"]: {"account-deletion-email", "restore_link", "/account/restore"},
+	models.SupportedMessageType["DATA_EXPORT"]:      {"data-export-email", "download_link", "/account/export"},
+}
+
+// bearerTokenTypes carry tokens that grant access on their own. They are kept out of mailer_logs,
+// only their hash is stored by the service that issued them.
+var bearerTokenTypes = map[string]bool{
+	models.SupportedMessageType["ACCOUNT_UNLOCK"]:   true,
+	models.SupportedMessageType["MAGIC_LINK"]:       true,
+	models.SupportedMessageType["ACCOUNT_DELETION"]: true,
+	models.SupportedMessageType["DATA_EXPORT"]:      true,
 }
 
 func (ms *MailerService) HandleMessage(m *nsq.Message) error {
+	return ms.Consume(string(m.ID[:]), m.Body)
+}
+
+// Consume sends the mail described by body, a message published on ConfirmationMailTopic.
+// An error means the message should be delivered again.
+func (ms *MailerService) Consume(messageID string, body []byte) error {
 	var message = UserTransactionMessage{}
-	if err := json.Unmarshal(m.Body, &message); err != nil {
-		ms.logger.Errorf("Failed to read message from nsq", err)
+	if err := json.Unmarshal(body, &message); err != nil {
+		logging.FromContext(ms.context, ms.logger).WithField("nsq_message_id", messageID).Errorf("Failed to read message from nsq %s", err)
 		return err
 	}
-	receiverEmail := message.EmailAddress
-	senderEmail := os.Getenv("MAIL_SENDER")
-	subject := message.Subject
-	ms.Message = ms.MGClient.NewMessage(senderEmail, subject, "", receiverEmail)
 
-	if message.Type == models.SupportedMessageType["CONFIRMATION"] {
-		return ms.sendAccountConfirmationEmail(message)
+	ctx, span := tracing.Tracer().Start(
+		tracing.Extract(ms.context, message.TraceContext),
+		ConfirmationMailTopic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nsq"),
+			semconv.MessagingDestinationName(ConfirmationMailTopic),
+			attribute.Int("siena.mail.tracking_id", message.TrackingId),
+			attribute.String("siena.mail.type", message.Type),
+		),
+	)
+	defer span.End()
+	ctx = logging.WithLogger(ctx, logging.FromContext(ctx, ms.logger).WithFields(logrus.Fields{
+		"nsq_message_id": messageID,
+		"tracking_id":    message.TrackingId,
+		"mail_type":      message.Type,
+	}))
+
+	if _, supported := mailTemplates[message.Type]; !supported {
+		logging.FromContext(ctx, ms.logger).Warnf("Dropping message of unsupported type %s", message.Type)
+		return nil
 	}
-	return nil
+	err := ms.sendMail(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
 }
 
-func (ms *MailerService) sendAccountConfirmationEmail(msg UserTransactionMessage) error {
-	ms.Message.SetTemplate("account-confirmation-email")
-	_ = ms.Message.AddVariable(
-		"confirmation_link",
-		fmt.Sprintf("https://foobar.com?id=%d&token=%s", msg.TrackingId, msg.Token))
-
-	_, _, err := ms.MGClient.Send(ms.context, ms.Message)
+func (ms *MailerService) sendMail(ctx context.Context, msg UserTransactionMessage) error {
+	template := mailTemplates[msg.Type]
+	err := ms.transport.Send(ctx, Mail{
+		From:     os.Getenv("MAIL_SENDER"),
+		To:       msg.EmailAddress,
+		Subject:  msg.Subject,
+		Template: template.template,
+		Variables: map[string]string{
+			template.linkVar: fmt.Sprintf(
+				"%s%s?id=%d&token=%s", AppURL(), template.linkPath, msg.TrackingId, url.QueryEscape(msg.Token)),
+		},
+	})
 	if err != nil {
-		ms.logger.Errorf("Error occured while trying to send out mail", err)
+		metrics.MailsSentTotal.WithLabelValues(msg.Type, "failure").Inc()
+		logging.FromContext(ctx, ms.logger).Errorf("Error occured while trying to send out mail %s", err)
 		return err
 	}
+	metrics.MailsSentTotal.WithLabelValues(msg.Type, "success").Inc()
 
 	// Update the mail log as sent
-	persistedMailLog, err := models.MailerLogs(qm.Where("id = ?", msg.TrackingId)).
-		One(ms.context, ms.store.DB)
+	persistedMailLog, err := ms.store.MailLogs().FindByID(ctx, msg.TrackingId)
 	if err != nil {
-		ms.logger.Errorf("Unexpected error occurred", errors.Cause(err))
+		logging.FromContext(ctx, ms.logger).Errorf("Unexpected error occurred %s", errors.Cause(err))
 		return err
 	}
 	persistedMailLog.Status = models.SupportedStatus["SENT"]
-	_, err = persistedMailLog.Update(ms.context, ms.store.DB, boil.Infer())
-	if err != nil {
-		ms.logger.Errorf("Unexpected error occurred", errors.Cause(err))
+	if err = ms.store.MailLogs().Update(ctx, persistedMailLog); err != nil {
+		logging.FromContext(ctx, ms.logger).Errorf("Unexpected error occurred %s", errors.Cause(err))
 		return err
 	}
 	return nil
 }
 
+// Queue records message in mailer_logs and publishes it for the mailer consumer.
+// message.TrackingId is set to the id of the mail log.
+func (q *MailQueue) Queue(ctx context.Context, message *UserTransactionMessage) error {
+	messageLog := models.MailerLog{
+		Type:      message.Type,
+		Payload:   loggedPayload(*message),
+		Status:    models.SupportedStatus["PROCESSING"],
+		CreatedAt: time.Now(),
+	}
+	if err := q.store.MailLogs().Insert(ctx, &messageLog); err != nil {
+		return err
+	}
+	message.TrackingId = messageLog.ID
+	if err := q.publish(ctx, message); err != nil {
+		return err
+	}
+	messageLog.Status = models.SupportedStatus["QUEUED"]
+	messageLog.Payload = loggedPayload(*message)
+	return q.store.MailLogs().Update(ctx, &messageLog)
+}
+
+func loggedPayload(message UserTransactionMessage) string {
+	if bearerTokenTypes[message.Type] {
+		message.Token = ""
+	}
+	rawPayload, _ := json.Marshal(message)
+	return string(rawPayload)
+}
+
+func (q *MailQueue) publish(ctx context.Context, message *UserTransactionMessage) (err error) {
+	ctx, span := tracing.Tracer().Start(ctx, ConfirmationMailTopic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("nsq"),
+			semconv.MessagingDestinationName(ConfirmationMailTopic),
+		),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+	message.TraceContext = tracing.Inject(ctx)
+	rawMSG, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return q.broker.Publish(ConfirmationMailTopic, rawMSG)
+}
+
+// AppURL is the address of the web frontend links in mails point to.
+func AppURL() string {
+	if base := os.Getenv("APP_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return "https://foobar.com"
+}
+
 func StartMailerConsumer(logger *logrus.Logger, messagesHandler *MailerService) error {
 	config := nsq.NewConfig()
 	consumer, err := nsq.NewConsumer(ConfirmationMailTopic, ConfirmationMailChannel, config)
 	if err != nil {
-		logger.Errorf("Error while trying to initialize consumer", err)
+		logger.Errorf("Error while trying to initialize consumer %s", err)
 		return err
 	}
+	// route the nsq client's own logs through our structured logger
+	consumer.SetLogger(log.New(logger.WriterLevel(logrus.InfoLevel), "", 0), nsq.LogLevelInfo)
 	consumer.ChangeMaxInFlight(200)
+	if err = metrics.RegisterNSQConsumer(ConfirmationMailTopic, consumer); err != nil {
+		logger.Errorf("Could not register consumer metrics %s", err)
+	}
 	consumer.AddConcurrentHandlers(
 		messagesHandler,
 		20,
 	)
 	if err = consumer.ConnectToNSQLookupd(os.Getenv("NSQLOOKUPD")); err != nil {
-		logger.Errorf("Error occurred while trying to connect to NSQD", err)
+		logger.Errorf("Error occurred while trying to connect to NSQD %s", err)
 		return err
 	}
 	shutdown := make(chan os.Signal, 2)
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/totp"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+const (
+	MFAIssuer = "Siena"
+	// MFAChallengeAudience marks the short lived token handed out between the password and the
+	// second factor, VerifyToken refuses it as an access token.
+	MFAChallengeAudience = "api.siena.mfa"
+	MFAChallengeLifetime = 5 * time.Minute
+	RecoveryCodeCount    = 10
+)
+
+var (
+	ErrInvalidMFACode   = UnauthorizedError("Invalid authentication code", nil)
+	ErrInvalidChallenge = UnauthorizedError("The login attempt expired, sign in again", nil)
+	ErrMFANotEnabled    = ValidationError("Two-factor authentication is not enabled")
+)
+
+// MFAEnrollment is what the user needs to add the account to an authenticator app.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	// ProvisioningURI is meant to be rendered as a QR code.
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFAService struct {
+	auditor   *AuditService
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+	now       func() time.Time
+}
+
+// Enabled reports whether the user has completed TOTP enrollment.
+func (s *MFAService) Enabled(ctx context.Context, userID int) (bool, error) {
+	return models.UserMfas(qm.Where("user_id = ? AND enabled = ?", userID, true)).Exists(ctx, s.dataLayer.Executor)
+}
+
+// BeginEnrollment generates a new secret for the user. It isn't used for logins until the user
+// proves they can generate codes with ConfirmEnrollment.
+func (s *MFAService) BeginEnrollment(ctx context.Context, user *models.User) (*MFAEnrollment, error) {
+	mfa, err := s.find(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa != nil && mfa.Enabled {
+		return nil, ConflictError("Two-factor authentication is already enabled", nil)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptMFASecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		mfa = &models.UserMfa{UserID: user.ID, Secret: encrypted}
+		err = mfa.Insert(ctx, s.dataLayer.Executor, boil.Infer())
+	} else {
+		mfa.Secret = encrypted
+		mfa.LastUsedStep = 0
+		_, err = mfa.Update(ctx, s.dataLayer.Executor, boil.Infer())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user submits a valid code and returns their recovery codes.
+// The codes are only stored hashed, this is the one time they can be shown.
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, user *models.User, code string) ([]string, error) {
+	mfa, err := s.find(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, ValidationError("Start the two-factor enrollment first")
+	}
+	if mfa.Enabled {
+		return nil, ConflictError("Two-factor authentication is already enabled", nil)
+	}
+	if err = s.verifyTOTP(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+	enabled, err := models.UserMfas(
+		qm.Where("id = ? AND enabled = ?", mfa.ID, false),
+	).UpdateAll(ctx, s.dataLayer.Executor, models.M{"enabled": true, "updated_at": s.now()})
+	if err != nil {
+		return nil, err
+	}
+	if enabled == 0 {
+		return nil, ConflictError("Two-factor authentication is already enabled", nil)
+	}
+	s.audit(ctx, "mfa.enabled", user.ID)
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// RegenerateRecoveryCodes invalidates the remaining recovery codes and issues new ones.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, user *models.User, code string) ([]string, error) {
+	if err := s.VerifyCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return s.replaceRecoveryCodes(ctx, user.ID)
+}
+
+// Disable turns MFA off for a user who can still produce a code.
+func (s *MFAService) Disable(ctx context.Context, user *models.User, code string) error {
+	if err := s.VerifyCode(ctx, user, code); err != nil {
+		return err
+	}
+	if err := s.remove(ctx, user.ID); err != nil {
+		return err
+	}
+	s.audit(ctx, "mfa.disabled", user.ID)
+	return nil
+}
+
+// Reset removes MFA from an account without a code, for admins helping users who lost their device.
+func (s *MFAService) Reset(ctx context.Context, userID int) error {
+	exists, err := models.Users(qm.Where("id = ?", userID)).Exists(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return NotFoundError("User not found", nil)
+	}
+	if err = s.remove(ctx, userID); err != nil {
+		return err
+	}
+	s.audit(ctx, "mfa.reset", userID)
+	return nil
+}
+
+// IssueChallenge signs the token proving the password step succeeded, to be exchanged together with
+// a code through VerifyChallenge.
+func (s *MFAService) IssueChallenge(user *models.User) (string, error) {
+	claims := CustomClaims{
+		user.ID,
+		user.Email,
+		time.Now(),
+		jwt.StandardClaims{
+			Audience:  MFAChallengeAudience,
+			ExpiresAt: time.Now().Add(MFAChallengeLifetime).Unix(),
+			Issuer:    "api.siena",
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(os.Getenv("JWT_SIGNING_KEY")))
+}
+
+// ChallengeUser returns the user a challenge token was issued to.
+func (s *MFAService) ChallengeUser(ctx context.Context, rawToken string) (*models.User, error) {
+	claims := CustomClaims{}
+	token, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(os.Getenv("JWT_SIGNING_KEY")), nil
+	})
+	if err != nil || !token.Valid || !claims.VerifyAudience(MFAChallengeAudience, true) {
+		return nil, ErrInvalidChallenge
+	}
+	user, err := models.ScopedUsers(models.Active, qm.Where("id = ?", claims.UserId)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidChallenge
+	}
+	return user, err
+}
+
+// VerifyCode accepts either a current TOTP code or one of the unused recovery codes.
+func (s *MFAService) VerifyCode(ctx context.Context, user *models.User, code string) error {
+	mfa, err := s.find(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if mfa == nil || !mfa.Enabled {
+		return ErrMFANotEnabled
+	}
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(ctx, mfa, code)
+	}
+	return s.useRecoveryCode(ctx, user.ID, code)
+}
+
+// verifyTOTP checks code and claims its step. The step only moves forward in a conditional update,
+// of two requests racing with the same code only one can claim it.
+func (s *MFAService) verifyTOTP(ctx context.Context, mfa *models.UserMfa, code string) error {
+	secret, err := decryptMFASecret(mfa.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := totp.Validate(secret, code, s.now())
+	if !ok || step <= mfa.LastUsedStep {
+		return ErrInvalidMFACode
+	}
+	claimed, err := models.UserMfas(
+		qm.Where("id = ? AND last_used_step < ?", mfa.ID, step),
+	).UpdateAll(ctx, s.dataLayer.Executor, models.M{"last_used_step": step, "updated_at": s.now()})
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrInvalidMFACode
+	}
+	mfa.LastUsedStep = step
+	return nil
+}
+
+// useRecoveryCode burns code, like TOTP steps it is claimed in a single conditional update.
+func (s *MFAService) useRecoveryCode(ctx context.Context, userID int, code string) error {
+	claimed, err := models.MfaRecoveryCodes(
+		qm.Where("user_id = ? AND code_hash = ?", userID, hashToken(normalizeRecoveryCode(code))),
+		qm.And("used_at IS NULL"),
+	).UpdateAll(ctx, s.dataLayer.Executor, models.M{"used_at": s.now()})
+	if err != nil {
+		return err
+	}
+	if claimed == 0 {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// UseClock replaces time.Now as the time codes are checked against, tests use it to step through
+// TOTP periods.
+func (s *MFAService) UseClock(now func() time.Time) {
+	s.now = now
+}
+
+func (s *MFAService) replaceRecoveryCodes(ctx context.Context, userID int) ([]string, error) {
+	if _, err := models.MfaRecoveryCodes(qm.Where("user_id = ?", userID)).DeleteAll(ctx, s.dataLayer.Executor); err != nil {
+		return nil, err
+	}
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := hex.EncodeToString(b)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		recoveryCode := models.MfaRecoveryCode{UserID: userID, CodeHash: hashToken(raw)}
+		if err := recoveryCode.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
+
+func (s *MFAService) remove(ctx context.Context, userID int) error {
+	if _, err := models.MfaRecoveryCodes(qm.Where("user_id = ?", userID)).DeleteAll(ctx, s.dataLayer.Executor); err != nil {
+		return err
+	}
+	_, err := models.UserMfas(qm.Where("user_id = ?", userID)).DeleteAll(ctx, s.dataLayer.Executor)
+	return err
+}
+
+func (s *MFAService) find(ctx context.Context, userID int) (*models.UserMfa, error) {
+	mfa, err := models.UserMfas(qm.Where("user_id = ?", userID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, nil
+	}
+	return mfa, err
+}
+
+func (s *MFAService) audit(ctx context.Context, action string, userID int) {
+	targetType, targetID := UserTarget(userID)
+	s.auditor.Record(ctx, AuditEvent{Action: action, TargetType: targetType, TargetID: targetID})
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// mfaCipher encrypts TOTP secrets at rest with MFA_ENCRYPTION_KEY, falling back to the JWT signing
+// key. Unlike passwords they can't be hashed since the server needs them to compute codes.
+func mfaCipher() (cipher.AEAD, error) {
+	key := os.Getenv("MFA_ENCRYPTION_KEY")
+	if key == "" {
+		key = os.Getenv("JWT_SIGNING_KEY")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptMFASecret(secret string) (string, error) {
+	aead, err := mfaCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+func decryptMFASecret(encrypted string) (string, error) {
+	aead, err := mfaCipher()
+	if err != nil {
+		return "", err
+	}
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(raw) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed mfa secret")
+	}
+	secret, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
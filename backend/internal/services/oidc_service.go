@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/oidc"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// OIDCStateLifetime is how long the user has to come back from the provider.
+const OIDCStateLifetime = 10 * time.Minute
+
+var ErrInvalidOIDCState = UnauthorizedError("The sign in attempt expired, start again", nil)
+
+// OIDCResult tells the caller what Complete did with the external identity.
+type OIDCResult struct {
+	User *models.User
+	// Linked is set when the identity was attached to an account that was already signed in.
+	Linked bool
+	// Created is set when a new account was signed up.
+	Created bool
+}
+
+// OIDCService signs users in with external OpenID providers and links those identities to accounts.
+// Providers are discovered on first use so that an unreachable provider doesn't keep the api from
+// starting.
+type OIDCService struct {
+	configs     map[string]oidc.Config
+	providers   map[string]*oidc.Provider
+	mutex       sync.Mutex
+	httpClient  *http.Client
+	userService *UserService
+	auditor     *AuditService
+	dataLayer   *models.DataStore
+	logger      *logrus.Logger
+	context     context.Context
+}
+
+// APIURL is the public address of this api, providers redirect back to it.
+func APIURL() string {
+	if base := os.Getenv("API_URL"); base != "" {
+		return strings.TrimRight(base, "/")
+	}
+	return "http://localhost:8090"
+}
+
+// Providers lists the names of the configured providers.
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.configs))
+	for name := range s.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Begin starts a sign in with provider and returns where to send the user, along with the state
+// the browser has to present again on the way back. userID is set to link the identity to an
+// account that is already signed in instead.
+func (s *OIDCService) Begin(ctx context.Context, providerName string, userID int) (authURL string, state string, err error) {
+	provider, err := s.provider(ctx, providerName)
+	if err != nil {
+		return "", "", err
+	}
+	if state, err = oidc.RandomString(); err != nil {
+		return "", "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		return "", "", err
+	}
+	loginState := models.OidcLoginState{
+		Provider:     providerName,
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(OIDCStateLifetime),
+	}
+	if userID != 0 {
+		loginState.UserID = null.IntFrom(userID)
+	}
+	if err = loginState.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return "", "", err
+	}
+	return provider.AuthCodeURL(state, nonce, challenge), state, nil
+}
+
+// Complete finishes the flow started by Begin: it redeems the code and signs in the owner of the
+// identity, linking or signing up an account when the identity is new.
+func (s *OIDCService) Complete(ctx context.Context, providerName, code, state string) (*OIDCResult, error) {
+	provider, err := s.provider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	loginState, err := s.redeemState(ctx, providerName, state)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := provider.Exchange(ctx, code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, UnauthorizedError("Could not sign in with {0}", err).Naming(providerName)
+	}
+	claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, UnauthorizedError("Could not sign in with {0}", err).Naming(providerName)
+	}
+
+	identity, err := models.UserIdentities(
+		qm.Where("provider = ? AND subject = ?", providerName, claims.Subject),
+	).One(ctx, s.dataLayer.Executor)
+	if err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return nil, err
+	}
+
+	if loginState.UserID.Valid {
+		if identity != nil && identity.UserID != loginState.UserID.Int {
+			return nil, ConflictError("This {0} account is already linked to another user", nil).Naming(providerName)
+		}
+		user, err := s.activeUser(ctx, loginState.UserID.Int)
+		if err != nil {
+			return nil, err
+		}
+		if identity == nil {
+			if err = s.link(ctx, user, providerName, claims); err != nil {
+				return nil, err
+			}
+		}
+		return &OIDCResult{User: user, Linked: true}, nil
+	}
+
+	if identity != nil {
+		user, err := s.activeUser(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return &OIDCResult{User: user}, nil
+	}
+	return s.signInNewIdentity(ctx, providerName, claims)
+}
+
+// Identities lists the external identities linked to a user.
+func (s *OIDCService) Identities(ctx context.Context, userID int) (models.UserIdentitySlice, error) {
+	return models.UserIdentities(qm.Where("user_id = ?", userID), qm.OrderBy("created_at")).All(ctx, s.dataLayer.Executor)
+}
+
+// Unlink removes one of the user's identities.
+func (s *OIDCService) Unlink(ctx context.Context, userID, identityID int) error {
+	identity, err := models.UserIdentities(qm.Where("id = ? AND user_id = ?", identityID, userID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return NotFoundError("Identity not found", err)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err = identity.Delete(ctx, s.dataLayer.Executor); err != nil {
+		return err
+	}
+	s.audit(ctx, "identity.unlinked", userID, identity.Provider)
+	return nil
+}
+
+// signInNewIdentity links the identity to the active account with the same email, provided the
+// provider verified that email, or signs up a new account seeded from the ID token. The email of a
+// deleted account is free to be used again, like it is for signing up with a password.
+func (s *OIDCService) signInNewIdentity(ctx context.Context, providerName string, claims *oidc.Claims) (*OIDCResult, error) {
+	if claims.Email == "" {
+		return nil, ValidationError("{0} did not share an email address",
+			FieldError{Field: "email", Message: "email is required"}).Naming(providerName)
+	}
+	existing, err := models.ScopedUsers(models.Active, models.UserEmailIs(NormalizeEmail(claims.Email))).One(ctx, s.dataLayer.Executor)
+	if err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return nil, err
+	}
+	if existing != nil {
+		// an unverified address could belong to anyone, taking over the account would be too easy
+		if !bool(claims.EmailVerified) {
+			return nil, ConflictError("An account with this email already exists, sign in to link {0}", nil,
+				FieldError{Field: "email", Message: "email is already taken"}).Naming(providerName)
+		}
+		if err = s.link(ctx, existing, providerName, claims); err != nil {
+			return nil, err
+		}
+		return &OIDCResult{User: existing}, nil
+	}
+
+	profile := models.Profile{
+		Names:   null.StringFrom(claims.DisplayName()),
+		TagLine: null.StringFrom(""),
+	}
+	if claims.Picture != "" {
+		profile.ProfilePhoto = null.StringFrom(claims.Picture)
+	}
+	user, err := s.userService.CreateFederatedUser(ctx, claims.Email, bool(claims.EmailVerified), profile)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.link(ctx, user, providerName, claims); err != nil {
+		return nil, err
+	}
+	return &OIDCResult{User: user, Created: true}, nil
+}
+
+// link attaches the identity to user and fills the blanks of the profile from the ID token.
+func (s *OIDCService) link(ctx context.Context, user *models.User, providerName string, claims *oidc.Claims) error {
+	identity := models.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+	}
+	if claims.Email != "" {
+		identity.Email = null.StringFrom(claims.Email)
+	}
+	if err := identity.Insert(ctx, s.dataLayer.Executor, boil.Infer()); err != nil {
+		return err
+	}
+	s.audit(ctx, "identity.linked", user.ID, providerName)
+
+	profile, err := user.Profile().One(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return err
+	}
+	changed := false
+	if profile.Names.String == "" && claims.DisplayName() != "" {
+		profile.Names, changed = null.StringFrom(claims.DisplayName()), true
+	}
+	if profile.ProfilePhoto.String == "" && claims.Picture != "" {
+		profile.ProfilePhoto, changed = null.StringFrom(claims.Picture), true
+	}
+	if changed {
+		_, err = profile.Update(ctx, s.dataLayer.Executor, boil.Infer())
+	}
+	return err
+}
+
+// redeemState consumes the state of a pending sign in, each state can only be used once. Finding
+// and burning it is a single statement so two callbacks racing with the same state can't both win.
+func (s *OIDCService) redeemState(ctx context.Context, providerName, state string) (*models.OidcLoginState, error) {
+	var loginState models.OidcLoginState
+	err := queries.Raw(`
+		UPDATE oidc_login_states SET used_at = now(), updated_at = now()
+		WHERE state_hash = $1 AND provider = $2 AND used_at IS NULL AND expires_at > now()
+		RETURNING *`, hashToken(state), providerName,
+	).Bind(ctx, s.dataLayer.Executor, &loginState)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
+
+func (s *OIDCService) activeUser(ctx context.Context, userID int) (*models.User, error) {
+	user, err := models.Users(qm.Where("id = ?", userID)).One(ctx, s.dataLayer.Executor)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, UnauthorizedError("This account no longer exists", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Deleted {
+		return nil, UnauthorizedError("This account has been deleted", nil)
+	}
+	return user, nil
+}
+
+// provider returns the discovered provider called name. Discovery goes over the network, it runs
+// outside the lock so that a slow provider doesn't hold up sign ins with the others.
+func (s *OIDCService) provider(ctx context.Context, name string) (*oidc.Provider, error) {
+	s.mutex.Lock()
+	provider, ok := s.providers[name]
+	s.mutex.Unlock()
+	if ok {
+		return provider, nil
+	}
+	config, ok := s.configs[name]
+	if !ok {
+		return nil, NotFoundError("Unknown identity provider {0}", nil).Naming(name)
+	}
+	provider, err := oidc.Discover(ctx, config, s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if discovered, ok := s.providers[name]; ok {
+		// another request discovered it meanwhile, keep the one everybody else got
+		return discovered, nil
+	}
+	s.providers[name] = provider
+	return provider, nil
+}
+
+func (s *OIDCService) audit(ctx context.Context, action string, userID int, provider string) {
+	targetType, targetID := UserTarget(userID)
+	s.auditor.Record(ctx, AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Metadata:   map[string]interface{}{"provider": provider},
+	})
+}
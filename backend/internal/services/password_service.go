@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ntwarijoshua/siena/internal/passwords"
+	"github.com/sirupsen/logrus"
+)
+
+// PasswordConfig gathers the password policy and the hashing settings.
+type PasswordConfig struct {
+	Policy passwords.Policy
+	Hasher passwords.Hasher
+}
+
+// PasswordConfigFromEnv reads the PASSWORD_* variables:
+//
+//	PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_MIN_CHARACTER_CLASSES
+//	PASSWORD_DENYLIST_FILE            extra refused passwords, one per line
+//	PASSWORD_BREACH_DIR               local Pwned Passwords range files, see passwords.RangeDirectory
+//	PASSWORD_MAX_BREACH_OCCURRENCES   how often a password may have been breached, defaults to 1
+//	PASSWORD_HASH_ALGORITHM           bcrypt or argon2id
+//	PASSWORD_BCRYPT_COST, PASSWORD_ARGON2_MEMORY_KB, PASSWORD_ARGON2_ITERATIONS, PASSWORD_ARGON2_PARALLELISM
+func PasswordConfigFromEnv(logger *logrus.Logger) PasswordConfig {
+	config := PasswordConfig{
+		Policy: passwords.Policy{
+			MinLength:            envInt("PASSWORD_MIN_LENGTH", 8),
+			MinCharacterClasses:  envInt("PASSWORD_MIN_CHARACTER_CLASSES", 2),
+			MaxBreachOccurrences: envInt("PASSWORD_MAX_BREACH_OCCURRENCES", 1),
+		},
+		Hasher: passwords.Hasher{
+			Algorithm:  passwords.AlgorithmBcrypt,
+			BcryptCost: envInt("PASSWORD_BCRYPT_COST", 10),
+			Argon2:     passwords.DefaultArgon2Params,
+		},
+	}
+	// bcrypt ignores everything past 72 bytes, NewPasswordService also caps the bytes for multibyte passwords
+	config.Policy.MaxLength = envInt("PASSWORD_MAX_LENGTH", 72)
+	if os.Getenv("PASSWORD_HASH_ALGORITHM") == passwords.AlgorithmArgon2id {
+		config.Hasher.Algorithm = passwords.AlgorithmArgon2id
+		config.Policy.MaxLength = envInt("PASSWORD_MAX_LENGTH", 128)
+		config.Hasher.Argon2.Memory = uint32(envInt("PASSWORD_ARGON2_MEMORY_KB", int(config.Hasher.Argon2.Memory)))
+		config.Hasher.Argon2.Iterations = uint32(envInt("PASSWORD_ARGON2_ITERATIONS", int(config.Hasher.Argon2.Iterations)))
+		config.Hasher.Argon2.Parallelism = uint8(envInt("PASSWORD_ARGON2_PARALLELISM", int(config.Hasher.Argon2.Parallelism)))
+	}
+	if dir := os.Getenv("PASSWORD_BREACH_DIR"); dir != "" {
+		config.Policy.Breaches = passwords.RangeDirectory{Dir: dir}
+	}
+	if path := os.Getenv("PASSWORD_DENYLIST_FILE"); path != "" {
+		denylist, err := readDenylist(path)
+		if err != nil {
+			logger.Errorf("Could not read the password denylist %s", err)
+		}
+		config.Policy.Denylist = denylist
+	}
+	return config
+}
+
+type PasswordService struct {
+	policy passwords.Policy
+	hasher passwords.Hasher
+	// dummyHash is verified against when there is no account, so that it costs the same as a real one.
+	dummyHash string
+	logger    *logrus.Logger
+}
+
+// Validate checks password against the policy. personal holds the email, names and such of the
+// account the password is for.
+func (s *PasswordService) Validate(ctx context.Context, password string, personal ...string) error {
+	violation, err := s.policy.Check(ctx, password, personal...)
+	if err != nil {
+		return err
+	}
+	if violation != nil {
+		return ValidationError("The submitted data is invalid", FieldError{Field: "password", Message: violation.Template}.Naming(violation.Params...))
+	}
+	return nil
+}
+
+func (s *PasswordService) Hash(password string) (string, error) {
+	return s.hasher.Hash(password)
+}
+
+// Verify checks password against hash, an empty hash is checked against a dummy hash and never
+// matches. needsRehash is set when hash doesn't use the current algorithm and cost.
+func (s *PasswordService) Verify(hash, password string) (needsRehash bool, err error) {
+	if hash == "" {
+		_, _ = s.hasher.Verify(s.dummyHash, password)
+		return false, passwords.ErrMismatch
+	}
+	return s.hasher.Verify(hash, password)
+}
+
+func readDenylist(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	denylist := map[string]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			denylist[strings.ToLower(line)] = true
+		}
+	}
+	return denylist, scanner.Err()
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ntwarijoshua/siena/internal/cache"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ProfileService reads profiles, through a cache since every dashboard page shows one.
+type ProfileService struct {
+	dataLayer models.DataLayer
+	cache     cache.Cache
+	logger    *logrus.Logger
+	context   context.Context
+}
+
+// Get returns the profile with id.
+func (s *ProfileService) Get(ctx context.Context, id int) (*models.Profile, error) {
+	var profile models.Profile
+	err := cache.Aside(ctx, s.cache, profileCacheKey(id), lookupCacheTTL, &profile, func() error {
+		found, err := s.dataLayer.Profiles().FindByID(ctx, id)
+		if errors.Cause(err) == sql.ErrNoRows {
+			return NotFoundError("Profile not found", err)
+		}
+		if err != nil {
+			return err
+		}
+		profile = *found
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
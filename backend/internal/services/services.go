@@ -3,10 +3,14 @@ package services
 import (
 	"context"
 	"fmt"
-	"github.com/mailgun/mailgun-go/v3"
+	"github.com/ntwarijoshua/siena/internal/cache"
 	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/oidc"
+	"github.com/ntwarijoshua/siena/internal/ratelimit"
 	"github.com/sirupsen/logrus"
+	"net/http"
 	"os"
+	"time"
 )
 
 type ServiceContainer struct {
@@ -24,38 +28,106 @@ func (sc *ServiceContainer) GetService(serviceName string) interface{} {
 	return service
 }
 
+// Register adds service under serviceName, replacing what was registered before. Tests use it to swap
+// in services built on fakes.
+func (sc *ServiceContainer) Register(serviceName string, service interface{}) {
+	if sc.services == nil {
+		sc.services = map[string]interface{}{}
+	}
+	sc.services[serviceName] = service
+}
+
 func (sc *ServiceContainer) BuildServiceContainer() {
+	auditService := NewAuditService(sc.Context, sc.Store, sc.Logger)
+	passwordService := NewPasswordService(PasswordConfigFromEnv(sc.Logger), sc.Logger)
+	mailTransport := NewMailgunTransport(os.Getenv("MAIL_GUN_DOMAIN"), os.Getenv("MAIL_GUN_API_KEY"))
+	mailQueue := NewMailQueue(sc.Store, NSQBroker{Address: os.Getenv("NSQD")})
+	userService := NewUserUserService(sc.Context, sc.Store, sc.Logger, passwordService, mailQueue, auditService)
+	lookups := NewCache(sc.Store)
+	InvalidateOnWrite(lookups, sc.Logger)
 	sc.services = map[string]interface{}{
-		"userService":       NewUserUserService(sc.Context, sc.Store, sc.Logger),
-		"mailerService":     NewMailerService(sc.Context, sc.Store, sc.Logger),
+		"auditService":      auditService,
+		"userService":       userService,
+		"profileService":    NewProfileService(sc.Context, sc.Store, sc.Logger, lookups),
+		"passwordService":   passwordService,
+		"mailerService":     NewMailerService(sc.Context, sc.Store, sc.Logger, mailTransport),
 		"validationService": NewValidationService(sc.Context, sc.Store, sc.Logger),
+		"healthService":     NewHealthService(sc.Context, sc.Store, sc.Logger),
+		"loginProtectionService": NewLoginProtectionService(
+			sc.Context, sc.Store, sc.Logger, DefaultLoginProtectionPolicy, mailQueue, auditService,
+		),
+		"rateLimitStore": NewRateLimitStore(sc.Store),
+		"mfaService":     NewMFAService(sc.Context, sc.Store, sc.Logger, auditService),
+		"magicLinkService": NewMagicLinkService(
+			sc.Context, sc.Store, sc.Logger, MagicLinkOptionsFromEnv(), mailQueue, auditService,
+		),
+		"apiKeyService":        NewAPIKeyService(sc.Context, sc.Store, sc.Logger, auditService),
+		"userDirectoryService": NewUserDirectoryService(sc.Context, sc.Store, sc.Logger),
+		"accountService": NewAccountService(
+			sc.Context, sc.Store, sc.Logger, AccountOptionsFromEnv(), mailQueue, auditService,
+		),
+		"oidcService": NewOIDCService(
+			sc.Context, sc.Store, sc.Logger, userService, auditService, oidc.ConfigsFromEnv(APIURL()+"/api/v1/auth/oidc"),
+		),
 	}
 }
 
-func NewUserUserService(context context.Context, store *models.DataStore, logger *logrus.Logger) *UserService {
+func NewUserUserService(context context.Context, store models.DataLayer, logger *logrus.Logger, passwords *PasswordService, mails *MailQueue, auditor *AuditService) *UserService {
 	return &UserService{
 		dataLayer: store,
 		userModel: &models.User{},
 		roleModel: &models.Role{},
+		passwords: passwords,
+		mails:     mails,
+		auditor:   auditor,
+		logger:    logger,
+		context:   context,
+	}
+}
+
+func NewProfileService(context context.Context, store models.DataLayer, logger *logrus.Logger, lookups cache.Cache) *ProfileService {
+	return &ProfileService{
+		dataLayer: store,
+		cache:     lookups,
 		logger:    logger,
 		context:   context,
 	}
 }
 
-func NewMailerService(context context.Context, store *models.DataStore, logger *logrus.Logger) *MailerService {
-	// resolving service dependencies
-	mailGunClientInstance := mailgun.NewMailgun(
-		os.Getenv("MAIL_GUN_DOMAIN"), os.Getenv("MAIL_GUN_API_KEY"),
-	)
+func NewPasswordService(config PasswordConfig, logger *logrus.Logger) *PasswordService {
+	dummyHash, err := config.Hasher.Hash("siena-dummy-password")
+	if err != nil {
+		logger.Errorf("Could not compute the dummy password hash %s", err)
+	}
+	// a password longer than the hasher reads would be accepted with only its start checked
+	if max := config.Hasher.MaxPasswordBytes(); max > 0 && (config.Policy.MaxBytes == 0 || config.Policy.MaxBytes > max) {
+		config.Policy.MaxBytes = max
+	}
+	return &PasswordService{
+		policy:    config.Policy,
+		hasher:    config.Hasher,
+		dummyHash: dummyHash,
+		logger:    logger,
+	}
+}
+
+func NewMailerService(context context.Context, store models.DataLayer, logger *logrus.Logger, transport MailTransport) *MailerService {
 	return &MailerService{
-		MGClient: mailGunClientInstance,
-		logger:   logger,
-		store:    store,
-		context:  context,
+		transport: transport,
+		logger:    logger,
+		store:     store,
+		context:   context,
 	}
 }
 
-func NewValidationService(context context.Context, store *models.DataStore, logger *logrus.Logger) *ValidationService {
+func NewMailQueue(store models.DataLayer, broker MessageBroker) *MailQueue {
+	return &MailQueue{
+		store:  store,
+		broker: broker,
+	}
+}
+
+func NewValidationService(context context.Context, store models.DataLayer, logger *logrus.Logger) *ValidationService {
 	validationService := ValidationService{
 		dataLayer: store,
 		logger:    logger,
@@ -64,3 +136,111 @@ func NewValidationService(context context.Context, store *models.DataStore, logg
 	validationService.InitializeValidator()
 	return &validationService
 }
+
+func NewHealthService(context context.Context, store *models.DataStore, logger *logrus.Logger) *HealthService {
+	healthService := HealthService{
+		timeout:  defaultHealthCheckTimeout,
+		cacheTTL: defaultHealthCacheTTL,
+		store:    store,
+		logger:   logger,
+		context:  context,
+	}
+	healthService.registerDefaultChecks()
+	return &healthService
+}
+
+// NewRateLimitStore shares rate limit counters through postgres when RATE_LIMIT_STORE=postgres,
+// which is needed as soon as more than one api instance runs. Counters are kept in memory otherwise.
+func NewRateLimitStore(store *models.DataStore) ratelimit.Store {
+	if os.Getenv("RATE_LIMIT_STORE") == "postgres" {
+		return ratelimit.NewSQLStore(store.Executor)
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+func NewMFAService(context context.Context, store *models.DataStore, logger *logrus.Logger, auditor *AuditService) *MFAService {
+	return &MFAService{
+		auditor:   auditor,
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+		now:       time.Now,
+	}
+}
+
+func NewMagicLinkService(context context.Context, store *models.DataStore, logger *logrus.Logger, options MagicLinkOptions, mails *MailQueue, auditor *AuditService) *MagicLinkService {
+	return &MagicLinkService{
+		options:   options,
+		mails:     mails,
+		auditor:   auditor,
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+		dispatch:  func(task func()) { go task() },
+	}
+}
+
+func NewOIDCService(context context.Context, store *models.DataStore, logger *logrus.Logger, userService *UserService, auditor *AuditService, configs []oidc.Config) *OIDCService {
+	oidcService := OIDCService{
+		configs:     make(map[string]oidc.Config, len(configs)),
+		providers:   map[string]*oidc.Provider{},
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		userService: userService,
+		auditor:     auditor,
+		dataLayer:   store,
+		logger:      logger,
+		context:     context,
+	}
+	for _, config := range configs {
+		oidcService.configs[config.Name] = config
+	}
+	return &oidcService
+}
+
+func NewAPIKeyService(context context.Context, store *models.DataStore, logger *logrus.Logger, auditor *AuditService) *APIKeyService {
+	return &APIKeyService{
+		auditor:   auditor,
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+	}
+}
+
+func NewUserDirectoryService(context context.Context, store *models.DataStore, logger *logrus.Logger) *UserDirectoryService {
+	return &UserDirectoryService{
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+	}
+}
+
+func NewAccountService(context context.Context, store *models.DataStore, logger *logrus.Logger, options AccountOptions, mails *MailQueue, auditor *AuditService) *AccountService {
+	return &AccountService{
+		options:   options,
+		mails:     mails,
+		auditor:   auditor,
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+	}
+}
+
+func NewAuditService(context context.Context, store *models.DataStore, logger *logrus.Logger) *AuditService {
+	return &AuditService{
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+	}
+}
+
+func NewLoginProtectionService(context context.Context, store *models.DataStore, logger *logrus.Logger, policy LoginProtectionPolicy, mails *MailQueue, auditor *AuditService) *LoginProtectionService {
+	return &LoginProtectionService{
+		policy:    policy,
+		mails:     mails,
+		auditor:   auditor,
+		dataLayer: store,
+		logger:    logger,
+		context:   context,
+		sleep:     time.Sleep,
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ntwarijoshua/siena/internal/listing"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/sirupsen/logrus"
+)
+
+// UserListing is what admins can filter and sort accounts by.
+var UserListing = &listing.Resource{
+	Table: models.TableNames.Users,
+	Fields: map[string]listing.Field{
+		"id":         {Column: models.UserColumns.ID, Type: listing.Int, Filterable: true, Sortable: true},
+		"email":      {Column: models.UserColumns.Email, Type: listing.String, Filterable: true, Sortable: true},
+		"role_id":    {Column: models.UserColumns.RoleID, Type: listing.Int, Filterable: true},
+		"confirmed":  {Column: models.UserColumns.Confirmed, Type: listing.Bool, Filterable: true},
+		"created_at": {Column: models.UserColumns.CreatedAt, Type: listing.Time, Filterable: true, Sortable: true},
+	},
+	Key:          "id",
+	DefaultSort:  "-created_at",
+	DefaultLimit: 25,
+	MaxLimit:     100,
+}
+
+// UserDirectoryService looks accounts up for admins.
+type UserDirectoryService struct {
+	dataLayer *models.DataStore
+	logger    *logrus.Logger
+	context   context.Context
+}
+
+// List returns a page of the active accounts matching query, a query parsed against UserListing.
+func (s *UserDirectoryService) List(ctx context.Context, query *listing.Query) (*listing.Page, error) {
+	users, err := models.ScopedUsers(models.Active, query.QueryMods()...).All(ctx, s.dataLayer.Executor)
+	if err != nil {
+		return nil, err
+	}
+	return query.Page(users)
+}
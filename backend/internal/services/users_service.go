@@ -3,24 +3,38 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	jwt "github.com/dgrijalva/jwt-go"
-	"github.com/nsqio/go-nsq"
+	"github.com/ntwarijoshua/siena/internal/emails"
+	"github.com/ntwarijoshua/siena/internal/i18n"
+	"github.com/ntwarijoshua/siena/internal/logging"
 	"github.com/ntwarijoshua/siena/internal/models"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/volatiletech/sqlboiler/boil"
-	"github.com/volatiletech/sqlboiler/queries/qm"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/volatiletech/null"
 	"os"
+	"strings"
 	"time"
 )
 
 const ClientRoleSlug = "client"
+const MasterRoleSlug = "master"
+const TokenLifetime = time.Minute * 45
 const ConfirmationMailTopic = "account-confirmation-emails"
 const ConfirmationMailChannel = "account-confirmation-channel"
 
+// ErrInvalidCredentials is deliberately vague so it doesn't reveal which of email or password was wrong.
+var ErrInvalidCredentials = UnauthorizedError("Invalid email or password", nil)
+
+// NormalizeEmail is the form emails are stored and looked up in. Every path that takes an email from
+// a client goes through it, so case and stray spaces never make two accounts.
+func NormalizeEmail(email string) string {
+	return emails.NormalizerFromEnv().Normalize(email)
+}
+
 type UserTransactionMessage struct {
 	Name         string `json:"name"`
 	EmailAddress string `json:"email_address"`
@@ -28,6 +42,8 @@ type UserTransactionMessage struct {
 	Subject      string `json:"subject"`
 	TrackingId   int    `json:"tracking_id"`
 	Type         string `json:"type"`
+	// TraceContext carries the producer's span so the consumer can continue the trace.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
 }
 
 type CustomClaims struct {
@@ -38,88 +54,168 @@ type CustomClaims struct {
 }
 
 type UserService struct {
-	dataLayer *models.DataStore
+	dataLayer models.DataLayer
 	userModel *models.User
 	roleModel *models.Role
+	passwords *PasswordService
+	mails     *MailQueue
+	auditor   *AuditService
 	logger    *logrus.Logger
 	context   context.Context
 }
 
-func (s *UserService) CreateUser(user models.User, profile models.Profile) (models.User, error) {
-	var err error
-	clientRole, err := models.Roles(qm.Where("slug = ?", ClientRoleSlug)).One(s.context, s.dataLayer.DB)
-	if err != nil {
-		s.logger.Errorf("Error occurred %s", errors.Cause(err))
-	}
-	if err = user.SetRole(s.context, s.dataLayer.DB, false, clientRole); err != nil {
-		s.logger.Errorf("Error occurred %s", errors.Cause(err))
-	}
-
-	if err = user.SetProfile(s.context, s.dataLayer.DB, true, &profile); err != nil {
-		s.logger.Errorf("Error occurred %s", errors.Cause(err))
-	}
-
-	hashAndSalt, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
-	if err != nil {
-		s.logger.Errorf("Error while hashing user password:", err)
-		return user, err
-	}
-	user.Password = string(hashAndSalt)
-	err = user.Insert(s.context, s.dataLayer.DB, boil.Infer())
-	if err != nil {
-		s.logger.Errorf("Could not create user %s", errors.Cause(err))
+func (s *UserService) CreateUser(ctx context.Context, user models.User, profile models.Profile) (models.User, error) {
+	if err := s.insertUser(ctx, &user, &profile); err != nil {
 		return user, err
 	}
 
 	// queue confirmation mail
 	confirmationToken, err := generateUniqueTokenForUser(user)
 	if err != nil {
-		s.logger.Errorf("Error occurred while generating a confirmation token %s", errors.Cause(err))
+		logging.FromContext(ctx, s.logger).Errorf("Error occurred while generating a confirmation token %s", errors.Cause(err))
 		return user, err
 	}
 	confirmationMessage := UserTransactionMessage{
-		Name:         user.R.Profile.Names.String,
+		Name:         profile.Names.String,
 		EmailAddress: user.Email,
 		Token:        confirmationToken,
 		Subject:      "Confirm your account!",
+		Type:         models.SupportedMessageType["CONFIRMATION"],
 	}
-	rawPayload, _ := json.Marshal(confirmationMessage)
-	messageLog := models.MailerLog{
-		Type:      models.SupportedMessageType["CONFIRMATION"],
-		Payload:   string(rawPayload),
-		Status:    models.SupportedStatus["PROCESSING"],
-		CreatedAt: time.Now(),
+	if err = s.mails.Queue(ctx, &confirmationMessage); err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("Error occurred while queueing user confirmation mail %s", errors.Cause(err))
+		return user, err
 	}
-	err = messageLog.Insert(s.context, s.dataLayer.DB, boil.Infer())
+	return user, err
+}
+
+// CreateFederatedUser signs up someone who authenticated with an external identity provider. The
+// account gets a random password, its owner can sign in with the provider or a magic link. When the
+// provider vouches for the email there is no need for a confirmation mail.
+func (s *UserService) CreateFederatedUser(ctx context.Context, email string, emailVerified bool, profile models.Profile) (*models.User, error) {
+	password, err := RandomToken()
 	if err != nil {
-		s.logger.Errorf("Error occurred while queueing user confirmation mail %s", errors.Cause(err))
+		return nil, err
 	}
-	confirmationMessage.TrackingId = messageLog.ID
-	confirmationMessage.Type = messageLog.Type
-	if err = produceConfirmationMail(confirmationMessage); err != nil {
-		s.logger.Errorf("Error occurred while queueing user confirmation mail %s", errors.Cause(err))
-		return user, err
+	user := models.User{Email: email, Password: password}
+	if !emailVerified {
+		created, err := s.CreateUser(ctx, user, profile)
+		return &created, err
 	}
-	messageLog.Status = models.SupportedStatus["QUEUED"]
-	messageLog.Payload = string(func(message interface{}) []byte {
-		marshalled, _ := json.Marshal(message)
-		return marshalled
-	}(confirmationMessage))
-	_, err = messageLog.Update(s.context, s.dataLayer.DB, boil.Infer())
+	user.Confirmed = null.BoolFrom(true)
+	if err = s.insertUser(ctx, &user, &profile); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// insertUser stores a client user along with its profile, hashing the password. Both rows are
+// inserted in one transaction so a failed user insert doesn't leave its profile behind, the password
+// is hashed before the transaction begins to keep it short.
+func (s *UserService) insertUser(ctx context.Context, user *models.User, profile *models.Profile) error {
+	user.Email = NormalizeEmail(user.Email)
+	hashAndSalt, err := s.passwords.Hash(user.Password)
 	if err != nil {
-		s.logger.Errorf("Error occurred %s", errors.Cause(err))
-		return user, err
+		logging.FromContext(ctx, s.logger).Errorf("Error while hashing user password: %s", err)
+		return err
+	}
+	user.Password = hashAndSalt
+
+	err = s.dataLayer.InTransaction(ctx, func(store models.DataLayer) error {
+		clientRole, err := store.Roles().FindBySlug(ctx, ClientRoleSlug)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("Could not find the client role %s", errors.Cause(err))
+			return err
+		}
+		user.RoleID = clientRole.ID
+
+		if err = store.Profiles().Insert(ctx, profile); err != nil {
+			logging.FromContext(ctx, s.logger).Errorf("Could not create profile %s", errors.Cause(err))
+			return err
+		}
+		user.ProfileID = profile.ID
+
+		if err = store.Users().Insert(ctx, user); err != nil {
+			// a concurrent signup with the same email got past the is_unique validator too
+			if conflict := constraintViolation(err); conflict != err {
+				return conflict
+			}
+			logging.FromContext(ctx, s.logger).Errorf("Could not create user %s", errors.Cause(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.audit(ctx, "user.created", user.ID)
+	return nil
+}
+
+func (s *UserService) GetUserByMail(ctx context.Context, email string) (*models.User, error) {
+	user, err := s.dataLayer.Users().FindByEmail(ctx, NormalizeEmail(email))
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, NotFoundError("User not found", err)
+	}
+	return user, err
+}
+
+func (s *UserService) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	user, err := s.dataLayer.Users().FindByID(ctx, id)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, NotFoundError("User not found", err)
 	}
 	return user, err
 }
 
-func (s *UserService) GetUserByMail(email string) (*models.User, error) {
-	return models.Users(qm.Where("email = ?", email)).One(s.context, s.dataLayer.DB)
+// Authenticate checks an email and password pair. Unknown emails cost the same hash comparison as a
+// wrong password and both yield ErrInvalidCredentials, so neither the response nor its timing tells
+// which accounts exist. Hashes made with an outdated algorithm or cost are upgraded on the way.
+// Without an active account the latest deleted one is checked, whoever knows its password is told
+// it was deleted and can still be restored.
+func (s *UserService) Authenticate(ctx context.Context, email, password string) (*models.User, error) {
+	user, err := s.dataLayer.Users().FindByEmail(ctx, NormalizeEmail(email), models.WithDeleted)
+	if err != nil && errors.Cause(err) != sql.ErrNoRows {
+		return nil, err
+	}
+	hash := ""
+	if user != nil {
+		hash = user.Password
+	}
+	needsRehash, err := s.passwords.Verify(hash, password)
+	if err != nil || user == nil {
+		return nil, ErrInvalidCredentials
+	}
+	if needsRehash {
+		s.rehashPassword(ctx, user, password)
+	}
+	return user, nil
+}
+
+// rehashPassword stores a hash made with the current settings. Failing to do so doesn't fail the
+// login, it is retried on the next one.
+func (s *UserService) rehashPassword(ctx context.Context, user *models.User, password string) {
+	hash, err := s.passwords.Hash(password)
+	if err == nil {
+		user.Password = hash
+		err = s.dataLayer.Users().Update(ctx, user, models.UserColumns.Password)
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Errorf("Could not rehash password %s", err)
+		return
+	}
+	s.audit(ctx, "user.password_rehashed", user.ID)
 }
+
 func (s *UserService) GetJWTToken(user *models.User, password string) (string, error) {
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", err
+	if _, err := s.passwords.Verify(user.Password, password); err != nil {
+		return "", ErrInvalidCredentials
 	}
+	return s.IssueJWTToken(user)
+}
+
+// IssueJWTToken signs an access token for a user whose identity has already been established.
+func (s *UserService) IssueJWTToken(user *models.User) (string, error) {
 	signingKey := os.Getenv("JWT_SIGNING_KEY")
 
 	claims := CustomClaims{
@@ -127,7 +223,7 @@ func (s *UserService) GetJWTToken(user *models.User, password string) (string, e
 		user.Email,
 		time.Now(),
 		jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Minute * 45).Unix(),
+			ExpiresAt: time.Now().Add(TokenLifetime).Unix(),
 			Issuer:    "api.siena",
 		},
 	}
@@ -135,18 +231,97 @@ func (s *UserService) GetJWTToken(user *models.User, password string) (string, e
 	return token.SignedString([]byte(signingKey))
 }
 
-func produceConfirmationMail(message UserTransactionMessage) error {
-	config := nsq.NewConfig()
-	w, err := nsq.NewProducer(os.Getenv("NSQD"), config)
-	defer func() { _ = w.Stop }()
+// VerifyToken validates a JWT issued by GetJWTToken and returns the user it was issued to,
+// as long as that account is still active and confirmed.
+func (s *UserService) VerifyToken(ctx context.Context, rawToken string) (*models.User, error) {
+	claims := CustomClaims{}
+	token, err := jwt.ParseWithClaims(rawToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(os.Getenv("JWT_SIGNING_KEY")), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, UnauthorizedError("Invalid or expired token", err)
+	}
+	if claims.Audience == MFAChallengeAudience {
+		return nil, UnauthorizedError("Invalid or expired token", nil)
+	}
+	user, err := s.dataLayer.Users().FindByID(ctx, claims.UserId, models.WithDeleted)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, UnauthorizedError("Invalid or expired token", err)
+	}
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if user.Deleted {
+		return nil, UnauthorizedError("This account has been deleted", nil)
+	}
+	if !user.Confirmed.Bool {
+		return nil, UnauthorizedError("This account has not been confirmed yet", nil)
+	}
+	return user, nil
+}
+
+// ConfirmUser marks the account a confirmation mail was sent to as confirmed, given the tracking id
+// and token from the confirmation link.
+func (s *UserService) ConfirmUser(ctx context.Context, trackingID int, token string) (*models.User, error) {
+	invalidLink := ValidationError("The confirmation link is invalid", FieldError{Field: "token", Message: "token is invalid"})
+	mailLog, err := s.dataLayer.MailLogs().FindByID(ctx, trackingID)
+	if errors.Cause(err) == sql.ErrNoRows {
+		return nil, invalidLink
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mailLog.Type != models.SupportedMessageType["CONFIRMATION"] {
+		return nil, invalidLink
+	}
+	var message UserTransactionMessage
+	if err = json.Unmarshal([]byte(mailLog.Payload), &message); err != nil {
+		return nil, err
+	}
+	if message.Token == "" || subtle.ConstantTimeCompare([]byte(message.Token), []byte(token)) != 1 {
+		return nil, invalidLink
+	}
+	user, err := s.GetUserByMail(ctx, message.EmailAddress)
+	if IsKind(err, KindNotFound) {
+		return nil, invalidLink
 	}
-	rawMSG, err := json.Marshal(message)
 	if err != nil {
+		return nil, err
+	}
+	if user.Confirmed.Bool {
+		return user, nil
+	}
+	user.Confirmed = null.BoolFrom(true)
+	if err = s.dataLayer.Users().Update(ctx, user, models.UserColumns.Confirmed); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, "user.confirmed", user.ID)
+	return user, nil
+}
+
+// SetLocale stores the language user wants the api to answer in, it wins over the languages their
+// clients ask for. An empty locale forgets the preference.
+func (s *UserService) SetLocale(ctx context.Context, user *models.User, locale string) error {
+	if locale != "" && !i18n.IsSupported(locale) {
+		return ValidationError("The submitted data is invalid", FieldError{
+			Field:   "locale",
+			Message: "locale should be one of " + strings.Join(i18n.Supported, ", "),
+		})
+	}
+	user.Locale = null.NewString(locale, locale != "")
+	if err := s.dataLayer.Users().Update(ctx, user, models.UserColumns.Locale); err != nil {
 		return err
 	}
-	return w.Publish(ConfirmationMailTopic, []byte(rawMSG))
+	s.audit(ctx, "user.locale_updated", user.ID)
+	return nil
+}
+
+func (s *UserService) audit(ctx context.Context, action string, userID int) {
+	targetType, targetID := UserTarget(userID)
+	s.auditor.Record(ctx, AuditEvent{Action: action, TargetType: targetType, TargetID: targetID})
 }
 
 func generateUniqueTokenForUser(user models.User) (string, error) {
@@ -2,24 +2,25 @@ package services
 
 import (
 	"context"
-	"github.com/go-playground/locales"
-	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
+	"github.com/ntwarijoshua/siena/internal/i18n"
 	"github.com/ntwarijoshua/siena/internal/models"
 	"github.com/sirupsen/logrus"
-	"github.com/volatiletech/sqlboiler/queries/qm"
 	"gopkg.in/go-playground/validator.v9"
 	enTranslations "gopkg.in/go-playground/validator.v9/translations/en"
+	frTranslations "gopkg.in/go-playground/validator.v9/translations/fr"
+	"reflect"
+	"strings"
 )
 
 type ValidationService struct {
-	validator  *validator.Validate
-	dataLayer  *models.DataStore
-	translator locales.Translator
-	uni        *ut.UniversalTranslator
-	lang       ut.Translator
-	logger     *logrus.Logger
-	context    context.Context
+	validator   *validator.Validate
+	dataLayer   models.DataLayer
+	translators *ut.UniversalTranslator
+	// lang is the default locale, errors are built in it and localized when they are reported
+	lang    ut.Translator
+	logger  *logrus.Logger
+	context context.Context
 }
 
 func (vs *ValidationService) GetValidator() *validator.Validate {
@@ -28,53 +29,123 @@ func (vs *ValidationService) GetValidator() *validator.Validate {
 
 func (vs *ValidationService) InitializeValidator() {
 	vs.validator = validator.New()
-	vs.translator = en.New()
-	vs.uni = ut.New(vs.translator, vs.translator)
-	_ = vs.validator.RegisterValidation("is_unique", func(fl validator.FieldLevel) bool {
-		email := fl.Field().String()
-		user, err := models.Users(qm.Where("email = ?", email)).One(vs.context, vs.dataLayer.DB)
-		if err == nil && user.Email == email {
-			return false
+	// report fields the way clients send them rather than by their go names
+	vs.validator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
 		}
-		return true
+		return name
+	})
+	_ = vs.validator.RegisterValidationCtx("is_unique", func(ctx context.Context, fl validator.FieldLevel) bool {
+		exists, err := vs.dataLayer.Users().EmailExists(ctx, NormalizeEmail(fl.Field().String()))
+		return err != nil || !exists
 	})
 
-	//register custom message
-	var found = false
-	vs.lang, found = vs.uni.FindTranslator("en")
-	if !found {
-		vs.logger.Fatal("Could not find translator")
+	var err error
+	if vs.translators, err = i18n.NewUniversalTranslator(); err != nil {
+		vs.logger.Fatal(err)
 	}
-	if err := enTranslations.RegisterDefaultTranslations(vs.validator, vs.lang); err != nil {
+	vs.lang = vs.translator(i18n.Default)
+	if err = enTranslations.RegisterDefaultTranslations(vs.validator, vs.lang); err != nil {
 		vs.logger.Fatal(err)
 	}
+	if err = frTranslations.RegisterDefaultTranslations(vs.validator, vs.translator("fr")); err != nil {
+		vs.logger.Fatal(err)
+	}
+	// the validator has no kinyarwanda messages, these cover the tags the api uses and the rest is
+	// reported in English
+	for tag, message := range map[string]string{
+		"required": "{0} ni ngombwa",
+		"email":    "{0} igomba kuba imeli yemewe",
+		"min":      "{0} igomba kuba nibura {1}",
+		"max":      "{0} ntigomba kurenza {1}",
+	} {
+		vs.registerMessage(vs.translator("rw"), tag, message)
+	}
 
-	_ = vs.validator.RegisterTranslation("required", vs.lang, func(ut ut.Translator) error {
-		return ut.Add("required", "{0} is a required field", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("required", fe.Field())
-		return t
-	})
+	//register custom message
+	for locale, messages := range map[string]map[string]string{
+		"en": {"required": "{0} is a required field", "email": "{0} should be a valid email", "is_unique": "{0} is already taken"},
+		"fr": {"is_unique": "{0} est déjà utilisé"},
+		"rw": {"is_unique": "{0} yamaze gukoreshwa"},
+	} {
+		for tag, message := range messages {
+			vs.registerMessage(vs.translator(locale), tag, message)
+		}
+	}
+}
 
-	_ = vs.validator.RegisterTranslation("email", vs.lang, func(ut ut.Translator) error {
-		return ut.Add("email", "{0} should be a valid email", true)
+// registerMessage reports fields failing tag with message in the locale of translator, {0} is the
+// field and {1} the parameter of the tag.
+func (vs *ValidationService) registerMessage(translator ut.Translator, tag, message string) {
+	_ = vs.validator.RegisterTranslation(tag, translator, func(ut ut.Translator) error {
+		return ut.Add(tag, message, true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("email", fe.Field())
+		t, _ := ut.T(tag, fe.Field(), fe.Param())
 		return t
 	})
+}
 
-	_ = vs.validator.RegisterTranslation("is_unique", vs.lang, func(ut ut.Translator) error {
-		return ut.Add("is_unique", "{0} An account with the same email already exists", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("is_unique", fe.Field())
-		return t
-	})
+// translator returns the translator of a supported locale.
+func (vs *ValidationService) translator(locale string) ut.Translator {
+	translator, _ := vs.translators.GetTranslator(locale)
+	return translator
+}
+
+// Localize returns err in locale: its message, and those of its fields, looked up in the catalog of
+// locale, and the fields the validator refused translated by the validator. What isn't translated
+// is left in English.
+func (vs *ValidationService) Localize(err *ServiceError, locale string) *ServiceError {
+	translator := vs.translator(locale)
+	localized := *err
+	if err.template != "" {
+		localized.Message = i18n.Translate(translator, err.template, err.params...)
+	} else {
+		localized.Message = i18n.Translate(translator, err.Message)
+	}
+	localized.Fields = nil
+	for _, field := range err.Fields {
+		if field.source != nil {
+			field.Message = field.source.Translate(translator)
+		} else if field.template != "" {
+			field.Message = i18n.Translate(translator, field.template, field.params...)
+		} else {
+			field.Message = i18n.Translate(translator, field.Message)
+		}
+		localized.Fields = append(localized.Fields, field)
+	}
+	return &localized
+}
+
+// Translate returns message in locale.
+func (vs *ValidationService) Translate(locale, message string) string {
+	return i18n.Translate(vs.translator(locale), message)
 }
 
-func (vs *ValidationService) GenerateValidationResponse(err error) []string {
-	var errMessages []string
-	for _, e := range err.(validator.ValidationErrors) {
-		errMessages = append(errMessages, e.Translate(vs.lang))
+// ValidationFailure converts the errors returned by the validator into a ServiceError carrying a
+// translated message for every invalid field. Input that is only refused because is_unique found
+// the email taken is a conflict, the same one the unique index reports when a concurrent signup
+// gets past the check.
+func (vs *ValidationService) ValidationFailure(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	fields := make([]FieldError, 0, len(validationErrors))
+	taken := 0
+	for _, e := range validationErrors {
+		fields = append(fields, FieldError{Field: e.Field(), Message: e.Translate(vs.lang), source: e})
+		if e.Tag() == "is_unique" {
+			taken++
+		}
+	}
+	if taken == len(fields) {
+		// reported like the unique violations of the database, whose messages come from the catalog
+		for i := range fields {
+			fields[i].source = nil
+		}
+		return ConflictError(ErrEmailTaken.Message, nil, fields...)
 	}
-	return errMessages
+	return ValidationError("The submitted data is invalid", fields...)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	_ "github.com/lib/pq"
 	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/tracing"
 	logger "github.com/sirupsen/logrus"
 )
 
@@ -22,5 +23,5 @@ func NewDB(config DBCredentials) (*models.DataStore, error) {
 	logger.Info("Connecting to the database")
 	database, err := sql.Open("postgres", fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=disable",
 		config.Host, config.Port, config.Dbname, config.Username, config.Password))
-	return &models.DataStore{DB: database}, err
+	return &models.DataStore{DB: database, Executor: &tracing.Executor{DB: database}}, err
 }
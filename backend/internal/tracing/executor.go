@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/volatiletech/sqlboiler/boil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Executor wraps the connection pool handed to sqlboiler, or a transaction of it, so that every query
+// gets its own span.
+type Executor struct {
+	DB boil.ContextExecutor
+}
+
+var _ boil.ContextExecutor = &Executor{}
+
+func (e *Executor) startSpan(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, "sql "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBStatement(query),
+			attribute.String("db.operation", operation),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != sql.ErrNoRows {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (e *Executor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return e.ExecContext(context.Background(), query, args...)
+}
+
+func (e *Executor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return e.QueryContext(context.Background(), query, args...)
+}
+
+func (e *Executor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return e.QueryRowContext(context.Background(), query, args...)
+}
+
+func (e *Executor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := e.startSpan(ctx, "exec", query)
+	result, err := e.DB.ExecContext(ctx, query, args...)
+	endSpan(span, err)
+	return result, err
+}
+
+func (e *Executor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := e.startSpan(ctx, "query", query)
+	rows, err := e.DB.QueryContext(ctx, query, args...)
+	endSpan(span, err)
+	return rows, err
+}
+
+func (e *Executor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := e.startSpan(ctx, "query_row", query)
+	row := e.DB.QueryRowContext(ctx, query, args...)
+	endSpan(span, row.Err())
+	return row
+}
@@ -0,0 +1,282 @@
+package account_tests
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/cache"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// accounts is an account service on postgres with the users fixtures loaded, it keeps the mails
+// it queued.
+type accounts struct {
+	*services.AccountService
+	store     *models.DataStore
+	fixtures  harness.Fixtures
+	broker    *harness.Broker
+	mails     []services.UserTransactionMessage
+	exportDir string
+}
+
+func newAccounts(t *testing.T, options services.AccountOptions) *accounts {
+	return newAccountsPublishingTo(t, options, nil)
+}
+
+// newAccountsPublishingTo queues the mails on broker instead, tests keeping the mails pass nil.
+func newAccountsPublishingTo(t *testing.T, options services.AccountOptions, broker services.MessageBroker) *accounts {
+	store := harness.NewDatabase(t)
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	ctx := context.Background()
+	accounts := &accounts{store: store, fixtures: harness.LoadFixtures(t, store, "users"), broker: harness.NewBroker()}
+	accounts.broker.Subscribe(services.ConfirmationMailTopic, func(_ string, body []byte) error {
+		var message services.UserTransactionMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			return err
+		}
+		accounts.mails = append(accounts.mails, message)
+		return nil
+	})
+	if broker == nil {
+		broker = accounts.broker
+	}
+	if options.ExportDir == "" {
+		dir, err := ioutil.TempDir("", "exports")
+		assert.Nil(t, err)
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		options.ExportDir = dir
+	}
+	accounts.exportDir = options.ExportDir
+	accounts.AccountService = services.NewAccountService(
+		ctx, store, logger, options, services.NewMailQueue(store, broker), services.NewAuditService(ctx, store, logger),
+	)
+	return accounts
+}
+
+// token returns the token of the last mail of type sent to the fixture user email.
+func (a *accounts) token(t *testing.T, email, mailType string) string {
+	t.Helper()
+	assert.Nil(t, a.broker.Deliver(services.ConfirmationMailTopic))
+	for i := len(a.mails) - 1; i >= 0; i-- {
+		if a.mails[i].EmailAddress == email && a.mails[i].Type == mailType {
+			return a.mails[i].Token
+		}
+	}
+	t.Fatalf("no %s was sent to %s", mailType, email)
+	return ""
+}
+
+// user reloads the fixture user email, it is nil once purged.
+func (a *accounts) user(t *testing.T, email string) *models.User {
+	t.Helper()
+	user, err := models.FindUser(context.Background(), a.store.Executor, a.fixtures.Users[email].ID)
+	if err != nil {
+		assert.Equal(t, sql.ErrNoRows, pkgerrors.Cause(err))
+		return nil
+	}
+	return user
+}
+
+type failingBroker struct{}
+
+func (failingBroker) Publish(string, []byte) error {
+	return errors.New("nsqd is down")
+}
+
+var (
+	deletionMail = models.SupportedMessageType["ACCOUNT_DELETION"]
+	exportMail   = models.SupportedMessageType["DATA_EXPORT"]
+)
+
+func TestDeletedAccountsCanBeRestored(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: time.Hour, ExportLifetime: time.Hour})
+	ctx := context.Background()
+	jane := accounts.fixtures.Users["jane@example.com"]
+
+	deletion, err := accounts.RequestDeletion(ctx, jane)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deletion.PurgeAfter, time.Minute)
+	assert.True(t, accounts.user(t, "jane@example.com").Deleted)
+	_, err = accounts.RequestDeletion(ctx, jane)
+	assert.Equal(t, services.ErrAccountAlreadyDeleted, err)
+
+	token := accounts.token(t, "jane@example.com", deletionMail)
+	_, err = accounts.Restore(ctx, "forged")
+	assert.Equal(t, services.ErrInvalidRestoreLink, err)
+	restored, err := accounts.Restore(ctx, token)
+	if assert.Nil(t, err) {
+		assert.Equal(t, jane.ID, restored.ID)
+	}
+	assert.False(t, accounts.user(t, "jane@example.com").Deleted)
+	_, err = accounts.Restore(ctx, token)
+	assert.Equal(t, services.ErrInvalidRestoreLink, err, "restore links are single use")
+}
+
+func TestAccountsCanNotBeRestoredOnceTheirEmailIsReused(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: time.Hour, ExportLifetime: time.Hour})
+	ctx := context.Background()
+	jane := accounts.fixtures.Users["jane@example.com"]
+	_, err := accounts.RequestDeletion(ctx, jane)
+	assert.Nil(t, err)
+	token := accounts.token(t, "jane@example.com", deletionMail)
+
+	profile := models.Profile{}
+	assert.Nil(t, accounts.store.Profiles().Insert(ctx, &profile))
+	newcomer := models.User{Email: jane.Email, Password: "-", Confirmed: null.BoolFrom(true), ProfileID: profile.ID, RoleID: jane.RoleID}
+	assert.Nil(t, accounts.store.Users().Insert(ctx, &newcomer))
+
+	_, err = accounts.Restore(ctx, token)
+	assert.Equal(t, services.ErrEmailReused, err)
+	assert.True(t, accounts.user(t, "jane@example.com").Deleted)
+}
+
+func TestPurgeDueRemovesAccountsPastTheirGracePeriod(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: -time.Second, ExportLifetime: time.Hour})
+	ctx := context.Background()
+	jane := accounts.fixtures.Users["jane@example.com"]
+	attempt := models.LoginAttempt{Email: jane.Email, IPAddress: "203.0.113.7"}
+	assert.Nil(t, attempt.Insert(ctx, accounts.store.Executor, boil.Infer()))
+	_, err := accounts.Export(ctx, jane)
+	assert.Nil(t, err)
+	_, err = accounts.RequestDeletion(ctx, jane)
+	assert.Nil(t, err)
+	token := accounts.token(t, "jane@example.com", deletionMail)
+	lookups := cache.NewLRU(10)
+	services.InvalidateOnWrite(lookups, logrus.New())
+	profiles := services.NewProfileService(ctx, accounts.store, logrus.New(), lookups)
+	_, err = profiles.Get(ctx, jane.ProfileID)
+	assert.Nil(t, err)
+
+	purged, err := accounts.PurgeDue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Nil(t, accounts.user(t, "jane@example.com"))
+	assert.NotNil(t, accounts.user(t, "john@example.com"))
+	_, err = models.FindProfile(ctx, accounts.store.Executor, jane.ProfileID)
+	assert.NotNil(t, err, "the profile goes with the user")
+	_, err = profiles.Get(ctx, jane.ProfileID)
+	assert.True(t, services.IsKind(err, services.KindNotFound), "and out of the cache")
+	attempts, err := models.LoginAttempts(qm.Where("email = ?", jane.Email)).Count(ctx, accounts.store.Executor)
+	assert.Nil(t, err)
+	assert.Zero(t, attempts)
+	mails, err := models.MailerLogs().All(ctx, accounts.store.Executor)
+	if assert.Nil(t, err) && assert.NotEmpty(t, mails) {
+		for _, mail := range mails {
+			assert.JSONEq(t, `{"purged":true}`, mail.Payload)
+		}
+	}
+	files, err := ioutil.ReadDir(accounts.exportDir)
+	assert.Nil(t, err)
+	assert.Empty(t, files, "exports are removed with the account")
+	_, err = accounts.Restore(ctx, token)
+	assert.Equal(t, services.ErrInvalidRestoreLink, err)
+
+	purged, err = accounts.PurgeDue(ctx)
+	assert.Nil(t, err)
+	assert.Zero(t, purged, "purged accounts aren't purged again")
+}
+
+func TestPurgeDueLeavesAccountsToTheInstanceHoldingTheLock(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: -time.Second, ExportLifetime: time.Hour})
+	ctx := context.Background()
+	_, err := accounts.RequestDeletion(ctx, accounts.fixtures.Users["jane@example.com"])
+	assert.Nil(t, err)
+
+	other, err := accounts.store.DB.Conn(ctx)
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = other.Close() }()
+	_, err = other.ExecContext(ctx, "SELECT pg_advisory_lock($1)", services.AccountPurgeLock)
+	assert.Nil(t, err)
+	purged, err := accounts.PurgeDue(ctx)
+	assert.Nil(t, err)
+	assert.Zero(t, purged)
+	assert.NotNil(t, accounts.user(t, "jane@example.com"))
+
+	_, err = other.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", services.AccountPurgeLock)
+	assert.Nil(t, err)
+	purged, err = accounts.PurgeDue(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, purged)
+}
+
+func TestExportsCanOnlyBeOpenedByTheirOwner(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: time.Hour, ExportLifetime: time.Hour})
+	ctx := context.Background()
+	jane, john := accounts.fixtures.Users["jane@example.com"], accounts.fixtures.Users["john@example.com"]
+
+	export, err := accounts.Export(ctx, jane)
+	if !assert.Nil(t, err) {
+		return
+	}
+	token := accounts.token(t, "jane@example.com", exportMail)
+	_, err = accounts.OpenExport(ctx, john.ID, token)
+	assert.Equal(t, services.ErrInvalidExportLink, err)
+	_, err = accounts.OpenExport(ctx, jane.ID, "forged")
+	assert.Equal(t, services.ErrInvalidExportLink, err)
+
+	opened, err := accounts.OpenExport(ctx, jane.ID, token)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, export.ID, opened.ID)
+	assert.True(t, opened.DownloadedAt.Valid)
+	reader, err := zip.OpenReader(opened.FilePath)
+	if assert.Nil(t, err) {
+		assert.Len(t, reader.File, 6)
+		_ = reader.Close()
+	}
+
+	assert.Nil(t, os.Remove(opened.FilePath))
+	_, err = accounts.OpenExport(ctx, jane.ID, token)
+	assert.Equal(t, services.ErrInvalidExportLink, err, "exports whose file is gone can't be opened")
+}
+
+func TestExpiredExportsAreRemoved(t *testing.T) {
+	accounts := newAccounts(t, services.AccountOptions{DeletionGracePeriod: time.Hour, ExportLifetime: -time.Second})
+	ctx := context.Background()
+	jane := accounts.fixtures.Users["jane@example.com"]
+
+	export, err := accounts.Export(ctx, jane)
+	if !assert.Nil(t, err) {
+		return
+	}
+	_, err = accounts.OpenExport(ctx, jane.ID, accounts.token(t, "jane@example.com", exportMail))
+	assert.Equal(t, services.ErrInvalidExportLink, err)
+
+	_, err = accounts.PurgeDue(ctx)
+	assert.Nil(t, err)
+	_, err = os.Stat(export.FilePath)
+	assert.True(t, os.IsNotExist(err))
+	exports, err := models.DataExports().Count(ctx, accounts.store.Executor)
+	assert.Nil(t, err)
+	assert.Zero(t, exports)
+}
+
+func TestExportsSurviveTheMailQueueFailing(t *testing.T) {
+	accounts := newAccountsPublishingTo(t, services.AccountOptions{DeletionGracePeriod: time.Hour, ExportLifetime: time.Hour}, failingBroker{})
+	export, err := accounts.Export(context.Background(), accounts.fixtures.Users["jane@example.com"])
+	if assert.Nil(t, err) {
+		_, err = os.Stat(export.FilePath)
+		assert.Nil(t, err)
+	}
+}
@@ -0,0 +1,50 @@
+package account_tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+)
+
+func TestArchiveWritesOneJSONFilePerSection(t *testing.T) {
+	archive := services.AccountArchive{
+		Account: services.ArchivedAccount{ID: 7, Email: "jane@example.com", Role: "user", Confirmed: true},
+		Profile: &models.Profile{ID: 3, Names: null.StringFrom("Jane Doe")},
+		APIKeys: []services.ArchivedAPIKey{
+			{Name: "ci", Prefix: "siena_0123456789ab", Scopes: []string{services.ScopeProfileRead}, CreatedAt: time.Now()},
+		},
+		LoginAttempts: []services.ArchivedLoginAttempt{},
+		Mails:         []services.ArchivedMail{{Type: "confirmation_mail", Status: "sent"}},
+	}
+	var buffer bytes.Buffer
+	assert.Nil(t, archive.WriteZip(&buffer))
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	assert.Nil(t, err)
+	contents := map[string][]byte{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		assert.Nil(t, err)
+		contents[file.Name], _ = ioutil.ReadAll(rc)
+		_ = rc.Close()
+	}
+	assert.Len(t, contents, 6)
+	for _, name := range []string{"account.json", "profile.json", "identities.json", "api_keys.json", "login_attempts.json", "mails.json"} {
+		assert.True(t, json.Valid(contents[name]), name)
+	}
+
+	var account map[string]interface{}
+	assert.Nil(t, json.Unmarshal(contents["account.json"], &account))
+	assert.Equal(t, "jane@example.com", account["email"])
+	assert.NotContains(t, account, "password")
+	assert.NotContains(t, string(contents["api_keys.json"]), "secret_hash")
+	assert.JSONEq(t, "[]", string(contents["login_attempts.json"]))
+}
@@ -0,0 +1,11 @@
+package account_tests
+
+import (
+	"testing"
+
+	"github.com/ntwarijoshua/siena/test/harness"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
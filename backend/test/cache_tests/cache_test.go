@@ -0,0 +1,103 @@
+package cache_tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/cache"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+func cached(t *testing.T, c cache.Cache, key string) string {
+	t.Helper()
+	value, ok, err := c.Get(context.Background(), key)
+	assert.Nil(t, err)
+	if !ok {
+		return ""
+	}
+	return string(value)
+}
+
+func TestLRUEvictsTheLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(2)
+	assert.Nil(t, lru.Set(ctx, "a", []byte("1"), 0))
+	assert.Nil(t, lru.Set(ctx, "b", []byte("2"), 0))
+	assert.Equal(t, "1", cached(t, lru, "a"))
+	assert.Nil(t, lru.Set(ctx, "c", []byte("3"), 0))
+
+	assert.Equal(t, "", cached(t, lru, "b"), "b was used last")
+	assert.Equal(t, "1", cached(t, lru, "a"))
+	assert.Equal(t, "3", cached(t, lru, "c"))
+	assert.Equal(t, 2, lru.Len())
+
+	assert.Nil(t, lru.Delete(ctx, "a", "missing"))
+	assert.Equal(t, "", cached(t, lru, "a"))
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(10)
+	assert.Nil(t, lru.Set(ctx, "short", []byte("1"), time.Millisecond))
+	assert.Nil(t, lru.Set(ctx, "long", []byte("2"), time.Hour))
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, "", cached(t, lru, "short"))
+	assert.Equal(t, "2", cached(t, lru, "long"))
+	assert.Equal(t, 1, lru.Len(), "expired entries are dropped once looked up")
+}
+
+func TestAside(t *testing.T) {
+	ctx := context.Background()
+	lru := cache.NewLRU(10)
+	loads := 0
+	load := func(dest *[]string) func() error {
+		return func() error {
+			loads++
+			*dest = []string{"jane", "john"}
+			return nil
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		var names []string
+		assert.Nil(t, cache.Aside(ctx, lru, "names", time.Minute, &names, load(&names)))
+		assert.Equal(t, []string{"jane", "john"}, names)
+	}
+	assert.Equal(t, 1, loads, "the second read is a hit")
+
+	failure := errors.New("database is down")
+	var value string
+	err := cache.Aside(ctx, lru, "failing", time.Minute, &value, func() error { return failure })
+	assert.Equal(t, failure, err)
+	assert.Equal(t, "", cached(t, lru, "failing"), "failures aren't cached")
+
+	var names []string
+	assert.Nil(t, cache.Aside(ctx, nil, "names", time.Minute, &names, load(&names)))
+	assert.Equal(t, 2, loads, "without a cache every read loads")
+}
+
+func TestSQLCache(t *testing.T) {
+	store := harness.NewDatabase(t)
+	ctx := context.Background()
+	sqlCache := cache.NewSQLCache(store.Executor)
+
+	assert.Nil(t, sqlCache.Set(ctx, "a", []byte("1"), time.Hour))
+	assert.Nil(t, sqlCache.Set(ctx, "a", []byte("2"), time.Hour))
+	assert.Nil(t, sqlCache.Set(ctx, "b", []byte("3"), 0))
+	assert.Nil(t, sqlCache.Set(ctx, "expired", []byte("4"), time.Millisecond))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, "2", cached(t, sqlCache, "a"))
+	assert.Equal(t, "3", cached(t, sqlCache, "b"))
+	assert.Equal(t, "", cached(t, sqlCache, "expired"))
+
+	assert.Nil(t, sqlCache.Delete(ctx, "a", "b"))
+	assert.Equal(t, "", cached(t, sqlCache, "a"))
+	assert.Equal(t, "", cached(t, sqlCache, "b"))
+}
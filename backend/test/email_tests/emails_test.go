@@ -0,0 +1,85 @@
+package email_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/emails"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+func TestNormalize(t *testing.T) {
+	plain := emails.Normalizer{}
+	providers := emails.Normalizer{Rules: emails.ProviderRules}
+	for _, test := range []struct {
+		address, plain, providers string
+	}{
+		{" Jane@Example.com ", "jane@example.com", "jane@example.com"},
+		{"Jane.Doe+news@GMail.com", "jane.doe+news@gmail.com", "janedoe@gmail.com"},
+		{"jane.doe@googlemail.com", "jane.doe@googlemail.com", "janedoe@gmail.com"},
+		{"jane.doe+work@outlook.com", "jane.doe+work@outlook.com", "jane.doe@outlook.com"},
+		{"+news@gmail.com", "+news@gmail.com", "+news@gmail.com"},
+		{"not an address", "not an address", "not an address"},
+	} {
+		assert.Equal(t, test.plain, plain.Normalize(test.address), test.address)
+		assert.Equal(t, test.providers, providers.Normalize(test.address), test.address)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	users := models.UserSlice{
+		{ID: 1, Email: "jane@example.com"},
+		{ID: 2, Email: "Jane@Example.com"},
+		{ID: 3, Email: "JOHN@example.com"},
+		{ID: 4, Email: "John@example.com", Deleted: true},
+		{ID: 5, Email: "ada@example.com"},
+	}
+	report := emails.Plan(users, emails.Normalizer{})
+	assert.Equal(t, []emails.Change{
+		{UserID: 3, From: "JOHN@example.com", To: "john@example.com"},
+		{UserID: 4, From: "John@example.com", To: "john@example.com"},
+	}, report.Changes)
+	if assert.Len(t, report.Conflicts, 1) {
+		assert.Equal(t, "jane@example.com", report.Conflicts[0].Email)
+		assert.Equal(t, models.UserSlice{users[0], users[1]}, report.Conflicts[0].Users)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	store := harness.NewDatabase(t)
+	ctx := context.Background()
+	// written past the services, as they were before emails were normalized
+	role, err := store.Roles().FindBySlug(ctx, "client")
+	if !assert.Nil(t, err) {
+		return
+	}
+	for _, email := range []string{"Ada@Example.com", "ada.lovelace@example.com"} {
+		profile := models.Profile{Names: null.StringFrom("Ada Lovelace")}
+		assert.Nil(t, store.Profiles().Insert(ctx, &profile))
+		user := models.User{Email: email, Password: "x", ProfileID: profile.ID, RoleID: role.ID}
+		assert.Nil(t, user.Insert(ctx, store.Executor, boil.Infer()))
+	}
+
+	report, err := emails.Backfill(ctx, store.Executor, emails.Normalizer{}, false)
+	if assert.Nil(t, err) {
+		assert.Len(t, report.Changes, 1)
+		assert.Empty(t, report.Conflicts)
+	}
+	_, err = store.Users().FindByEmail(ctx, "ada@example.com")
+	assert.Nil(t, err, "lookups don't depend on the backfill")
+
+	_, err = emails.Backfill(ctx, store.Executor, emails.Normalizer{}, true)
+	assert.Nil(t, err)
+	report, err = emails.Backfill(ctx, store.Executor, emails.Normalizer{}, false)
+	if assert.Nil(t, err) {
+		assert.Empty(t, report.Changes)
+	}
+}
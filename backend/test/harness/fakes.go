@@ -0,0 +1,115 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/ntwarijoshua/siena/internal/services"
+)
+
+// MailTransport keeps the mails it is asked to send instead of sending them. Setting Err makes every
+// send fail with it, the way mailgun being down would.
+type MailTransport struct {
+	mutex sync.Mutex
+	sent  []services.Mail
+	Err   error
+}
+
+func (t *MailTransport) Send(_ context.Context, mail services.Mail) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.Err != nil {
+		return t.Err
+	}
+	t.sent = append(t.sent, mail)
+	return nil
+}
+
+// Sent returns the mails sent so far, oldest first.
+func (t *MailTransport) Sent() []services.Mail {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]services.Mail(nil), t.sent...)
+}
+
+// Link parses the link variable of mail, e.g. confirmation_link, so tests can follow it.
+func Link(mail services.Mail, variable string) (*url.URL, error) {
+	raw, ok := mail.Variables[variable]
+	if !ok {
+		return nil, fmt.Errorf("mail to %s has no %s", mail.To, variable)
+	}
+	return url.Parse(raw)
+}
+
+// Handler consumes a message, an error asks for it to be delivered again.
+type Handler func(messageID string, body []byte) error
+
+// Broker is an in-memory services.MessageBroker. Published messages wait on their topic until Deliver
+// hands them to the topic's handler, so tests decide when the consumer runs.
+type Broker struct {
+	mutex    sync.Mutex
+	nextID   int
+	queues   map[string][]message
+	handlers map[string]Handler
+}
+
+type message struct {
+	id   string
+	body []byte
+}
+
+func NewBroker() *Broker {
+	return &Broker{queues: map[string][]message{}, handlers: map[string]Handler{}}
+}
+
+func (b *Broker) Publish(topic string, body []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.nextID++
+	b.queues[topic] = append(b.queues[topic], message{id: fmt.Sprintf("%016d", b.nextID), body: body})
+	return nil
+}
+
+// Subscribe makes handler the consumer of topic.
+func (b *Broker) Subscribe(topic string, handler Handler) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.handlers[topic] = handler
+}
+
+// Pending returns how many messages wait on topic.
+func (b *Broker) Pending(topic string) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.queues[topic])
+}
+
+// Deliver hands the messages waiting on topic to its handler in order. It stops at the first one the
+// handler fails, which stays on the topic to be delivered again, and returns the handler's error.
+func (b *Broker) Deliver(topic string) error {
+	b.mutex.Lock()
+	handler := b.handlers[topic]
+	b.mutex.Unlock()
+	if handler == nil {
+		return fmt.Errorf("nothing subscribed to %s", topic)
+	}
+	for {
+		b.mutex.Lock()
+		if len(b.queues[topic]) == 0 {
+			b.mutex.Unlock()
+			return nil
+		}
+		next := b.queues[topic][0]
+		b.mutex.Unlock()
+
+		// the lock is released while handling, handlers may publish
+		if err := handler(next.id, next.body); err != nil {
+			return err
+		}
+		b.mutex.Lock()
+		b.queues[topic] = b.queues[topic][1:]
+		b.mutex.Unlock()
+	}
+}
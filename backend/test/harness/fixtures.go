@@ -0,0 +1,85 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/volatiletech/null"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Fixtures are the rows loaded from a fixture file, users are keyed by email.
+type Fixtures struct {
+	Users map[string]*models.User
+}
+
+type fixtureFile struct {
+	Users []struct {
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+		Names     string `json:"names"`
+		Role      string `json:"role"`
+		Confirmed bool   `json:"confirmed"`
+		Deleted   bool   `json:"deleted"`
+	} `json:"users"`
+}
+
+// LoadFixtures inserts the rows of fixtures/<name>.json into store. Passwords are written in the clear
+// in the file and hashed with bcrypt's minimum cost on the way in.
+func LoadFixtures(t *testing.T, store models.DataLayer, name string) Fixtures {
+	t.Helper()
+	raw, err := ioutil.ReadFile(filepath.Join(repositoryRoot(), "test", "harness", "fixtures", name+".json"))
+	if err != nil {
+		t.Fatalf("could not read fixtures %s %s", name, err)
+	}
+	var file fixtureFile
+	if err = json.Unmarshal(raw, &file); err != nil {
+		t.Fatalf("could not parse fixtures %s %s", name, err)
+	}
+
+	ctx := context.Background()
+	fixtures := Fixtures{Users: map[string]*models.User{}}
+	for _, fixture := range file.Users {
+		role, err := store.Roles().FindBySlug(ctx, fixture.Role)
+		if err != nil {
+			t.Fatalf("fixture %s has unknown role %s", fixture.Email, fixture.Role)
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(fixture.Password), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+		profile := models.Profile{Names: null.StringFrom(fixture.Names)}
+		if err = store.Profiles().Insert(ctx, &profile); err != nil {
+			t.Fatalf("could not insert the profile of %s %s", fixture.Email, err)
+		}
+		user := models.User{
+			Email:     fixture.Email,
+			Password:  string(hash),
+			Confirmed: null.BoolFrom(fixture.Confirmed),
+			Deleted:   fixture.Deleted,
+			ProfileID: profile.ID,
+			RoleID:    role.ID,
+		}
+		if err = store.Users().Insert(ctx, &user); err != nil {
+			t.Fatalf("could not insert %s %s", fixture.Email, err)
+		}
+		fixtures.Users[user.Email] = &user
+	}
+	return fixtures
+}
+
+// EachStore runs test against the in-memory store, then against a postgres database of its own when
+// TEST_DATABASE_URL is set.
+func EachStore(t *testing.T, test func(t *testing.T, store models.DataLayer)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, memstore.New())
+	})
+	t.Run("postgres", func(t *testing.T) {
+		test(t, NewDatabase(t))
+	})
+}
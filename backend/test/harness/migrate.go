@@ -0,0 +1,65 @@
+package harness
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+)
+
+// changeLog is the part of a liquibase changelog the harness understands: includes of other
+// changelogs and change sets made of sql files.
+type changeLog struct {
+	Includes []struct {
+		File string `xml:"file,attr"`
+	} `xml:"include"`
+	ChangeSets []struct {
+		ID       string `xml:"id,attr"`
+		SQLFiles []struct {
+			Path string `xml:"path,attr"`
+		} `xml:"sqlFile"`
+	} `xml:"changeSet"`
+}
+
+// Migrate applies the liquibase changelogs under database/ to db, in the order liquibase would.
+// Every sql file is sent as a single query, postgres splits the statements itself.
+func Migrate(db *sql.DB) error {
+	return applyChangeLog(db, filepath.Join(repositoryRoot(), "database", "db-changelog-main.xml"))
+}
+
+func applyChangeLog(db *sql.DB, path string) error {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var log changeLog
+	if err = xml.Unmarshal(raw, &log); err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	dir := filepath.Dir(path)
+	for _, include := range log.Includes {
+		if err = applyChangeLog(db, filepath.Join(dir, include.File)); err != nil {
+			return err
+		}
+	}
+	for _, changeSet := range log.ChangeSets {
+		for _, file := range changeSet.SQLFiles {
+			statements, err := ioutil.ReadFile(filepath.Join(dir, file.Path))
+			if err != nil {
+				return err
+			}
+			if _, err = db.Exec(string(statements)); err != nil {
+				return fmt.Errorf("%s change set %s: %s", path, changeSet.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// repositoryRoot is the backend directory, found from this file so tests can run from any package.
+func repositoryRoot() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
@@ -0,0 +1,211 @@
+package http_tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+// keyring is the users fixtures on postgres, with the real api key service verifying their keys.
+type keyring struct {
+	*accounts
+	keys  *services.APIKeyService
+	store *models.DataStore
+}
+
+func newKeyring(t *testing.T) *keyring {
+	store := harness.NewDatabase(t)
+	ctx, logger := context.Background(), logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	return &keyring{
+		accounts: newAccountsIn(t, store),
+		keys:     services.NewAPIKeyService(ctx, store, logger, services.NewAuditService(ctx, store, logger)),
+		store:    store,
+	}
+}
+
+// issue creates a key for the fixture user owner, issued by the fixture user issuer.
+func (k *keyring) issue(t *testing.T, owner, issuer string, scopes ...string) *services.IssuedAPIKey {
+	t.Helper()
+	key, err := k.keys.Create(context.Background(), k.fixtures.Users[owner], k.fixtures.Users[issuer], "ci", scopes, null.Time{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return key
+}
+
+func TestParseAPIKey(t *testing.T) {
+	cases := []struct {
+		key    string
+		prefix string
+		secret string
+		ok     bool
+	}{
+		{"siena_0a1b2c_s3cr3t", "0a1b2c", "s3cr3t", true},
+		{"siena_0a1b2c_", "", "", false},
+		{"siena__s3cr3t", "", "", false},
+		{"other_0a1b2c_s3cr3t", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tc := range cases {
+		prefix, secret, ok := services.ParseAPIKey(tc.key)
+		assert.Equal(t, tc.ok, ok, tc.key)
+		assert.Equal(t, tc.prefix, prefix, tc.key)
+		assert.Equal(t, tc.secret, secret, tc.key)
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keyring := newKeyring(t)
+	session := keyring.token(t, "jane@example.com")
+	keys := map[string]string{
+		"reader": keyring.issue(t, "jane@example.com", "jane@example.com", services.ScopeProfileRead).Secret,
+		"admin":  keyring.issue(t, "admin@example.com", "admin@example.com", services.ScopeAdminRead).Secret,
+	}
+	app := Handlers.App{Logger: logrus.New()}
+	r := gin.New()
+	r.Use(app.ErrorHandler(), Handlers.Authenticate(
+		Handlers.BearerAuthenticator{Verifier: keyring.users},
+		Handlers.APIKeyAuthenticator{Verifier: keyring.keys},
+	))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/admin", Handlers.RequireScope(services.ScopeAdminRead), ok)
+	r.GET("/me", Handlers.RequireScope(services.ScopeProfileRead), ok)
+	r.PUT("/me/locale", Handlers.RequireScope(services.ScopeProfileWrite), ok)
+	r.POST("/me/api-keys", Handlers.DenyAPIKeys(), ok)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		bearer string
+		key    string
+		status int
+	}{
+		{"unknown key", http.MethodGet, "/admin", "", "nope", http.StatusUnauthorized},
+		{"key without scope", http.MethodGet, "/admin", "", "reader", http.StatusForbidden},
+		{"key with scope", http.MethodGet, "/admin", "", "admin", http.StatusOK},
+		{"session is not scoped", http.MethodGet, "/admin", session, "", http.StatusOK},
+		{"key reading the account", http.MethodGet, "/me", "", "reader", http.StatusOK},
+		{"key without write scope", http.MethodPut, "/me/locale", "", "reader", http.StatusForbidden},
+		{"keys can't manage keys", http.MethodPost, "/me/api-keys", "", "admin", http.StatusForbidden},
+		{"session can manage keys", http.MethodPost, "/me/api-keys", session, "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.bearer)
+			}
+			if tc.key != "" {
+				key, ok := keys[tc.key]
+				if !ok {
+					key = tc.key
+				}
+				req.Header.Set(Handlers.APIKeyHeader, key)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
+
+func TestVerifyAPIKey(t *testing.T) {
+	keyring := newKeyring(t)
+	ctx := context.Background()
+	verify := func(key string) error {
+		_, _, err := keyring.keys.VerifyAPIKey(ctx, key, "203.0.113.7")
+		return err
+	}
+
+	issued := keyring.issue(t, "jane@example.com", "jane@example.com", services.ScopeProfileRead)
+	user, key, err := keyring.keys.VerifyAPIKey(ctx, issued.Secret, "203.0.113.7")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "jane@example.com", user.Email)
+		assert.Equal(t, issued.Key.ID, key.ID)
+		assert.Equal(t, services.ScopeProfileRead, key.Scopes)
+	}
+	stored, err := models.FindAPIKey(ctx, keyring.store.Executor, issued.Key.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "203.0.113.7", stored.LastUsedIP.String)
+	}
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(issued.Secret+"x"), "the secret is compared, not just the prefix")
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(services.APIKeyPrefix+"000000000000_"+strings.Repeat("a", 43)))
+
+	revoked := keyring.issue(t, "jane@example.com", "jane@example.com")
+	assert.Nil(t, keyring.keys.Revoke(ctx, revoked.Key.ID, revoked.Key.UserID, revoked.Key.UserID))
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(revoked.Secret))
+
+	expired := keyring.issue(t, "jane@example.com", "jane@example.com")
+	expired.Key.ExpiresAt = null.TimeFrom(time.Now().Add(-time.Second))
+	_, err = expired.Key.Update(ctx, keyring.store.Executor, boil.Whitelist(models.APIKeyColumns.ExpiresAt))
+	assert.Nil(t, err)
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(expired.Secret))
+
+	unconfirmed := keyring.issue(t, "john@example.com", "john@example.com")
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(unconfirmed.Secret))
+
+	keyring.deleteUser(t, "jane@example.com")
+	assert.Equal(t, services.ErrInvalidAPIKey, verify(issued.Secret), "keys die with their owner")
+}
+
+func TestAPIKeysOutliveTheirIssuer(t *testing.T) {
+	keyring := newKeyring(t)
+	ctx := context.Background()
+	issued := keyring.issue(t, "jane@example.com", "admin@example.com")
+
+	_, err := models.Users(qm.Where("id = ?", keyring.fixtures.Users["admin@example.com"].ID)).DeleteAll(ctx, keyring.store.Executor)
+	assert.Nil(t, err)
+	_, _, err = keyring.keys.VerifyAPIKey(ctx, issued.Secret, "203.0.113.7")
+	assert.Nil(t, err)
+	stored, err := models.FindAPIKey(ctx, keyring.store.Executor, issued.Key.ID)
+	if assert.Nil(t, err) {
+		assert.False(t, stored.CreatedBy.Valid)
+	}
+}
+
+func TestDeletedRolesGrantNothing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keyring := newKeyring(t)
+	ctx := context.Background()
+	session := keyring.token(t, "admin@example.com")
+	app := Handlers.App{Logger: logrus.New(), ServiceContainer: &services.ServiceContainer{Store: keyring.store}}
+	r := gin.New()
+	r.Use(app.ErrorHandler(), Handlers.Authenticate(Handlers.BearerAuthenticator{Verifier: keyring.users}))
+	r.GET("/admin", app.RequireRole(services.MasterRoleSlug), func(c *gin.Context) { c.Status(http.StatusOK) })
+	admin := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+session)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, admin())
+
+	role, err := keyring.store.Roles().FindBySlug(ctx, services.MasterRoleSlug)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, keyring.store.Roles().SoftDelete(ctx, role))
+	assert.Equal(t, http.StatusForbidden, admin())
+	owner := keyring.fixtures.Users["admin@example.com"]
+	_, err = keyring.keys.Create(ctx, owner, owner, "ci", []string{services.ScopeAdminRead}, null.Time{})
+	assert.True(t, services.IsKind(err, services.KindValidation))
+}
@@ -0,0 +1,181 @@
+package http_tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/passwords"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const signingKey = "test-signing-key"
+
+// accounts are the users fixtures in a store, with the real user service verifying their tokens.
+type accounts struct {
+	store    models.DataLayer
+	users    *services.UserService
+	fixtures harness.Fixtures
+}
+
+// newAccounts keeps the accounts in memory.
+func newAccounts(t *testing.T) *accounts {
+	return newAccountsIn(t, memstore.New())
+}
+
+func newAccountsIn(t *testing.T, store models.DataLayer) *accounts {
+	assert.Nil(t, os.Setenv("JWT_SIGNING_KEY", signingKey))
+	t.Cleanup(func() { _ = os.Unsetenv("JWT_SIGNING_KEY") })
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	passwordService := services.NewPasswordService(services.PasswordConfig{
+		Policy: passwords.Policy{MinLength: 8, MaxLength: 72},
+		Hasher: passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost},
+	}, logger)
+	return &accounts{
+		store:    store,
+		users:    services.NewUserUserService(context.Background(), store, logger, passwordService, nil, nil),
+		fixtures: harness.LoadFixtures(t, store, "users"),
+	}
+}
+
+// token signs in the fixture user with email.
+func (a *accounts) token(t *testing.T, email string) string {
+	token, err := a.users.IssueJWTToken(a.fixtures.Users[email])
+	assert.Nil(t, err)
+	return token
+}
+
+// deleteUser soft deletes the fixture user with email, its tokens are still signed correctly.
+func (a *accounts) deleteUser(t *testing.T, email string) {
+	user := a.fixtures.Users[email]
+	user.Deleted = true
+	assert.Nil(t, a.store.Users().Update(context.Background(), user, models.UserColumns.Deleted))
+}
+
+// signed signs claims with key, for tokens IssueJWTToken would never make.
+func signed(t *testing.T, method jwt.SigningMethod, key interface{}, claims services.CustomClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.Nil(t, err)
+	return token
+}
+
+func newProtectedRouter(verifier Handlers.TokenVerifier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	app := Handlers.App{Logger: logrus.New()}
+	r := gin.New()
+	r.Use(app.ErrorHandler())
+	r.GET("/protected", Handlers.Authenticate(
+		Handlers.BearerAuthenticator{Verifier: verifier},
+		Handlers.CookieAuthenticator{Verifier: verifier, CookieName: Handlers.AuthCookieName},
+	), func(c *gin.Context) {
+		user := c.MustGet("user").(*models.User)
+		c.JSON(http.StatusOK, map[string]int{"id": user.ID})
+	})
+	return r
+}
+
+func TestParseBearerToken(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		token  string
+		ok     bool
+	}{
+		{"empty header", "", "", false},
+		{"scheme only", "Bearer", "", false},
+		{"scheme with trailing space", "Bearer ", "", false},
+		{"valid", "Bearer abc.def.ghi", "abc.def.ghi", true},
+		{"lowercase scheme", "bearer abc", "abc", true},
+		{"extra whitespace", "  Bearer   abc  ", "abc", true},
+		{"basic scheme", "Basic dXNlcjpwYXNz", "", false},
+		{"too many parts", "Bearer abc def", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			token, ok := Handlers.ParseBearerToken(tc.header)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.token, token)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	accounts := newAccounts(t)
+	jane := accounts.fixtures.Users["jane@example.com"]
+	confirmed := accounts.token(t, "jane@example.com")
+	unconfirmed := accounts.token(t, "john@example.com")
+	deleted := accounts.token(t, "admin@example.com")
+	accounts.deleteUser(t, "admin@example.com")
+	expired := signed(t, jwt.SigningMethodHS256, []byte(signingKey), services.CustomClaims{
+		UserId:         jane.ID,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(-time.Minute).Unix()},
+	})
+	forged := signed(t, jwt.SigningMethodHS256, []byte("guessed-key"), services.CustomClaims{UserId: jane.ID})
+	unsigned := signed(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, services.CustomClaims{UserId: jane.ID})
+	challenge, err := services.NewMFAService(context.Background(), nil, nil, nil).IssueChallenge(jane)
+	assert.Nil(t, err)
+	vanished := signed(t, jwt.SigningMethodHS256, []byte(signingKey), services.CustomClaims{UserId: 404})
+
+	cases := []struct {
+		name    string
+		header  string
+		cookie  string
+		status  int
+		message string
+	}{
+		{"missing credentials", "", "", http.StatusUnauthorized, "Authentication required"},
+		{"malformed header", "Bearer", "", http.StatusUnauthorized, "Malformed authorization header"},
+		{"wrong scheme", "Token " + confirmed, "", http.StatusUnauthorized, "Malformed authorization header"},
+		{"unknown token", "Bearer nope", "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"valid bearer", "Bearer " + confirmed, "", http.StatusOK, ""},
+		{"expired token", "Bearer " + expired, "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"token signed with another key", "Bearer " + forged, "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"unsigned token", "Bearer " + unsigned, "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"mfa challenge token", "Bearer " + challenge, "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"token of a user that is gone", "Bearer " + vanished, "", http.StatusUnauthorized, "Invalid or expired token"},
+		{"unconfirmed user", "Bearer " + unconfirmed, "", http.StatusUnauthorized, "This account has not been confirmed yet"},
+		{"deleted user", "Bearer " + deleted, "", http.StatusUnauthorized, "This account has been deleted"},
+		{"valid cookie", "", confirmed, http.StatusOK, ""},
+		{"invalid cookie", "", "nope", http.StatusUnauthorized, "Invalid or expired token"},
+		{"header wins over cookie", "Bearer nope", confirmed, http.StatusUnauthorized, "Invalid or expired token"},
+	}
+	router := newProtectedRouter(accounts.users)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: Handlers.AuthCookieName, Value: tc.cookie})
+			}
+			rec := httptest.NewRecorder()
+			assert.NotPanics(t, func() { router.ServeHTTP(rec, req) })
+			assert.Equal(t, tc.status, rec.Code)
+			if tc.message == "" {
+				assert.JSONEq(t, fmt.Sprintf(`{"id":%d}`, jane.ID), rec.Body.String())
+				return
+			}
+			var body Handlers.ErrorResponse
+			assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, services.KindUnauthorized, body.Error.Code)
+			assert.Equal(t, tc.message, body.Error.Message)
+		})
+	}
+}
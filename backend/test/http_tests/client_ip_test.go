@@ -0,0 +1,54 @@
+package http_tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	proxies, err := Handlers.ParseTrustedProxies("10.0.0.0/8, 192.0.2.1")
+	if !assert.Nil(t, err) {
+		return
+	}
+	r := gin.New()
+	r.Use(Handlers.ClientIP(proxies))
+	r.GET("/ip", func(c *gin.Context) {
+		c.String(http.StatusOK, Handlers.RequestIP(c))
+	})
+
+	cases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		ip           string
+	}{
+		{"direct client", "198.51.100.1:5555", nil, "198.51.100.1"},
+		{"clients can't forward for others", "198.51.100.1:5555", []string{"203.0.113.7"}, "198.51.100.1"},
+		{"trusted proxy", "10.0.0.5:443", []string{"203.0.113.7"}, "203.0.113.7"},
+		{"only the hops of trusted proxies are believed", "10.0.0.5:443", []string{"1.1.1.1, 203.0.113.7"}, "203.0.113.7"},
+		{"chained proxies", "10.0.0.5:443", []string{"203.0.113.7, 192.0.2.1", "10.1.1.1"}, "203.0.113.7"},
+		{"garbage stops at the proxy", "10.0.0.5:443", []string{"203.0.113.7, " + string(make([]byte, 300))}, "10.0.0.5"},
+		{"only proxies", "10.0.0.5:443", []string{"10.0.0.6"}, "10.0.0.6"},
+		{"addresses are canonical", "[::ffff:198.51.100.1]:5555", nil, "198.51.100.1"},
+		{"ipv6", "[2001:DB8::1]:5555", nil, "2001:db8::1"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header["X-Forwarded-For"] = tc.forwardedFor
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tc.ip, rec.Body.String())
+		})
+	}
+
+	_, err = Handlers.ParseTrustedProxies("10.0.0.0/8, proxy.internal")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,250 @@
+package http_tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apphttp "github.com/ntwarijoshua/siena/internal/http"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/openapi"
+	"github.com/ntwarijoshua/siena/internal/passwords"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// contract serves the api with every request and response checked against the OpenAPI document.
+type contract struct {
+	router     *gin.Engine
+	violations []openapi.Violation
+	// header is sent with every request
+	header http.Header
+}
+
+func newContract(container *services.ServiceContainer) *contract {
+	gin.SetMode(gin.TestMode)
+	contract := &contract{}
+	app := Handlers.App{Logger: container.Logger, ServiceContainer: container}
+	contract.router = apphttp.GetRouter(app, openapi.Middleware(Handlers.APIDocument(), func(violation openapi.Violation) {
+		contract.violations = append(contract.violations, violation)
+	}))
+	return contract
+}
+
+// newMemoryContract serves the routes that don't need postgres from the in-memory store.
+func newMemoryContract(t *testing.T) (*contract, *memstore.Store) {
+	store := memstore.New()
+	return newStoreContract(store), store
+}
+
+// newStoreContract serves the routes that don't need postgres from store.
+func newStoreContract(store models.DataLayer) *contract {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	ctx := context.Background()
+	container := &services.ServiceContainer{Logger: logger, Context: ctx}
+	container.BuildServiceContainer()
+
+	passwordService := services.NewPasswordService(services.PasswordConfig{
+		Policy: passwords.Policy{MinLength: 8, MaxLength: 72},
+		Hasher: passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost},
+	}, logger)
+	mails := services.NewMailQueue(store, harness.NewBroker())
+	container.Register("passwordService", passwordService)
+	container.Register("validationService", services.NewValidationService(ctx, store, logger))
+	container.Register("userService", services.NewUserUserService(ctx, store, logger, passwordService, mails, nil))
+	container.Register("auditService", (*services.AuditService)(nil))
+	return newContract(container)
+}
+
+func (c *contract) do(method, path string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if raw, ok := body.(string); ok {
+		reader = bytes.NewReader([]byte(raw))
+	} else {
+		encoded, _ := json.Marshal(body)
+		reader = bytes.NewReader(encoded)
+	}
+	request := httptest.NewRequest(method, path, reader)
+	for name, values := range c.header {
+		request.Header[name] = values
+	}
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	c.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (c *contract) assertHonoured(t *testing.T) {
+	t.Helper()
+	for _, violation := range c.violations {
+		t.Error(violation)
+	}
+	c.violations = nil
+}
+
+func TestDocumentedRoutesAreServed(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	served := map[[2]string]bool{}
+	for _, route := range contract.router.Routes() {
+		served[[2]string{route.Method, route.Path}] = true
+	}
+	for _, route := range Handlers.APIDocument().Routes() {
+		assert.True(t, served[route], "%s %s is documented but not served", route[0], route[1])
+	}
+}
+
+func TestServedRoutesAreDocumented(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	document := Handlers.APIDocument()
+	for _, route := range contract.router.Routes() {
+		assert.NotNil(t, document.Operation(route.Method, route.Path), "%s %s is served but not documented", route.Method, route.Path)
+	}
+}
+
+func TestSignupContract(t *testing.T) {
+	contract, store := newMemoryContract(t)
+
+	response := contract.do(http.MethodPost, "/api/v1/users", Handlers.CreateUserRequest{
+		Email:    "ada@example.com",
+		Password: "correct horse battery staple",
+		Names:    "Ada Lovelace",
+		DOB:      "1815-12-10",
+	})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), "password")
+	contract.assertHonoured(t)
+
+	logs := store.MailLogList()
+	if assert.Len(t, logs, 1) {
+		var message services.UserTransactionMessage
+		assert.Nil(t, json.Unmarshal([]byte(logs[0].Payload), &message))
+		response = contract.do(http.MethodPost, "/api/v1/users/confirm", Handlers.ConfirmUserRequest{
+			ID:    logs[0].ID,
+			Token: message.Token,
+		})
+		assert.Equal(t, http.StatusOK, response.Code)
+		contract.assertHonoured(t)
+	}
+
+	response = contract.do(http.MethodPost, "/api/v1/users/confirm", Handlers.ConfirmUserRequest{ID: 404, Token: "forged"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	contract.assertHonoured(t)
+}
+
+func TestRequestsOutsideTheContractAreReported(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+
+	response := contract.do(http.MethodPost, "/api/v1/users", `{"email":"not an email","password":"x"}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	if assert.Len(t, contract.violations, 1) {
+		violation := contract.violations[0]
+		assert.Equal(t, 0, violation.Status, "the error response itself is documented")
+		assert.Contains(t, violation.Error(), "names is missing")
+		assert.Contains(t, violation.Error(), "$.email: is not a valid email")
+	}
+}
+
+func TestPublicRoutesContract(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+
+	assert.Equal(t, http.StatusOK, contract.do(http.MethodGet, "/api/v1/auth/oidc", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, contract.do(http.MethodGet, "/api/v1/", nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, contract.do(http.MethodGet, "/api/v1/me", nil).Code)
+	assert.Equal(t, http.StatusOK, contract.do(http.MethodGet, "/healthz", nil).Code)
+	contract.assertHonoured(t)
+}
+
+func TestAuthenticateContract(t *testing.T) {
+	store := harness.NewDatabase(t)
+	harness.LoadFixtures(t, store, "users")
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	container := &services.ServiceContainer{Store: store, Logger: logger, Context: context.Background()}
+	container.BuildServiceContainer()
+	contract := newContract(container)
+
+	response := contract.do(http.MethodPost, "/api/v1/auth", Handlers.AuthenticateUserRequest{
+		Email:    "jane@example.com",
+		Password: "correct horse battery staple",
+	})
+	assert.Equal(t, http.StatusOK, response.Code)
+	response = contract.do(http.MethodPost, "/api/v1/auth", Handlers.AuthenticateUserRequest{
+		Email:    "jane@example.com",
+		Password: "wrong horse",
+	})
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	contract.assertHonoured(t)
+}
+
+func TestResponseDriftIsReported(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	document := openapi.Build(openapi.Info{Title: "drift", Version: "1"}, nil, nil, nil, []openapi.Route{{
+		Method:    http.MethodGet,
+		Path:      "/login/:id",
+		Responses: map[int]interface{}{http.StatusOK: Handlers.AuthenticateUserResponse{}},
+	}})
+	var violations []openapi.Violation
+	router := gin.New()
+	router.Use(openapi.Middleware(document, func(violation openapi.Violation) {
+		violations = append(violations, violation)
+	}))
+	router.GET("/login/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, map[string]interface{}{"message": "hi", "access_token": "t"})
+	})
+	router.GET("/undocumented", func(c *gin.Context) {
+		c.JSON(http.StatusOK, map[string]interface{}{"anything": true})
+	})
+
+	for _, path := range []string{"/login/1", "/undocumented", "/unrouted"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	if assert.Len(t, violations, 2, "requests no route serves aren't checked") {
+		assert.Equal(t, "/login/:id", violations[0].Path)
+		assert.Contains(t, violations[0].Error(), "token is missing")
+		assert.Contains(t, violations[0].Error(), "access_token is not documented")
+		assert.Equal(t, "GET /undocumented request: route is not documented", violations[1].Error())
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+
+	response := contract.do(http.MethodGet, "/api/v1/openapi.json", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	contract.assertHonoured(t)
+	var document openapi.Document
+	if assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &document)) {
+		assert.Equal(t, openapi.Version, document.OpenAPI)
+		signup := document.Operation(http.MethodPost, "/api/v1/users")
+		if assert.NotNil(t, signup) {
+			assert.Equal(t, "#/components/schemas/CreateUserRequest", signup.RequestBody.Content["application/json"].Schema.Ref)
+		}
+		request := document.Components.Schemas["CreateUserRequest"]
+		if assert.NotNil(t, request) {
+			assert.ElementsMatch(t, []string{"email", "password", "names", "date_of_birth"}, request.Required)
+			assert.Equal(t, "email", request.Properties["email"].Format)
+		}
+		assert.NotContains(t, document.Components.Schemas["UserResponse"].Properties, "password")
+	}
+
+	docs := contract.do(http.MethodGet, "/api/v1/docs", nil)
+	assert.Equal(t, http.StatusOK, docs.Code)
+	policy := docs.Header().Get("Content-Security-Policy")
+	assert.Contains(t, policy, "script-src 'nonce-")
+	assert.True(t, strings.HasPrefix(policy, "sandbox allow-scripts;"), "the cdn's scripts don't run as the api")
+	assert.NotContains(t, policy, "allow-same-origin")
+	assert.True(t, strings.Contains(docs.Body.String(), "swagger-ui-bundle.js"))
+	assert.Contains(t, docs.Body.String(), `"openapi":"`+openapi.Version+`"`, "the sandboxed page can't fetch the document")
+}
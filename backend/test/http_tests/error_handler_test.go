@@ -0,0 +1,67 @@
+package http_tests
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// failWith serves err through the error handler and returns the response.
+func failWith(err error) (*httptest.ResponseRecorder, Handlers.ErrorResponse) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	app := Handlers.App{Logger: logger}
+	router := gin.New()
+	router.Use(app.RequestLogger(), app.ErrorHandler())
+	router.GET("/fail", func(c *gin.Context) {
+		_ = c.Error(err)
+	})
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fail", nil))
+	var body Handlers.ErrorResponse
+	_ = json.Unmarshal(recorder.Body.Bytes(), &body)
+	return recorder, body
+}
+
+func TestServiceErrorsAreMappedToStatuses(t *testing.T) {
+	statuses := map[*services.ServiceError]int{
+		services.NotFoundError("Profile not found", nil):          http.StatusNotFound,
+		services.ErrEmailTaken:                                    http.StatusConflict,
+		services.ValidationError("The submitted data is invalid"): http.StatusBadRequest,
+		services.ErrInvalidCredentials:                            http.StatusUnauthorized,
+		services.ForbiddenError("You are not allowed to do this"): http.StatusForbidden,
+		services.ErrAccountLocked:                                 http.StatusTooManyRequests,
+		{Kind: "unheard_of", Message: "Something else"}:           http.StatusInternalServerError,
+	}
+	for serviceErr, status := range statuses {
+		response, body := failWith(serviceErr)
+		assert.Equal(t, status, response.Code, serviceErr.Message)
+		assert.Equal(t, serviceErr.Kind, body.Error.Code)
+		assert.Equal(t, serviceErr.Message, body.Error.Message)
+		assert.Equal(t, serviceErr.Fields, body.Error.Details)
+		assert.Equal(t, response.Header().Get(Handlers.RequestIDHeader), body.Error.RequestID)
+		assert.NotEmpty(t, body.Error.RequestID)
+	}
+}
+
+func TestCausesAreNotExposed(t *testing.T) {
+	response, body := failWith(services.UnauthorizedError("Invalid email or password", errors.New("crypto/bcrypt: hashedPassword is not the hash of the given password")))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.NotContains(t, response.Body.String(), "bcrypt")
+
+	response, body = failWith(errors.New(`pq: relation "users" does not exist`))
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, services.KindInternal, body.Error.Code)
+	assert.Equal(t, "An unexpected error occurred", body.Error.Message)
+	assert.NotContains(t, response.Body.String(), "relation")
+}
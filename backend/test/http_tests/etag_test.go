@@ -0,0 +1,60 @@
+package http_tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := Handlers.App{Logger: logrus.New()}
+	names := "jane"
+	r := gin.New()
+	r.Use(app.ErrorHandler())
+	r.GET("/profile", Handlers.ETag(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, map[string]string{"names": names})
+	})
+	r.GET("/missing", Handlers.ETag(), func(c *gin.Context) {
+		_ = c.Error(services.NotFoundError("Profile not found", nil))
+	})
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			request.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	first := get("/profile", "")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.JSONEq(t, `{"names":"jane"}`, first.Body.String())
+	tag := first.Header().Get("ETag")
+	assert.NotEmpty(t, tag)
+	assert.Equal(t, "private, no-cache", first.Header().Get("Cache-Control"))
+
+	unchanged := get("/profile", `"other", W/`+tag)
+	assert.Equal(t, http.StatusNotModified, unchanged.Code)
+	assert.Empty(t, unchanged.Body.String())
+	assert.Equal(t, tag, unchanged.Header().Get("ETag"))
+
+	names = "john"
+	changed := get("/profile", tag)
+	assert.Equal(t, http.StatusOK, changed.Code)
+	assert.JSONEq(t, `{"names":"john"}`, changed.Body.String())
+	assert.NotEqual(t, tag, changed.Header().Get("ETag"))
+
+	missing := get("/missing", "*")
+	assert.Equal(t, http.StatusNotFound, missing.Code, "only successful responses are tagged")
+	assert.Empty(t, missing.Header().Get("ETag"))
+	assert.Contains(t, missing.Body.String(), "Profile not found")
+}
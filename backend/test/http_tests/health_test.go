@@ -0,0 +1,110 @@
+package http_tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apphttp "github.com/ntwarijoshua/siena/internal/http"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// healthRouter serves the api with health checking the dependencies.
+func healthRouter(health *services.HealthService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	container := &services.ServiceContainer{Logger: logger, Context: context.Background()}
+	container.BuildServiceContainer()
+	container.Register("healthService", health)
+	return apphttp.GetRouter(Handlers.App{Logger: logger, ServiceContainer: container})
+}
+
+func probe(router *gin.Engine, path string) (*httptest.ResponseRecorder, services.HealthReport) {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	var report services.HealthReport
+	_ = json.Unmarshal(recorder.Body.Bytes(), &report)
+	return recorder, report
+}
+
+func TestLivenessDoesNotCheckDependencies(t *testing.T) {
+	health := &services.HealthService{}
+	health.RegisterCheck("database", func(context.Context) error { return errors.New("connection refused") })
+
+	response, report := probe(healthRouter(health), "/healthz")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, services.HealthStatusUp, report.Status)
+}
+
+func TestReadinessReportsEveryDependency(t *testing.T) {
+	health := &services.HealthService{}
+	health.RegisterCheck("database", func(context.Context) error { return nil })
+	router := healthRouter(health)
+
+	response, report := probe(router, "/readyz")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, report.Healthy())
+	assert.Equal(t, services.HealthStatusUp, report.Checks["database"].Status)
+
+	// registering a check drops the cached report
+	health.RegisterCheck("nsqd", func(context.Context) error { return errors.New("connection refused") })
+	response, report = probe(router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, services.HealthStatusDown, report.Status)
+	assert.Equal(t, services.HealthStatusUp, report.Checks["database"].Status)
+	assert.Equal(t, services.HealthCheckResult{Status: services.HealthStatusDown, Duration: report.Checks["nsqd"].Duration, Error: "connection refused"}, report.Checks["nsqd"])
+}
+
+func TestReadinessIsCached(t *testing.T) {
+	calls := 0
+	health := &services.HealthService{}
+	health.RegisterCheck("database", func(context.Context) error {
+		calls++
+		return nil
+	})
+	router := healthRouter(health)
+
+	for i := 0; i < 3; i++ {
+		response, _ := probe(router, "/readyz")
+		assert.Equal(t, http.StatusOK, response.Code)
+	}
+	assert.Equal(t, 1, calls, "probes within the cache ttl reuse the report")
+}
+
+func TestReadinessChecksTimeOut(t *testing.T) {
+	health := &services.HealthService{}
+	health.RegisterCheck("mail_transport", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	response, report := probe(healthRouter(health), "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, response.Code)
+	assert.Equal(t, services.HealthStatusDown, report.Checks["mail_transport"].Status)
+	assert.NotEmpty(t, report.Checks["mail_transport"].Error)
+}
+
+func TestAbandonedProbesDoNotCacheAFailure(t *testing.T) {
+	health := &services.HealthService{}
+	health.RegisterCheck("database", func(ctx context.Context) error { return ctx.Err() })
+	router := healthRouter(health)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusOK, recorder.Code, "the checks don't run on the probe's context")
+
+	response, report := probe(router, "/readyz")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, services.HealthStatusUp, report.Checks["database"].Status)
+}
@@ -0,0 +1,91 @@
+package http_tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsAreLocalized(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	contract.header = http.Header{"Accept-Language": {"de-DE, fr-CA;q=0.8, en;q=0.5"}}
+
+	response := contract.do(http.MethodPost, "/api/v1/users", Handlers.CreateUserRequest{
+		Email:    "ada@example.com",
+		Password: "correct horse battery staple",
+		DOB:      "1815-12-10",
+	})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, "fr", response.Header().Get("Content-Language"))
+	assert.Equal(t, "Accept-Language", response.Header().Get("Vary"))
+	var body Handlers.ErrorResponse
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "Les données envoyées sont invalides", body.Error.Message)
+	if assert.Len(t, body.Error.Details, 1) {
+		assert.Equal(t, "names", body.Error.Details[0].Field)
+		assert.Equal(t, "names est un champ obligatoire", body.Error.Details[0].Message)
+	}
+}
+
+func TestUnsupportedLanguagesAreAnsweredInEnglish(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	contract.header = http.Header{"Accept-Language": {"de, fr;q=0"}}
+
+	response := contract.do(http.MethodPost, "/api/v1/users", `{}`)
+	assert.Equal(t, "en", response.Header().Get("Content-Language"))
+	var body Handlers.ErrorResponse
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "The submitted data is invalid", body.Error.Message)
+}
+
+func TestLocalizedConflictsLookTheSameWhoeverCatchesThem(t *testing.T) {
+	validated, _ := newMemoryContract(t)
+	raced := newStoreContract(racedStore{memstore.New()})
+	for _, contract := range []*contract{validated, raced} {
+		contract.header = http.Header{"Accept-Language": {"rw"}}
+	}
+
+	byValidator := signupConflict(t, validated)
+	byConstraint := signupConflict(t, raced)
+	assert.Equal(t, byValidator, byConstraint)
+	assert.Equal(t, "Hari konti isanzwe ikoresha iyi imeli", byConstraint.Message)
+	if assert.Len(t, byConstraint.Details, 1) {
+		assert.Equal(t, "email yamaze gukoreshwa", byConstraint.Details[0].Message)
+	}
+}
+
+func TestErrorsNamingSomethingAreLocalized(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	contract.header = http.Header{"Accept-Language": {"fr"}}
+
+	response := contract.do(http.MethodGet, "/api/v1/auth/oidc/nowhere", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	var body Handlers.ErrorResponse
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "Fournisseur d'identité inconnu nowhere", body.Error.Message)
+	contract.assertHonoured(t)
+}
+
+func TestPasswordPolicyViolationsAreLocalized(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	contract.header = http.Header{"Accept-Language": {"fr"}}
+
+	response := contract.do(http.MethodPost, "/api/v1/users", Handlers.CreateUserRequest{
+		Email:    "ada@example.com",
+		Password: "short",
+		Names:    "Ada Lovelace",
+		DOB:      "1815-12-10",
+	})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	var body Handlers.ErrorResponse
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	if assert.Len(t, body.Error.Details, 1) {
+		assert.Equal(t, "password", body.Error.Details[0].Field)
+		assert.Equal(t, "password doit contenir au moins 8 caractères", body.Error.Details[0].Message)
+	}
+	contract.assertHonoured(t)
+}
@@ -0,0 +1,118 @@
+package http_tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/logging"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// newLoggedRouter serves /logged behind RequestLogger and the log level endpoints, the entries
+// written are kept by the returned hook.
+func newLoggedRouter() (*gin.Engine, *logrus.Logger, *test.Hook) {
+	gin.SetMode(gin.TestMode)
+	logger, hook := test.NewNullLogger()
+	logger.SetLevel(logrus.InfoLevel)
+	ctx := context.Background()
+	container := &services.ServiceContainer{Logger: logger, Context: ctx}
+	container.Register("validationService", services.NewValidationService(ctx, memstore.New(), logger))
+	container.Register("auditService", (*services.AuditService)(nil))
+	app := Handlers.App{Logger: logger, ServiceContainer: container}
+	r := gin.New()
+	r.Use(app.RequestLogger(), app.ErrorHandler())
+	r.GET("/logged", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context(), app.Logger).Info("handled")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/log-level", app.GetLogLevel)
+	r.PUT("/log-level", app.SetLogLevel)
+	return r, logger, hook
+}
+
+// logged requests /logged with the given request id and returns the id echoed back.
+func logged(r *gin.Engine, requestID string) string {
+	request := httptest.NewRequest(http.MethodGet, "/logged", nil)
+	if requestID != "" {
+		request.Header.Set(Handlers.RequestIDHeader, requestID)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, request)
+	return rec.Header().Get(Handlers.RequestIDHeader)
+}
+
+func TestRequestIDsArePropagated(t *testing.T) {
+	r, _, hook := newLoggedRouter()
+
+	assert.Equal(t, "req-42.abc", logged(r, "req-42.abc"))
+	entries := hook.AllEntries()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "handled", entries[0].Message)
+		assert.Equal(t, "req-42.abc", entries[0].Data["request_id"], "handlers log with the id")
+		assert.Equal(t, "request completed", entries[1].Message)
+		assert.Equal(t, "req-42.abc", entries[1].Data["request_id"])
+		assert.Equal(t, http.StatusNoContent, entries[1].Data["status"])
+	}
+}
+
+func TestInvalidRequestIDsAreReplaced(t *testing.T) {
+	r, _, hook := newLoggedRouter()
+
+	generated := logged(r, "")
+	assert.Len(t, generated, 36, "a uuid is generated")
+	for _, requestID := range []string{
+		strings.Repeat("a", 129),
+		"forged\nlevel=error msg=\"injected\"",
+		"with spaces",
+		"naïve",
+	} {
+		hook.Reset()
+		echoed := logged(r, requestID)
+		assert.NotEqual(t, requestID, echoed)
+		assert.Len(t, echoed, 36)
+		if assert.NotEmpty(t, hook.AllEntries()) {
+			assert.Equal(t, echoed, hook.LastEntry().Data["request_id"])
+		}
+	}
+	assert.NotEqual(t, generated, logged(r, ""), "every request gets its own")
+}
+
+func TestLogLevelCanBeChanged(t *testing.T) {
+	r, logger, _ := newLoggedRouter()
+	level := func(method, body string) (int, string) {
+		request := httptest.NewRequest(method, "/log-level", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, request)
+		var response Handlers.LogLevelResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &response)
+		return rec.Code, response.Level
+	}
+
+	code, current := level(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "info", current)
+
+	code, current = level(http.MethodPut, `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "debug", current)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+
+	for _, body := range []string{`{"level":"chatty"}`, `{}`} {
+		code, _ = level(http.MethodPut, body)
+		assert.Equal(t, http.StatusBadRequest, code, body)
+	}
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel(), "refused levels change nothing")
+	_, current = level(http.MethodGet, "")
+	assert.Equal(t, "debug", current)
+}
@@ -0,0 +1,152 @@
+package http_tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/ratelimit"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitHeadersAndRejection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	app := Handlers.App{Logger: logrus.New()}
+	r := gin.New()
+	r.Use(app.ErrorHandler())
+	r.GET("/limited", Handlers.RateLimitWith(
+		ratelimit.NewMemoryStore(), "test", ratelimit.Limit{Requests: 2, Period: time.Hour}, Handlers.RateLimitByIP,
+	), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	hit := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := hit("10.0.0.1")
+	assert.Equal(t, http.StatusNoContent, first.Code)
+	assert.Equal(t, "2", first.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", first.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, first.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusNoContent, hit("10.0.0.1").Code)
+	rejected := hit("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rejected.Code)
+	assert.Equal(t, "0", rejected.Header().Get("RateLimit-Remaining"))
+	assert.NotEmpty(t, rejected.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusNoContent, hit("10.0.0.2").Code, "other clients keep their own budget")
+}
+
+// brokenStore is a rate limit store that is down.
+type brokenStore struct{}
+
+func (brokenStore) Hit(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimitFailsClosed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	app := Handlers.App{Logger: logger}
+	r := gin.New()
+	r.Use(app.ErrorHandler())
+	r.GET("/limited", Handlers.RateLimitWith(
+		brokenStore{}, "test", ratelimit.PerMinute(10), Handlers.RateLimitByIP,
+	), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/limited", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "connection refused")
+}
+
+func TestForwardedForDoesNotBuyANewBudget(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 21; i++ {
+		request := httptest.NewRequest(http.MethodPost, "/api/v1/users/restore", strings.NewReader(`{}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		last = httptest.NewRecorder()
+		contract.router.ServeHTTP(last, request)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, last.Code, "signup allows 20 requests an hour to an address")
+}
+
+func TestMadeUpCredentialsAreRateLimited(t *testing.T) {
+	contract, _ := newMemoryContract(t)
+	contract.header = http.Header{"Authorization": {"Bearer made-up"}}
+
+	response := contract.do(http.MethodGet, "/api/v1/", nil)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "600", response.Header().Get("RateLimit-Limit"), "the address is counted before the credentials are checked")
+	contract.header = http.Header{Handlers.APIKeyHeader: {"made-up"}}
+	response = contract.do(http.MethodGet, "/api/v1/", nil)
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Equal(t, "598", response.Header().Get("RateLimit-Remaining"))
+}
+
+func TestAPIKeyHeadersDoNotBuyASessionANewBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	accounts := newAccounts(t)
+	app := Handlers.App{Logger: logrus.New()}
+	r := gin.New()
+	r.Use(app.ErrorHandler(), Handlers.Authenticate(Handlers.BearerAuthenticator{Verifier: accounts.users}))
+	r.GET("/limited", Handlers.RateLimitWith(
+		ratelimit.NewMemoryStore(), "api", ratelimit.Limit{Requests: 2, Period: time.Hour}, Handlers.FirstRateLimitKey(
+			Handlers.RateLimitByAPIKey, Handlers.RateLimitByUser, Handlers.RateLimitByIP,
+		),
+	), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	token := accounts.token(t, "jane@example.com")
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 3; i++ {
+		request := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		request.Header.Set(Handlers.APIKeyHeader, fmt.Sprintf("junk-%d", i))
+		last = httptest.NewRecorder()
+		r.ServeHTTP(last, request)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, last.Code, "the session is counted, not the key it didn't authenticate with")
+}
+
+func TestMemoryStoreSlidingWindow(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 10; i++ {
+		result, err := store.Hit(context.Background(), "k", limit, start.Add(time.Duration(i)*time.Second))
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+	}
+	// a quarter into the next window three quarters of the previous hits still count
+	result, _ := store.Hit(context.Background(), "k", limit, start.Add(75*time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	// two windows later everything has expired
+	result, _ = store.Hit(context.Background(), "k", limit, start.Add(3*time.Minute))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 9, result.Remaining)
+}
@@ -0,0 +1,108 @@
+package http_tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCORSRouter(origins ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config := Handlers.CORSConfigFromEnv()
+	config.AllowedOrigins = origins
+	config.AllowCredentials = true
+	r := gin.New()
+	r.Use(Handlers.CORS(config), Handlers.SecurityHeaders(true))
+	r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+	return r
+}
+
+func TestCORSPreflight(t *testing.T) {
+	r := newCORSRouter("https://app.siena.rw")
+	cases := []struct {
+		name   string
+		origin string
+		status int
+		allow  string
+	}{
+		{"allowed origin", "https://app.siena.rw", http.StatusNoContent, "https://app.siena.rw"},
+		{"unknown origin", "https://evil.example", http.StatusForbidden, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+			req.Header.Set("Origin", tc.origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code)
+			assert.Equal(t, tc.allow, w.Header().Get("Access-Control-Allow-Origin"))
+		})
+	}
+}
+
+func TestCORSWildcardNeverAllowsCredentials(t *testing.T) {
+	r := newCORSRouter("*")
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.NotEmpty(t, w.Header().Get("Strict-Transport-Security"))
+}
+
+func TestCSRFProtection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	accounts := newAccounts(t)
+	verifier, token := accounts.users, accounts.token(t, "jane@example.com")
+	app := Handlers.App{Logger: logrus.New()}
+	r := gin.New()
+	r.Use(app.ErrorHandler())
+	r.POST("/protected", Handlers.Authenticate(
+		Handlers.BearerAuthenticator{Verifier: verifier},
+		Handlers.CookieAuthenticator{Verifier: verifier, CookieName: Handlers.AuthCookieName},
+	), Handlers.CSRFProtection(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, map[string]int{"id": c.MustGet("user").(*models.User).ID})
+	})
+
+	cases := []struct {
+		name   string
+		bearer bool
+		cookie string
+		header string
+		status int
+	}{
+		{"bearer needs no csrf token", true, "", "", http.StatusOK},
+		{"cookie without csrf token", false, "", "", http.StatusForbidden},
+		{"cookie with mismatching token", false, "abc", "abd", http.StatusForbidden},
+		{"cookie with matching token", false, "abc", "abc", http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/protected", nil)
+			if tc.bearer {
+				req.Header.Set("Authorization", "Bearer "+token)
+			} else {
+				req.AddCookie(&http.Cookie{Name: Handlers.AuthCookieName, Value: token})
+			}
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: Handlers.CSRFCookieName, Value: tc.cookie})
+			}
+			if tc.header != "" {
+				req.Header.Set(Handlers.CSRFHeader, tc.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package http_tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/stretchr/testify/assert"
+)
+
+// racedStore loses every race: the is_unique validator never sees the account a concurrent signup
+// is about to create, only the unique index does.
+type racedStore struct {
+	*memstore.Store
+}
+
+func (s racedStore) Users() models.UserRepository {
+	return racedUsers{s.Store.Users()}
+}
+
+type racedUsers struct {
+	models.UserRepository
+}
+
+func (racedUsers) EmailExists(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func signupConflict(t *testing.T, contract *contract) Handlers.ErrorBody {
+	signup := Handlers.CreateUserRequest{
+		Email:    "ada@example.com",
+		Password: "correct horse battery staple",
+		Names:    "Ada Lovelace",
+		DOB:      "1815-12-10",
+	}
+	assert.Equal(t, http.StatusOK, contract.do(http.MethodPost, "/api/v1/users", signup).Code)
+	signup.Email = "Ada@Example.com"
+	response := contract.do(http.MethodPost, "/api/v1/users", signup)
+	assert.Equal(t, http.StatusConflict, response.Code)
+	contract.assertHonoured(t)
+
+	var body Handlers.ErrorResponse
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &body))
+	body.Error.RequestID = ""
+	return body.Error
+}
+
+func TestSignupConflictsLookTheSameWhoeverCatchesThem(t *testing.T) {
+	validated, _ := newMemoryContract(t)
+	raced := newStoreContract(racedStore{memstore.New()})
+
+	byValidator := signupConflict(t, validated)
+	byConstraint := signupConflict(t, raced)
+	assert.Equal(t, byValidator, byConstraint)
+	if assert.Len(t, byConstraint.Details, 1) {
+		assert.Equal(t, "email", byConstraint.Details[0].Field)
+		assert.Equal(t, "email is already taken", byConstraint.Details[0].Message)
+	}
+}
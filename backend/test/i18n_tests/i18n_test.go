@@ -0,0 +1,56 @@
+package i18n_tests
+
+import (
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	for _, test := range []struct {
+		preference, acceptLanguage, locale string
+	}{
+		{"", "", "en"},
+		{"", "fr", "fr"},
+		{"", "fr-CA", "fr"},
+		{"", "rw_RW", "rw"},
+		{"", "de, rw;q=0.4, fr;q=0.7", "fr"},
+		{"", "fr;q=0, de", "en"},
+		{"", "*", "en"},
+		{"", "fr;q=oops", "fr"},
+		{"rw", "fr", "rw"},
+		{"de", "fr", "fr"},
+	} {
+		assert.Equal(t, test.locale, i18n.Negotiate(test.preference, test.acceptLanguage), "%q %q", test.preference, test.acceptLanguage)
+	}
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	universal, err := i18n.NewUniversalTranslator()
+	if !assert.Nil(t, err) {
+		return
+	}
+	french, _ := universal.GetTranslator("fr")
+	english, _ := universal.GetTranslator("en")
+
+	assert.Equal(t, "Utilisateur introuvable", i18n.Translate(french, "User not found"))
+	assert.Equal(t, "User not found", i18n.Translate(english, "User not found"))
+	assert.Equal(t, "not in any catalog", i18n.Translate(french, "not in any catalog"))
+}
+
+func TestMessagesNamingSomethingAreTranslatedByTheirTemplate(t *testing.T) {
+	universal, err := i18n.NewUniversalTranslator()
+	if !assert.Nil(t, err) {
+		return
+	}
+	french, _ := universal.GetTranslator("fr")
+	english, _ := universal.GetTranslator("en")
+
+	assert.Equal(t, "Impossible de se connecter avec github", i18n.Translate(french, "Could not sign in with {0}", "github"))
+	assert.Equal(t, "Could not sign in with github", i18n.Translate(english, "Could not sign in with {0}", "github"))
+	assert.Equal(t, "github is unknown", i18n.Translate(french, "{0} is unknown", "github"))
+	assert.Equal(t,
+		"filter[id][like] n'est pas pris en charge, comparez id avec l'un de eq, in",
+		i18n.Translate(french, "{0} is not supported, compare {1} with one of {2}", "filter[id][like]", "id", "eq, in"))
+}
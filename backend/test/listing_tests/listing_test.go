@@ -0,0 +1,159 @@
+package listing_tests
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/listing"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/queries"
+)
+
+func TestMain(m *testing.M) {
+	harness.Main(m)
+}
+
+func parse(t *testing.T, rawQuery string) *listing.Query {
+	t.Helper()
+	params, err := url.ParseQuery(rawQuery)
+	assert.Nil(t, err)
+	query, err := listing.Parse(services.UserListing, params)
+	if !assert.Nil(t, err, rawQuery) {
+		t.FailNow()
+	}
+	return query
+}
+
+func sql(query *listing.Query) (string, []interface{}) {
+	return queries.BuildQuery(models.Users(query.QueryMods()...).Query)
+}
+
+func TestParse(t *testing.T) {
+	query := parse(t, "")
+	assert.Equal(t, 25, query.Limit)
+	assert.Equal(t, []listing.SortKey{{Field: "created_at", Descending: true}, {Field: "id"}}, query.Sort)
+
+	query = parse(t, "limit=500&sort=email,-id&filter[email][prefix]=ada&filter[role_id][in]=1,2&filter[confirmed]=true")
+	assert.Equal(t, 100, query.Limit, "limits are capped")
+	assert.Equal(t, []listing.SortKey{{Field: "email"}, {Field: "id", Descending: true}}, query.Sort)
+	statement, args := sql(query)
+	assert.Contains(t, statement, `WHERE (users.confirmed = $1) AND (users.email LIKE $2) AND ("users"."role_id" IN ($3,$4))`)
+	assert.Contains(t, statement, `ORDER BY users.email, users.id DESC LIMIT 101`)
+	assert.Equal(t, []interface{}{true, "ada%", int64(1), int64(2)}, args)
+
+	statement, args = sql(parse(t, "filter[email][prefix]=100%25_off"))
+	assert.Equal(t, []interface{}{`100\%\_off%`}, args, "wildcards are matched literally")
+	assert.NotContains(t, statement, "email =")
+}
+
+func TestParseRefusesWhatIsNotWhitelisted(t *testing.T) {
+	params, _ := url.ParseQuery("limit=0&sort=password&filter[password]=x&filter[created_at][prefix]=2020&filter[id][gt]=one&cursor=junk")
+	_, err := listing.Parse(services.UserListing, params)
+	errs, ok := err.(listing.ParamErrors)
+	if !assert.True(t, ok) {
+		return
+	}
+	var refused []string
+	for _, paramError := range errs {
+		refused = append(refused, paramError.Param)
+	}
+	// the cursor isn't looked at when the sort it belongs to is refused
+	assert.Equal(t, []string{"limit", "sort", "filter[created_at][prefix]", "filter[id][gt]", "filter[password]"}, refused)
+	assert.Equal(t, "filter[id][gt] should be a number", errs[3].Message)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_off\\`, listing.EscapeLike(`100%_off\`))
+	assert.Equal(t, "user.login", listing.EscapeLike("user.login"))
+}
+
+// rows are users created a minute apart, the query returns them like postgres would given its mods.
+func rows(count int) models.UserSlice {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := make(models.UserSlice, count)
+	for i := range users {
+		users[i] = &models.User{ID: i + 1, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+	}
+	return users
+}
+
+func TestPaging(t *testing.T) {
+	all := rows(5)
+	first := parse(t, "limit=2&sort=created_at")
+	page, err := first.Page(all[:3])
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, models.UserSlice{all[0], all[1]}, page.Rows)
+	assert.Empty(t, page.Prev)
+	assert.NotEmpty(t, page.Next)
+
+	second := parse(t, "limit=2&sort=created_at&cursor="+page.Next)
+	statement, args := sql(second)
+	assert.Contains(t, statement, "WHERE ((users.created_at > $1) OR (users.created_at = $2 AND users.id > $3))")
+	assert.Equal(t, []interface{}{all[1].CreatedAt, all[1].CreatedAt, int64(2)}, args)
+	page, err = second.Page(all[2:5])
+	assert.Nil(t, err)
+	assert.Equal(t, models.UserSlice{all[2], all[3]}, page.Rows)
+	assert.NotEmpty(t, page.Prev)
+
+	// paging back from the second page walks the sort in reverse and puts the rows back in order
+	back := parse(t, "limit=2&sort=created_at&cursor="+page.Prev)
+	statement, _ = sql(back)
+	assert.Contains(t, statement, "(users.created_at < $1) OR (users.created_at = $2 AND users.id < $3)")
+	assert.Contains(t, statement, "ORDER BY users.created_at DESC, users.id DESC")
+	page, err = back.Page(models.UserSlice{all[1], all[0]})
+	assert.Nil(t, err)
+	assert.Equal(t, models.UserSlice{all[0], all[1]}, page.Rows)
+	assert.Empty(t, page.Prev, "the first page has nothing before it")
+	assert.NotEmpty(t, page.Next)
+
+	self, _ := url.Parse("/api/v1/admin/users?limit=2&sort=created_at&cursor=" + page.Next)
+	links := page.Links(self)
+	assert.Equal(t, "/api/v1/admin/users?"+url.Values{"limit": {"2"}, "sort": {"created_at"}, "cursor": {page.Next}}.Encode(), links.Self)
+	assert.Empty(t, links.Prev)
+
+	params, _ := url.ParseQuery("limit=2&sort=-created_at&cursor=" + page.Next)
+	_, err = listing.Parse(services.UserListing, params)
+	assert.IsType(t, listing.ParamErrors{}, err, "cursors only work with the sort they were made for")
+}
+
+func TestListUsers(t *testing.T) {
+	store := harness.NewDatabase(t)
+	fixtures := harness.LoadFixtures(t, store, "users")
+	directory := services.NewUserDirectoryService(context.Background(), store, logrus.New())
+	ctx := context.Background()
+
+	var seen []string
+	params := url.Values{"limit": {"2"}, "sort": {"email"}}
+	for pages := 0; pages < 3; pages++ {
+		query, err := listing.Parse(services.UserListing, params)
+		if !assert.Nil(t, err) {
+			return
+		}
+		page, err := directory.List(ctx, query)
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, user := range page.Rows.(models.UserSlice) {
+			seen = append(seen, user.Email)
+		}
+		if page.Next == "" {
+			break
+		}
+		params.Set("cursor", page.Next)
+	}
+	assert.Equal(t, []string{"admin@example.com", "jane@example.com", "john@example.com"}, seen)
+
+	query := parse(t, "filter[confirmed]=false")
+	page, err := directory.List(ctx, query)
+	if assert.Nil(t, err) && assert.Len(t, page.Rows, 1) {
+		assert.Equal(t, fixtures.Users["john@example.com"].ID, page.Rows.(models.UserSlice)[0].ID)
+	}
+}
@@ -0,0 +1,126 @@
+package metrics_tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/lib/pq"
+	"github.com/nsqio/go-nsq"
+	"github.com/ntwarijoshua/siena/internal/http/Handlers"
+	"github.com/ntwarijoshua/siena/internal/metrics"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	return logger
+}
+
+// newRouter serves /metrics and a route with a parameter, behind the metrics middleware.
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	app := Handlers.App{Logger: newLogger()}
+	r := gin.New()
+	r.Use(app.MetricsMiddleware())
+	r.GET("/metrics", app.Metrics())
+	r.GET("/widgets/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	return r
+}
+
+// scrape returns what prometheus would read from /metrics.
+func scrape(t *testing.T, r *gin.Engine) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestRequestsAreLabelledByRouteTemplate(t *testing.T) {
+	r := newRouter()
+	for _, path := range []string{"/widgets/1", "/widgets/2", "/nowhere/1", "/nowhere/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	body := scrape(t, r)
+	assert.Contains(t, body, `siena_http_requests_total{method="GET",route="/widgets/:id",status="204"} 2`)
+	assert.Contains(t, body, `siena_http_requests_total{method="GET",route="unmatched",status="404"} 2`)
+	assert.Contains(t, body, `siena_http_request_duration_seconds_count{method="GET",route="/widgets/:id",status="204"} 2`)
+	assert.NotContains(t, body, `route="/widgets/1"`, "raw paths would explode the label cardinality")
+	assert.NotContains(t, body, `route="/nowhere/1"`)
+}
+
+func TestConnectionPoolIsExposed(t *testing.T) {
+	// sql.Open doesn't connect, the pool stays empty
+	db, err := sql.Open("postgres", "postgres://localhost/metrics?sslmode=disable")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer func() { _ = db.Close() }()
+	metrics.RegisterDBStats(db)
+
+	body := scrape(t, newRouter())
+	for _, line := range []string{
+		"siena_db_open_connections 0",
+		"siena_db_in_use_connections 0",
+		"siena_db_idle_connections 0",
+		"siena_db_wait_count_total 0",
+		"siena_db_wait_duration_seconds_total 0",
+	} {
+		assert.Contains(t, body, line)
+	}
+}
+
+func TestConsumerIsExposedByTopic(t *testing.T) {
+	consumer, err := nsq.NewConsumer("metrics-topic", "metrics-channel", nsq.NewConfig())
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer consumer.Stop()
+	assert.Nil(t, metrics.RegisterNSQConsumer("metrics-topic", consumer))
+
+	body := scrape(t, newRouter())
+	assert.Contains(t, body, `siena_nsq_messages_received_total{topic="metrics-topic"} 0`)
+	assert.Contains(t, body, `siena_nsq_messages_in_flight{topic="metrics-topic"} 0`)
+	assert.Contains(t, body, `siena_nsq_connections{topic="metrics-topic"} 0`)
+}
+
+func TestMailsAreCountedByTypeAndResult(t *testing.T) {
+	store := memstore.New()
+	broker := harness.NewBroker()
+	transport := &harness.MailTransport{}
+	mailer := services.NewMailerService(context.Background(), store, newLogger(), transport)
+	broker.Subscribe(services.ConfirmationMailTopic, mailer.Consume)
+	queue := services.NewMailQueue(store, broker)
+	send := func() error {
+		assert.Nil(t, queue.Queue(context.Background(), &services.UserTransactionMessage{
+			EmailAddress: "ada@example.com",
+			Token:        "token",
+			Subject:      "Your Siena login link",
+			Type:         models.SupportedMessageType["MAGIC_LINK"],
+		}))
+		return broker.Deliver(services.ConfirmationMailTopic)
+	}
+
+	assert.Nil(t, send())
+	transport.Err = errors.New("mailgun is down")
+	assert.NotNil(t, send())
+
+	body := scrape(t, newRouter())
+	assert.Contains(t, body, `siena_mailer_mails_total{result="success",type="magic_link_mail"} 1`)
+	assert.Contains(t, body, `siena_mailer_mails_total{result="failure",type="magic_link_mail"} 1`)
+}
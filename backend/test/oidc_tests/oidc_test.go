@@ -0,0 +1,92 @@
+package oidc_tests
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/ntwarijoshua/siena/internal/oidc"
+	"github.com/ntwarijoshua/siena/internal/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+)
+
+const redirectURL = "https://api.siena.rw/api/v1/auth/oidc/mock/callback"
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	assert := assert.New(t)
+	server := oidctest.NewServer("siena", "secret")
+	defer server.Close()
+	ctx := context.Background()
+
+	provider, err := oidc.Discover(ctx, server.Config("mock", redirectURL), nil)
+	assert.Nil(err)
+	verifier, challenge, err := oidc.NewPKCE()
+	assert.Nil(err)
+	authURL := provider.AuthCodeURL("the-state", "the-nonce", challenge)
+	parsed, _ := url.Parse(authURL)
+	assert.Equal("S256", parsed.Query().Get("code_challenge_method"))
+
+	code, state, err := server.Authorize(authURL)
+	assert.Nil(err)
+	assert.Equal("the-state", state)
+
+	_, err = provider.Exchange(ctx, code, "wrong-verifier")
+	assert.NotNil(err, "the code must not be redeemable without the verifier")
+
+	code, _, _ = server.Authorize(authURL)
+	tokens, err := provider.Exchange(ctx, code, verifier)
+	assert.Nil(err)
+	claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, "the-nonce")
+	assert.Nil(err)
+	assert.Equal(server.User.Subject, claims.Subject)
+	assert.Equal("jane@siena.rw", claims.Email)
+	assert.True(bool(claims.EmailVerified))
+	assert.Equal("Jane Doe", claims.DisplayName())
+}
+
+func TestVerifyIDTokenRejections(t *testing.T) {
+	server := oidctest.NewServer("siena", "secret")
+	defer server.Close()
+	ctx := context.Background()
+	provider, err := oidc.Discover(ctx, server.Config("mock", redirectURL), nil)
+	assert.Nil(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss": server.URL, "sub": "1", "aud": "siena", "nonce": "n",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+	}
+	cases := []struct {
+		name   string
+		change func(jwt.MapClaims)
+		nonce  string
+	}{
+		{"nonce mismatch", func(jwt.MapClaims) {}, "other"},
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "someone-else" }, "n"},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example" }, "n"},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, "n"},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }, "n"},
+	}
+	_, err = provider.VerifyIDToken(ctx, server.SignIDToken(valid()), "n")
+	assert.Nil(t, err)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := valid()
+			tc.change(claims)
+			_, err := provider.VerifyIDToken(ctx, server.SignIDToken(claims), tc.nonce)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestDiscoverRejectsIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer("siena", "secret")
+	defer server.Close()
+	config := server.Config("mock", redirectURL)
+	config.Issuer = server.URL + "/"
+	_, err := oidc.Discover(context.Background(), config, nil)
+	assert.NotNil(t, err)
+}
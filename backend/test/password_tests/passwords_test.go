@@ -0,0 +1,136 @@
+package password_tests
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/passwords"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var fastArgon2 = passwords.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHasherRoundTrip(t *testing.T) {
+	for _, hasher := range []passwords.Hasher{
+		{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost},
+		{Algorithm: passwords.AlgorithmArgon2id, Argon2: fastArgon2},
+	} {
+		t.Run(hasher.Algorithm, func(t *testing.T) {
+			hash, err := hasher.Hash("correct horse battery staple")
+			assert.Nil(t, err)
+			needsRehash, err := hasher.Verify(hash, "correct horse battery staple")
+			assert.Nil(t, err)
+			assert.False(t, needsRehash)
+			_, err = hasher.Verify(hash, "wrong horse")
+			assert.Equal(t, passwords.ErrMismatch, err)
+		})
+	}
+}
+
+func TestHasherAsksForRehash(t *testing.T) {
+	old := passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}
+	hash, _ := old.Hash("correct horse battery staple")
+
+	higherCost := passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}
+	needsRehash, err := higherCost.Verify(hash, "correct horse battery staple")
+	assert.Nil(t, err)
+	assert.True(t, needsRehash)
+
+	argon := passwords.Hasher{Algorithm: passwords.AlgorithmArgon2id, Argon2: fastArgon2}
+	needsRehash, err = argon.Verify(hash, "correct horse battery staple")
+	assert.Nil(t, err, "bcrypt hashes keep working after switching to argon2id")
+	assert.True(t, needsRehash)
+
+	hash, _ = argon.Hash("correct horse battery staple")
+	longerKey, longerSalt := argon, argon
+	longerKey.Argon2.KeyLength, longerSalt.Argon2.SaltLength = 64, 32
+	for _, hasher := range []passwords.Hasher{longerKey, longerSalt} {
+		needsRehash, err = hasher.Verify(hash, "correct horse battery staple")
+		assert.Nil(t, err)
+		assert.True(t, needsRehash, "%+v", hasher.Argon2)
+	}
+}
+
+func TestBcryptPasswordsAreLimitedInBytes(t *testing.T) {
+	bcryptHasher := passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}
+	argon := passwords.Hasher{Algorithm: passwords.AlgorithmArgon2id, Argon2: fastArgon2}
+	// 42 characters but 82 bytes, bcrypt would only hash the first 36 letters
+	password := strings.Repeat("é", 40) + "A1"
+
+	for _, tc := range []struct {
+		hasher passwords.Hasher
+		rule   string
+	}{
+		{bcryptHasher, "max_length"},
+		{argon, ""},
+	} {
+		policy := passwords.Policy{MinLength: 8, MaxLength: 72, MaxBytes: tc.hasher.MaxPasswordBytes()}
+		violation, err := policy.Check(context.Background(), password)
+		assert.Nil(t, err)
+		if tc.rule == "" {
+			assert.Nil(t, violation, tc.hasher.Algorithm)
+		} else if assert.NotNil(t, violation, tc.hasher.Algorithm) {
+			assert.Equal(t, tc.rule, violation.Rule)
+		}
+	}
+
+	passwordService := services.NewPasswordService(services.PasswordConfig{
+		Policy: passwords.Policy{MinLength: 8, MaxLength: 72},
+		Hasher: bcryptHasher,
+	}, logrus.New())
+	err := passwordService.Validate(context.Background(), password)
+	assert.True(t, services.IsKind(err, services.KindValidation), "the password service caps the bytes for bcrypt on its own")
+}
+
+func TestPolicy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "breaches")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	sum := sha1.Sum([]byte("Breached#2019"))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	rangeFile := "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + digest[5:] + ":42\r\n"
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, digest[:5]+".txt"), []byte(rangeFile), 0600))
+
+	policy := passwords.Policy{
+		MinLength:            8,
+		MaxLength:            72,
+		MinCharacterClasses:  2,
+		Denylist:             map[string]bool{"nightlife2020": true},
+		Breaches:             passwords.RangeDirectory{Dir: dir},
+		MaxBreachOccurrences: 1,
+	}
+	cases := []struct {
+		password string
+		rule     string
+	}{
+		{"Sh0rt", "min_length"},
+		{strings.Repeat("Ab1", 25), "max_length"},
+		{"alllowercase", "complexity"},
+		{"Password1", "common"},
+		{"NightLife2020", "common"},
+		{"Jane.Doe#99", "personal_info"},
+		{"Uwimana1990!", "personal_info"},
+		{"Breached#2019", "breached"},
+		{"Kigali-by-night-7", ""},
+	}
+	for _, tc := range cases {
+		violation, err := policy.Check(context.Background(), tc.password, "jane.doe@siena.rw", "Aline Uwimana")
+		assert.Nil(t, err)
+		if tc.rule == "" {
+			assert.Nil(t, violation, tc.password)
+			continue
+		}
+		if assert.NotNil(t, violation, tc.password) {
+			assert.Equal(t, tc.rule, violation.Rule, tc.password)
+		}
+	}
+}
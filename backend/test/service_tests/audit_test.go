@@ -0,0 +1,55 @@
+package service_tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/audit"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEventsAreNeverLostToTheirColumns(t *testing.T) {
+	store := harness.NewDatabase(t)
+	auditor := services.NewAuditService(context.Background(), store, newLogger())
+
+	for _, tc := range []struct {
+		ip     string
+		stored string
+	}{
+		{"::ffff:203.0.113.7", "203.0.113.7"},
+		{"2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{strings.Repeat("1", 100), ""},
+		{"unknown", ""},
+	} {
+		ctx := audit.WithRequest(context.Background(), audit.Request{IPAddress: tc.ip})
+		auditor.Record(ctx, services.AuditEvent{Action: "test.recorded", TargetType: "user", TargetID: strings.Repeat("7", 150)})
+		events, err := auditor.Query(ctx, services.AuditFilter{Action: "test.recorded", Limit: 1})
+		if assert.Nil(t, err) && assert.Len(t, events, 1, tc.ip) {
+			assert.Equal(t, tc.stored, events[0].IPAddress.String, tc.ip)
+			assert.Len(t, events[0].TargetID.String, 100)
+		}
+	}
+	verifier, err := auditor.Verify(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, verifier.BrokenAt)
+}
+
+func TestEmailTargetsAreStoredAsPseudonyms(t *testing.T) {
+	store := harness.NewDatabase(t)
+	ctx := context.Background()
+	auditor := services.NewAuditService(ctx, store, newLogger())
+	email := strings.Repeat("a", 120) + "@Example.com"
+
+	auditor.Record(ctx, services.AuditEvent{Action: "login.failed", TargetType: services.AuditTargetEmail, TargetID: email})
+	events, err := auditor.Query(ctx, services.AuditFilter{TargetType: services.AuditTargetEmail, TargetID: strings.ToLower(email)})
+	if assert.Nil(t, err) && assert.Len(t, events, 1) {
+		assert.Equal(t, services.EmailPseudonym(email), events[0].TargetID.String)
+		assert.NotContains(t, events[0].TargetID.String, "@")
+	}
+	events, err = auditor.Query(ctx, services.AuditFilter{TargetID: email})
+	assert.Nil(t, err)
+	assert.Len(t, events, 1, "email filters are recognised without the target type")
+}
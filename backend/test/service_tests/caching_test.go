@@ -0,0 +1,87 @@
+package service_tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/cache"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+	"github.com/volatiletech/sqlboiler/boil"
+)
+
+// countingProfiles counts the lookups that reach the store.
+type countingProfiles struct {
+	models.ProfileRepository
+	lookups *int
+}
+
+func (r countingProfiles) FindByID(ctx context.Context, id int) (*models.Profile, error) {
+	*r.lookups++
+	return r.ProfileRepository.FindByID(ctx, id)
+}
+
+type countingStore struct {
+	*memstore.Store
+	lookups *int
+}
+
+func (s countingStore) Profiles() models.ProfileRepository {
+	return countingProfiles{s.Store.Profiles(), s.lookups}
+}
+
+func TestProfileLookupsAreCachedUntilCommitted(t *testing.T) {
+	store := memstore.New()
+	lookups := 0
+	lru := cache.NewLRU(10)
+	services.InvalidateOnWrite(lru, newLogger())
+	profileService := services.NewProfileService(context.Background(), countingStore{store, &lookups}, newLogger(), lru)
+	ctx := context.Background()
+	profile := models.Profile{Names: null.StringFrom("Jane Doe")}
+	assert.Nil(t, store.Profiles().Insert(ctx, &profile))
+	get := func() {
+		_, err := profileService.Get(ctx, profile.ID)
+		assert.Nil(t, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		get()
+	}
+	assert.Equal(t, 1, lookups)
+
+	// a lookup made before the transaction commits would cache the old row again
+	assert.Nil(t, models.RunProfileHooks(ctx, &sql.Tx{}, boil.AfterUpdateHook, &profile))
+	get()
+	assert.Equal(t, 1, lookups, "writes in a transaction are dropped by whoever commits it")
+
+	assert.Nil(t, models.RunProfileHooks(ctx, nil, boil.AfterUpdateHook, &profile))
+	get()
+	assert.Equal(t, 2, lookups)
+
+	_, err := profileService.Get(ctx, 404)
+	assert.True(t, services.IsKind(err, services.KindNotFound))
+	_, err = profileService.Get(ctx, 404)
+	assert.Equal(t, 4, lookups, "misses aren't cached")
+}
+
+func TestProfileLookupsAreCached(t *testing.T) {
+	store := memstore.New()
+	lru := cache.NewLRU(10)
+	profileService := services.NewProfileService(context.Background(), store, newLogger(), lru)
+	ctx := context.Background()
+	profile := models.Profile{Names: null.StringFrom("Jane Doe")}
+	assert.Nil(t, store.Profiles().Insert(ctx, &profile))
+
+	found, err := profileService.Get(ctx, profile.ID)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Jane Doe", found.Names.String)
+	}
+	assert.Equal(t, 1, lru.Len())
+
+	_, err = profileService.Get(ctx, 404)
+	assert.True(t, services.IsKind(err, services.KindNotFound))
+}
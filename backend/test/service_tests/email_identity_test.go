@@ -0,0 +1,63 @@
+package service_tests
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+)
+
+func TestEmailsAreCaseInsensitive(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		ctx := context.Background()
+		users := newMailPipeline(store).users
+		validationService := services.NewValidationService(ctx, store, newLogger())
+
+		created, err := users.CreateUser(ctx, models.User{Email: " Ada@Example.com", Password: password},
+			models.Profile{Names: null.StringFrom("Ada Lovelace")})
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "ada@example.com", created.Email)
+
+		found, err := users.GetUserByMail(ctx, "ADA@example.COM")
+		if assert.Nil(t, err) {
+			assert.Equal(t, created.ID, found.ID)
+		}
+		authenticated, err := users.Authenticate(ctx, "Ada@example.com ", password)
+		if assert.Nil(t, err) {
+			assert.Equal(t, created.ID, authenticated.ID)
+		}
+
+		var payload struct {
+			Email string `json:"email" validate:"email,is_unique"`
+		}
+		payload.Email = "ADA@example.com"
+		assert.NotNil(t, validationService.GetValidator().StructCtx(ctx, payload))
+
+		_, err = users.CreateUser(ctx, models.User{Email: "ADA@EXAMPLE.COM", Password: password},
+			models.Profile{Names: null.StringFrom("Ada Lovelace")})
+		assert.NotNil(t, err, "a second account can't take the address in another case")
+	})
+}
+
+func TestProviderRules(t *testing.T) {
+	assert.Nil(t, os.Setenv("EMAIL_PROVIDER_RULES", "true"))
+	defer os.Unsetenv("EMAIL_PROVIDER_RULES")
+	ctx := context.Background()
+	users := newMailPipeline(memstore.New()).users
+
+	created, err := users.CreateUser(ctx, models.User{Email: "Ada.Lovelace+signup@gmail.com", Password: password},
+		models.Profile{Names: null.StringFrom("Ada Lovelace")})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "adalovelace@gmail.com", created.Email)
+	}
+	_, err = users.Authenticate(ctx, "ada.lovelace@googlemail.com", password)
+	assert.Nil(t, err)
+}
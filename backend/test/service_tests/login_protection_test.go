@@ -0,0 +1,179 @@
+package service_tests
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/queries/qm"
+)
+
+const attackerIP = "203.0.113.7"
+
+var testLoginPolicy = services.LoginProtectionPolicy{
+	Window:             time.Hour,
+	MaxAccountFailures: 3,
+	MaxIPFailures:      5,
+	LockoutDuration:    time.Minute,
+	MaxLockout:         time.Hour,
+	FreeAttempts:       1,
+	DelayStep:          time.Second,
+	MaxDelay:           2 * time.Second,
+}
+
+// loginProtection is a login protection service on postgres with the users fixtures loaded, it keeps
+// the delays it would have slept for and the unlock mails it queued.
+type loginProtection struct {
+	*services.LoginProtectionService
+	store   *models.DataStore
+	delays  []time.Duration
+	unlocks []services.UserTransactionMessage
+	broker  *harness.Broker
+}
+
+func newLoginProtection(t *testing.T) *loginProtection {
+	store := harness.NewDatabase(t)
+	harness.LoadFixtures(t, store, "users")
+	ctx, logger := context.Background(), newLogger()
+	protection := &loginProtection{store: store, broker: harness.NewBroker()}
+	protection.broker.Subscribe(services.ConfirmationMailTopic, func(_ string, body []byte) error {
+		var message services.UserTransactionMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			return err
+		}
+		protection.unlocks = append(protection.unlocks, message)
+		return nil
+	})
+	protection.LoginProtectionService = services.NewLoginProtectionService(
+		ctx, store, logger, testLoginPolicy, services.NewMailQueue(store, protection.broker), services.NewAuditService(ctx, store, logger),
+	)
+	protection.SlowDownWith(func(delay time.Duration) {
+		protection.delays = append(protection.delays, delay)
+	})
+	return protection
+}
+
+func (p *loginProtection) fail(t *testing.T, email, ip string, times int) {
+	t.Helper()
+	for i := 0; i < times; i++ {
+		if !assert.Nil(t, p.RecordFailure(context.Background(), email, ip)) {
+			t.FailNow()
+		}
+	}
+}
+
+// unlockToken returns the token of the last unlock mail sent to email.
+func (p *loginProtection) unlockToken(t *testing.T, email string) string {
+	t.Helper()
+	assert.Nil(t, p.broker.Deliver(services.ConfirmationMailTopic))
+	for i := len(p.unlocks) - 1; i >= 0; i-- {
+		if p.unlocks[i].EmailAddress == email {
+			return p.unlocks[i].Token
+		}
+	}
+	t.Fatalf("no unlock mail was sent to %s", email)
+	return ""
+}
+
+// lockouts returns the lockouts of subject, oldest first.
+func (p *loginProtection) lockouts(t *testing.T, subject string) models.AccountLockoutSlice {
+	lockouts, err := models.AccountLockouts(qm.Where("subject = ?", subject), qm.OrderBy("id")).All(context.Background(), p.store.Executor)
+	assert.Nil(t, err)
+	return lockouts
+}
+
+func TestAccountIsLockedAfterRepeatedFailures(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	protection.fail(t, "jane@example.com", attackerIP, 2)
+	assert.Nil(t, protection.CheckAllowed(ctx, "jane@example.com", attackerIP))
+	protection.fail(t, "Jane@Example.com", attackerIP, 1)
+
+	assert.Equal(t, services.ErrAccountLocked, protection.CheckAllowed(ctx, "jane@example.com", attackerIP))
+	assert.Equal(t, services.ErrAccountLocked, protection.CheckAllowed(ctx, "jane@example.com", "198.51.100.1"), "the account is locked wherever the login comes from")
+	assert.Nil(t, protection.CheckAllowed(ctx, "john@example.com", attackerIP), "the ip isn't locked yet")
+	assert.Equal(t, []time.Duration{0, time.Second, 2 * time.Second}, protection.delays, "failures past the free attempts are slowed down")
+	assert.NotEmpty(t, protection.unlockToken(t, "jane@example.com"))
+}
+
+func TestSuccessfulLoginsResetTheFailureCount(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	protection.fail(t, "jane@example.com", attackerIP, 2)
+	assert.Nil(t, protection.RecordSuccess(ctx, "jane@example.com", attackerIP))
+	protection.fail(t, "jane@example.com", attackerIP, 2)
+	assert.Nil(t, protection.CheckAllowed(ctx, "jane@example.com", attackerIP))
+}
+
+func TestUnlockForgivesTheFailures(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	protection.fail(t, "jane@example.com", attackerIP, 3)
+	token := protection.unlockToken(t, "jane@example.com")
+	assert.True(t, services.IsKind(protection.Unlock(ctx, "forged", "198.51.100.1"), services.KindValidation))
+	assert.Nil(t, protection.Unlock(ctx, token, "198.51.100.1"))
+	assert.Nil(t, protection.CheckAllowed(ctx, "jane@example.com", attackerIP))
+	assert.True(t, services.IsKind(protection.Unlock(ctx, token, "198.51.100.1"), services.KindValidation), "unlock links are single use")
+
+	// a typo after unlocking doesn't lock the owner out again
+	protection.fail(t, "jane@example.com", "198.51.100.1", 1)
+	assert.Nil(t, protection.CheckAllowed(ctx, "jane@example.com", "198.51.100.1"))
+	assert.Len(t, protection.lockouts(t, "jane@example.com"), 1)
+	assert.Len(t, protection.unlocks, 1)
+}
+
+func TestRepeatedLockoutsLastLonger(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	protection.fail(t, "jane@example.com", attackerIP, 3)
+	assert.Nil(t, protection.Unlock(ctx, protection.unlockToken(t, "jane@example.com"), "198.51.100.1"))
+	protection.fail(t, "jane@example.com", attackerIP, 3)
+
+	lockouts := protection.lockouts(t, "jane@example.com")
+	if assert.Len(t, lockouts, 2) {
+		first := lockouts[0].LockedUntil.Sub(lockouts[0].CreatedAt)
+		second := lockouts[1].LockedUntil.Sub(lockouts[1].CreatedAt)
+		assert.InDelta(t, float64(time.Minute), float64(first), float64(time.Second))
+		assert.InDelta(t, float64(2*time.Minute), float64(second), float64(time.Second))
+	}
+}
+
+func TestAddressIsLockedAfterFailuresOnManyAccounts(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"} {
+		protection.fail(t, email, attackerIP, 1)
+	}
+	assert.Nil(t, protection.CheckAllowed(ctx, "e@example.com", attackerIP))
+	// the same address written another way is still the same address
+	protection.fail(t, "e@example.com", "::ffff:"+attackerIP, 1)
+
+	assert.Equal(t, services.ErrAccountLocked, protection.CheckAllowed(ctx, "jane@example.com", attackerIP))
+	assert.Nil(t, protection.CheckAllowed(ctx, "jane@example.com", "198.51.100.1"))
+	assert.Len(t, protection.lockouts(t, attackerIP), 1)
+	assert.Zero(t, protection.broker.Pending(services.ConfirmationMailTopic), "nobody owns an address to unlock it")
+}
+
+func TestAttemptsFromUnknownAddressesAreRefused(t *testing.T) {
+	protection := newLoginProtection(t)
+	ctx := context.Background()
+
+	for _, ip := range []string{"", "unknown", strings.Repeat("1", 100)} {
+		assert.Equal(t, services.ErrUnknownClientIP, protection.CheckAllowed(ctx, "jane@example.com", ip))
+		assert.Equal(t, services.ErrUnknownClientIP, protection.RecordFailure(ctx, "jane@example.com", ip))
+	}
+	count, err := models.LoginAttempts().Count(ctx, protection.store.Executor)
+	assert.Nil(t, err)
+	assert.Zero(t, count)
+}
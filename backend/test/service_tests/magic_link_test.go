@@ -0,0 +1,168 @@
+package service_tests
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+// magicLinks is a magic link service on postgres with the users fixtures loaded. Links are sent when
+// the test calls send, and the mails are kept.
+type magicLinks struct {
+	*services.MagicLinkService
+	tasks  []func()
+	mails  []services.UserTransactionMessage
+	broker *harness.Broker
+}
+
+func newMagicLinks(t *testing.T, options services.MagicLinkOptions) *magicLinks {
+	store := harness.NewDatabase(t)
+	harness.LoadFixtures(t, store, "users")
+	ctx, logger := context.Background(), newLogger()
+	links := &magicLinks{broker: harness.NewBroker()}
+	links.broker.Subscribe(services.ConfirmationMailTopic, func(_ string, body []byte) error {
+		var message services.UserTransactionMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			return err
+		}
+		links.mails = append(links.mails, message)
+		return nil
+	})
+	links.MagicLinkService = services.NewMagicLinkService(
+		ctx, store, logger, options, services.NewMailQueue(store, links.broker), services.NewAuditService(ctx, store, logger),
+	)
+	links.DispatchWith(func(task func()) {
+		links.tasks = append(links.tasks, task)
+	})
+	return links
+}
+
+// send runs the lookups RequestLink left behind and returns the tokens that were mailed.
+func (l *magicLinks) send(t *testing.T) []string {
+	t.Helper()
+	for _, task := range l.tasks {
+		task()
+	}
+	l.tasks, l.mails = nil, nil
+	assert.Nil(t, l.broker.Deliver(services.ConfirmationMailTopic))
+	var tokens []string
+	for _, mail := range l.mails {
+		tokens = append(tokens, mail.Token)
+	}
+	return tokens
+}
+
+func TestLinksAreOnlyMailedToActiveAccounts(t *testing.T) {
+	links := newMagicLinks(t, services.MagicLinkOptions{Lifetime: time.Minute})
+	ctx := context.Background()
+
+	for _, email := range []string{"nobody@example.com", "john@example.com", "Jane@Example.com"} {
+		links.RequestLink(ctx, email, attackerIP, "")
+		assert.Zero(t, links.broker.Pending(services.ConfirmationMailTopic), "%s was looked up before the request was answered", email)
+	}
+	tokens := links.send(t)
+	if assert.Len(t, tokens, 1, "unknown and unconfirmed accounts get nothing") {
+		assert.Equal(t, "jane@example.com", links.mails[0].EmailAddress)
+	}
+}
+
+func TestLinksAreSingleUse(t *testing.T) {
+	links := newMagicLinks(t, services.MagicLinkOptions{Lifetime: time.Minute})
+	ctx := context.Background()
+
+	links.RequestLink(ctx, "jane@example.com", attackerIP, "")
+	first := links.send(t)
+	links.RequestLink(ctx, "jane@example.com", attackerIP, "")
+	second := links.send(t)
+	if !assert.Len(t, first, 1) || !assert.Len(t, second, 1) {
+		return
+	}
+
+	_, err := links.Redeem(ctx, first[0], attackerIP, "")
+	assert.Equal(t, services.ErrInvalidMagicLink, err, "a new link revokes the ones before it")
+	user, err := links.Redeem(ctx, second[0], attackerIP, "")
+	if assert.Nil(t, err) {
+		assert.Equal(t, "jane@example.com", user.Email)
+	}
+	_, err = links.Redeem(ctx, second[0], attackerIP, "")
+	assert.Equal(t, services.ErrInvalidMagicLink, err)
+	_, err = links.Redeem(ctx, "forged", attackerIP, "")
+	assert.Equal(t, services.ErrInvalidMagicLink, err)
+}
+
+func TestLinksRedeemedConcurrentlyAreAcceptedOnce(t *testing.T) {
+	links := newMagicLinks(t, services.MagicLinkOptions{Lifetime: time.Minute})
+	links.RequestLink(context.Background(), "jane@example.com", attackerIP, "")
+	tokens := links.send(t)
+	if !assert.Len(t, tokens, 1) {
+		return
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan error, 8)
+	for i := 0; i < cap(results); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := links.Redeem(context.Background(), tokens[0], attackerIP, "")
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+	accepted := 0
+	for err := range results {
+		if err == nil {
+			accepted++
+		} else {
+			assert.Equal(t, services.ErrInvalidMagicLink, err)
+		}
+	}
+	assert.Equal(t, 1, accepted, "the link was accepted %d times", accepted)
+}
+
+func TestExpiredLinksAreRefused(t *testing.T) {
+	links := newMagicLinks(t, services.MagicLinkOptions{Lifetime: -time.Minute})
+	ctx := context.Background()
+
+	links.RequestLink(ctx, "jane@example.com", attackerIP, "")
+	tokens := links.send(t)
+	if assert.Len(t, tokens, 1) {
+		_, err := links.Redeem(ctx, tokens[0], attackerIP, "")
+		assert.Equal(t, services.ErrInvalidMagicLink, err)
+	}
+}
+
+func TestBoundLinksAreBurntByOtherClients(t *testing.T) {
+	links := newMagicLinks(t, services.MagicLinkOptions{Lifetime: time.Minute, BindIP: true, BindDevice: true})
+	ctx := context.Background()
+
+	for _, attempt := range []struct{ name, ip, deviceID string }{
+		{"another address", "198.51.100.1", "device"},
+		{"an unknown address", "", "device"},
+		{"another device", attackerIP, "other-device"},
+	} {
+		links.RequestLink(ctx, "jane@example.com", attackerIP, "device")
+		tokens := links.send(t)
+		if !assert.Len(t, tokens, 1) {
+			return
+		}
+		_, err := links.Redeem(ctx, tokens[0], attempt.ip, attempt.deviceID)
+		assert.Equal(t, services.ErrInvalidMagicLink, err, attempt.name)
+		_, err = links.Redeem(ctx, tokens[0], attackerIP, "device")
+		assert.Equal(t, services.ErrInvalidMagicLink, err, "the link was burnt by %s", attempt.name)
+	}
+
+	links.RequestLink(ctx, "jane@example.com", attackerIP, "device")
+	tokens := links.send(t)
+	if assert.Len(t, tokens, 1) {
+		_, err := links.Redeem(ctx, tokens[0], attackerIP, "device")
+		assert.Nil(t, err)
+	}
+}
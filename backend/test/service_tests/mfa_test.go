@@ -0,0 +1,158 @@
+package service_tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/internal/totp"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+// mfaAccount is jane, on postgres, halfway through enrollment with a clock tests move by hand.
+type mfaAccount struct {
+	*services.MFAService
+	user   *models.User
+	secret string
+	clock  time.Time
+}
+
+func newMFAAccount(t *testing.T) *mfaAccount {
+	store := harness.NewDatabase(t)
+	fixtures := harness.LoadFixtures(t, store, "users")
+	ctx, logger := context.Background(), newLogger()
+	account := &mfaAccount{
+		MFAService: services.NewMFAService(ctx, store, logger, services.NewAuditService(ctx, store, logger)),
+		user:       fixtures.Users["jane@example.com"],
+		clock:      time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	account.UseClock(func() time.Time { return account.clock })
+	enrollment, err := account.BeginEnrollment(ctx, account.user)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	account.secret = enrollment.Secret
+	return account
+}
+
+// code returns the code of the current period.
+func (a *mfaAccount) code(t *testing.T) string {
+	t.Helper()
+	code, err := totp.Code(a.secret, totp.Step(a.clock))
+	assert.Nil(t, err)
+	return code
+}
+
+func (a *mfaAccount) enable(t *testing.T) []string {
+	t.Helper()
+	codes, err := a.ConfirmEnrollment(context.Background(), a.user, a.code(t))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	a.clock = a.clock.Add(totp.Period)
+	return codes
+}
+
+func TestEnrollmentNeedsAValidCode(t *testing.T) {
+	account := newMFAAccount(t)
+	ctx := context.Background()
+
+	_, err := account.ConfirmEnrollment(ctx, account.user, "000000")
+	assert.Equal(t, services.ErrInvalidMFACode, err)
+	enabled, err := account.Enabled(ctx, account.user.ID)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	codes := account.enable(t)
+	assert.Len(t, codes, services.RecoveryCodeCount)
+	enabled, err = account.Enabled(ctx, account.user.ID)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+	_, err = account.ConfirmEnrollment(ctx, account.user, account.code(t))
+	assert.True(t, services.IsKind(err, services.KindConflict))
+}
+
+func TestCodesCannotBeReplayed(t *testing.T) {
+	account := newMFAAccount(t)
+	ctx := context.Background()
+	account.enable(t)
+
+	code := account.code(t)
+	assert.Nil(t, account.VerifyCode(ctx, account.user, code))
+	assert.Equal(t, services.ErrInvalidMFACode, account.VerifyCode(ctx, account.user, code))
+
+	account.clock = account.clock.Add(totp.Period)
+	assert.Nil(t, account.VerifyCode(ctx, account.user, account.code(t)))
+}
+
+func TestCodesUsedConcurrentlyAreAcceptedOnce(t *testing.T) {
+	account := newMFAAccount(t)
+	recoveryCodes := account.enable(t)
+	code := account.code(t)
+
+	for _, attempt := range []string{code, recoveryCodes[0]} {
+		var wg sync.WaitGroup
+		results := make(chan error, 8)
+		for i := 0; i < cap(results); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results <- account.VerifyCode(context.Background(), account.user, attempt)
+			}()
+		}
+		wg.Wait()
+		close(results)
+		accepted := 0
+		for err := range results {
+			if err == nil {
+				accepted++
+			} else {
+				assert.Equal(t, services.ErrInvalidMFACode, err)
+			}
+		}
+		assert.Equal(t, 1, accepted, "%s was accepted %d times", attempt, accepted)
+	}
+}
+
+func TestRecoveryCodesAreSingleUse(t *testing.T) {
+	account := newMFAAccount(t)
+	ctx := context.Background()
+	codes := account.enable(t)
+
+	assert.Nil(t, account.VerifyCode(ctx, account.user, codes[0]))
+	assert.Equal(t, services.ErrInvalidMFACode, account.VerifyCode(ctx, account.user, codes[0]))
+	assert.Nil(t, account.VerifyCode(ctx, account.user, " "+codes[1][:5]+codes[1][6:]+" "), "the dash and spaces are optional")
+
+	regenerated, err := account.RegenerateRecoveryCodes(ctx, account.user, account.code(t))
+	assert.Nil(t, err)
+	assert.Len(t, regenerated, services.RecoveryCodeCount)
+	assert.Equal(t, services.ErrInvalidMFACode, account.VerifyCode(ctx, account.user, codes[2]), "regenerating burns the old codes")
+	assert.Nil(t, account.VerifyCode(ctx, account.user, regenerated[0]))
+}
+
+func TestDisableAndReset(t *testing.T) {
+	account := newMFAAccount(t)
+	ctx := context.Background()
+	account.enable(t)
+
+	assert.Equal(t, services.ErrInvalidMFACode, account.Disable(ctx, account.user, "000000"))
+	assert.Nil(t, account.Disable(ctx, account.user, account.code(t)))
+	enabled, err := account.Enabled(ctx, account.user.ID)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+	assert.Equal(t, services.ErrMFANotEnabled, account.VerifyCode(ctx, account.user, account.code(t)))
+
+	enrollment, err := account.BeginEnrollment(ctx, account.user)
+	assert.Nil(t, err)
+	account.secret = enrollment.Secret
+	account.enable(t)
+	assert.Nil(t, account.Reset(ctx, account.user.ID))
+	enabled, err = account.Enabled(ctx, account.user.ID)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+	assert.True(t, services.IsKind(account.Reset(ctx, 1<<30), services.KindNotFound))
+}
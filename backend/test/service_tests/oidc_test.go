@@ -0,0 +1,81 @@
+package service_tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/oidc"
+	"github.com/ntwarijoshua/siena/internal/oidc/oidctest"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+const oidcRedirectURL = "https://api.siena.rw/api/v1/auth/oidc/mock/callback"
+
+func newOIDCService(t *testing.T, configs ...oidc.Config) *services.OIDCService {
+	store := harness.NewDatabase(t)
+	ctx, logger := context.Background(), newLogger()
+	auditor := services.NewAuditService(ctx, store, logger)
+	return services.NewOIDCService(ctx, store, logger, newUserService(store, nil), auditor, configs)
+}
+
+func TestOIDCStatesAreSingleUse(t *testing.T) {
+	server := oidctest.NewServer("siena", "secret")
+	defer server.Close()
+	oidcService := newOIDCService(t, server.Config("mock", oidcRedirectURL), server.Config("other", oidcRedirectURL))
+	ctx := context.Background()
+
+	authURL, state, err := oidcService.Begin(ctx, "mock", 0)
+	if !assert.Nil(t, err) {
+		return
+	}
+	code, _, err := server.Authorize(authURL)
+	assert.Nil(t, err)
+
+	_, err = oidcService.Complete(ctx, "other", code, state)
+	assert.Equal(t, services.ErrInvalidOIDCState, err, "states only work with the provider they were issued for")
+	result, err := oidcService.Complete(ctx, "mock", code, state)
+	if assert.Nil(t, err) {
+		assert.True(t, result.Created)
+	}
+	code, _, _ = server.Authorize(authURL)
+	_, err = oidcService.Complete(ctx, "mock", code, state)
+	assert.Equal(t, services.ErrInvalidOIDCState, err)
+}
+
+func TestSlowDiscoveryDoesNotHoldUpOtherProviders(t *testing.T) {
+	server := oidctest.NewServer("siena", "secret")
+	defer server.Close()
+	discovering, release := make(chan struct{}), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(discovering)
+		<-release
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer slow.Close()
+	defer close(release)
+	slowConfig := server.Config("slow", oidcRedirectURL)
+	slowConfig.Issuer = slow.URL
+	oidcService := newOIDCService(t, server.Config("mock", oidcRedirectURL), slowConfig)
+	ctx := context.Background()
+
+	go func() {
+		_, _, _ = oidcService.Begin(ctx, "slow", 0)
+	}()
+	<-discovering
+	began := make(chan error, 1)
+	go func() {
+		_, _, err := oidcService.Begin(ctx, "mock", 0)
+		began <- err
+	}()
+	select {
+	case err := <-began:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("signing in with mock waited for the discovery of slow")
+	}
+}
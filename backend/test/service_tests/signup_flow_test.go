@@ -0,0 +1,202 @@
+package service_tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+)
+
+// mailPipeline wires a user service to the mailer consumer through the in-memory broker, with mails
+// ending up in the fake transport.
+type mailPipeline struct {
+	users     *services.UserService
+	broker    *harness.Broker
+	transport *harness.MailTransport
+}
+
+func newMailPipeline(store models.DataLayer) mailPipeline {
+	pipeline := mailPipeline{broker: harness.NewBroker(), transport: &harness.MailTransport{}}
+	mailer := services.NewMailerService(context.Background(), store, newLogger(), pipeline.transport)
+	pipeline.broker.Subscribe(services.ConfirmationMailTopic, mailer.Consume)
+	pipeline.users = newUserService(store, services.NewMailQueue(store, pipeline.broker))
+	return pipeline
+}
+
+// confirmationLink returns the tracking id and token of the confirmation link in mail.
+func confirmationLink(t *testing.T, mail services.Mail) (int, string) {
+	link, err := harness.Link(mail, "confirmation_link")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	trackingID, err := strconv.Atoi(link.Query().Get("id"))
+	assert.Nil(t, err)
+	return trackingID, link.Query().Get("token")
+}
+
+func TestSignupConfirmationAndLogin(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		ctx := context.Background()
+		pipeline := newMailPipeline(store)
+
+		created, err := pipeline.users.CreateUser(
+			ctx,
+			models.User{Email: "ada@example.com", Password: password},
+			models.Profile{Names: null.StringFrom("Ada Lovelace")},
+		)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.False(t, created.Confirmed.Bool)
+		assert.NotEqual(t, password, created.Password)
+		assert.Equal(t, 1, pipeline.broker.Pending(services.ConfirmationMailTopic))
+		assert.Empty(t, pipeline.transport.Sent())
+
+		assert.Nil(t, pipeline.broker.Deliver(services.ConfirmationMailTopic))
+		assert.Equal(t, 0, pipeline.broker.Pending(services.ConfirmationMailTopic))
+		sent := pipeline.transport.Sent()
+		if !assert.Len(t, sent, 1) {
+			return
+		}
+		assert.Equal(t, "ada@example.com", sent[0].To)
+		assert.Equal(t, "account-confirmation-email", sent[0].Template)
+		trackingID, token := confirmationLink(t, sent[0])
+
+		mailLog, err := store.MailLogs().FindByID(ctx, trackingID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, models.SupportedStatus["SENT"], mailLog.Status)
+		}
+
+		_, err = pipeline.users.ConfirmUser(ctx, trackingID, token+"-forged")
+		assert.True(t, services.IsKind(err, services.KindValidation))
+		confirmed, err := pipeline.users.ConfirmUser(ctx, trackingID, token)
+		if assert.Nil(t, err) {
+			assert.Equal(t, created.ID, confirmed.ID)
+			assert.True(t, confirmed.Confirmed.Bool)
+		}
+
+		authenticated, err := pipeline.users.Authenticate(ctx, "ada@example.com", password)
+		if assert.Nil(t, err) {
+			assert.Equal(t, created.ID, authenticated.ID)
+			assert.True(t, authenticated.Confirmed.Bool)
+		}
+		_, err = pipeline.users.Authenticate(ctx, "ada@example.com", "wrong horse")
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+	})
+}
+
+func TestSignupWithTakenEmailQueuesNothing(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		harness.LoadFixtures(t, store, "users")
+		pipeline := newMailPipeline(store)
+
+		_, err := pipeline.users.CreateUser(
+			context.Background(),
+			models.User{Email: "jane@example.com", Password: password},
+			models.Profile{Names: null.StringFrom("Jane Again")},
+		)
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, pipeline.broker.Pending(services.ConfirmationMailTopic))
+	})
+}
+
+func TestAuthenticateFixtureUsers(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		ctx := context.Background()
+		fixtures := harness.LoadFixtures(t, store, "users")
+		userService := newUserService(store, nil)
+
+		for email, user := range fixtures.Users {
+			authenticated, err := userService.Authenticate(ctx, email, password)
+			if assert.Nil(t, err, email) {
+				assert.Equal(t, user.ID, authenticated.ID)
+				assert.Equal(t, user.RoleID, authenticated.RoleID)
+			}
+		}
+		_, err := userService.Authenticate(ctx, "jane@example.com", "wrong horse")
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+		_, err = userService.Authenticate(ctx, "nobody@example.com", password)
+		assert.Equal(t, services.ErrInvalidCredentials, err)
+	})
+}
+
+func TestFailedSendsAreRedelivered(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		ctx := context.Background()
+		pipeline := newMailPipeline(store)
+		_, err := pipeline.users.CreateUser(
+			ctx,
+			models.User{Email: "ada@example.com", Password: password},
+			models.Profile{Names: null.StringFrom("Ada Lovelace")},
+		)
+		if !assert.Nil(t, err) {
+			return
+		}
+
+		pipeline.transport.Err = errors.New("mailgun is down")
+		assert.NotNil(t, pipeline.broker.Deliver(services.ConfirmationMailTopic))
+		assert.Equal(t, 1, pipeline.broker.Pending(services.ConfirmationMailTopic))
+
+		pipeline.transport.Err = nil
+		assert.Nil(t, pipeline.broker.Deliver(services.ConfirmationMailTopic))
+		sent := pipeline.transport.Sent()
+		if !assert.Len(t, sent, 1) {
+			return
+		}
+		trackingID, _ := confirmationLink(t, sent[0])
+		mailLog, err := store.MailLogs().FindByID(ctx, trackingID)
+		if assert.Nil(t, err) {
+			assert.Equal(t, models.SupportedStatus["SENT"], mailLog.Status)
+		}
+	})
+}
+
+func TestMailerSkipsMessagesItCannotSend(t *testing.T) {
+	transport := &harness.MailTransport{}
+	mailer := services.NewMailerService(context.Background(), memstore.New(), newLogger(), transport)
+
+	assert.Nil(t, mailer.Consume("1", []byte(`{"type":"newsletter_mail","email_address":"ada@example.com"}`)))
+	assert.NotNil(t, mailer.Consume("2", []byte(`{"type":`)))
+	assert.Empty(t, transport.Sent())
+}
+
+func TestSignupIsAudited(t *testing.T) {
+	store := harness.NewDatabase(t)
+	ctx := context.Background()
+	pipeline := newMailPipeline(store)
+	auditor := services.NewAuditService(ctx, store, newLogger())
+	passwordService := services.NewPasswordService(services.PasswordConfigFromEnv(newLogger()), newLogger())
+	pipeline.users = services.NewUserUserService(
+		ctx, store, newLogger(), passwordService, services.NewMailQueue(store, pipeline.broker), auditor,
+	)
+
+	created, err := pipeline.users.CreateUser(
+		ctx,
+		models.User{Email: "ada@example.com", Password: password},
+		models.Profile{Names: null.StringFrom("Ada Lovelace")},
+	)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, pipeline.broker.Deliver(services.ConfirmationMailTopic))
+	trackingID, token := confirmationLink(t, pipeline.transport.Sent()[0])
+	_, err = pipeline.users.ConfirmUser(ctx, trackingID, token)
+	assert.Nil(t, err)
+
+	_, targetID := services.UserTarget(created.ID)
+	events, err := auditor.Query(ctx, services.AuditFilter{Action: "user.*", TargetID: targetID})
+	if assert.Nil(t, err) && assert.Len(t, events, 2) {
+		assert.Equal(t, "user.confirmed", events[0].Action)
+		assert.Equal(t, "user.created", events[1].Action)
+	}
+	verifier, err := auditor.Verify(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, verifier.BrokenAt)
+}
@@ -0,0 +1,116 @@
+package service_tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+)
+
+func TestDeletedEmailCanBeReused(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		ctx := context.Background()
+		users := newMailPipeline(store).users
+		signup := func() *models.User {
+			user, err := users.CreateUser(ctx, models.User{Email: "ada@example.com", Password: password},
+				models.Profile{Names: null.StringFrom("Ada Lovelace")})
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+			return &user
+		}
+		deleted := signup()
+		assert.Nil(t, store.Users().SoftDelete(ctx, deleted))
+
+		exists, err := store.Users().EmailExists(ctx, "ada@example.com")
+		assert.Nil(t, err)
+		assert.False(t, exists)
+		_, err = users.GetUserByMail(ctx, "ada@example.com")
+		assert.True(t, services.IsKind(err, services.KindNotFound))
+		_, err = store.Users().FindByID(ctx, deleted.ID)
+		assert.Equal(t, sql.ErrNoRows, errors.Cause(err))
+
+		active := signup()
+		found, err := store.Users().FindByEmail(ctx, "ada@example.com", models.WithDeleted)
+		if assert.Nil(t, err) {
+			assert.Equal(t, active.ID, found.ID)
+		}
+		found, err = store.Users().FindByEmail(ctx, "ada@example.com", models.OnlyDeleted)
+		if assert.Nil(t, err) {
+			assert.Equal(t, deleted.ID, found.ID)
+		}
+
+		err = store.Users().Restore(ctx, found)
+		var pqErr *pq.Error
+		if assert.True(t, errors.As(err, &pqErr), "restoring %v", err) {
+			assert.Equal(t, models.UniqueEmailIndex, pqErr.Constraint)
+		}
+
+		assert.Nil(t, store.Users().SoftDelete(ctx, active))
+		found, err = store.Users().FindByID(ctx, deleted.ID, models.WithDeleted)
+		if assert.Nil(t, err) {
+			assert.Nil(t, store.Users().Restore(ctx, found))
+		}
+		restored, err := users.GetUserByID(ctx, deleted.ID)
+		if assert.Nil(t, err) {
+			assert.False(t, restored.Deleted)
+		}
+	})
+}
+
+func TestAuthenticateDeletedAccount(t *testing.T) {
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	ctx := context.Background()
+	deleted := addUser(t, store, "jane@example.com")
+	assert.Nil(t, store.Users().SoftDelete(ctx, deleted))
+
+	// knowing the password is enough to learn the account was deleted, and can be restored
+	user, err := userService.Authenticate(ctx, "jane@example.com", password)
+	if assert.Nil(t, err) {
+		assert.Equal(t, deleted.ID, user.ID)
+		assert.True(t, user.Deleted)
+	}
+	_, err = userService.Authenticate(ctx, "jane@example.com", "wrong horse")
+	assert.Equal(t, services.ErrInvalidCredentials, err)
+
+	active := addUser(t, store, "jane@example.com")
+	user, err = userService.Authenticate(ctx, "jane@example.com", password)
+	if assert.Nil(t, err) {
+		assert.Equal(t, active.ID, user.ID)
+	}
+}
+
+func TestRoleScopes(t *testing.T) {
+	store := memstore.New()
+	ctx := context.Background()
+	role, err := store.Roles().FindBySlug(ctx, services.ClientRoleSlug)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, store.Roles().SoftDelete(ctx, role))
+
+	_, err = store.Roles().FindBySlug(ctx, services.ClientRoleSlug)
+	assert.Equal(t, sql.ErrNoRows, err)
+	_, err = store.Roles().FindByID(ctx, role.ID)
+	assert.Equal(t, sql.ErrNoRows, err)
+	found, err := store.Roles().FindByID(ctx, role.ID, models.OnlyDeleted)
+	if assert.Nil(t, err) {
+		assert.True(t, found.Deleted)
+		assert.Nil(t, store.Roles().Restore(ctx, found))
+	}
+	found, err = store.Roles().FindBySlug(ctx, services.ClientRoleSlug)
+	if assert.Nil(t, err) {
+		assert.Equal(t, role.ID, found.ID)
+	}
+	_, err = store.Roles().FindByID(ctx, role.ID, models.OnlyDeleted)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
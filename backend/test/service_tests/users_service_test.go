@@ -1,10 +1,200 @@
 package service_tests
 
-//import (
-//	"testing"
-//	"github.com/stretchr/testify/assert"
-//	)
-//
-//func TestCreateUser(t *testing.T) {
-//	assert := assert.New(t)
-//}
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/passwords"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/null"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const password = "correct horse battery staple"
+
+func newLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	return logger
+}
+
+// newUserService returns a user service on store, tests that don't send mails pass a nil queue.
+func newUserService(store models.DataLayer, mails *services.MailQueue) *services.UserService {
+	logger := newLogger()
+	passwordService := services.NewPasswordService(services.PasswordConfig{
+		Policy: passwords.Policy{MinLength: 8, MaxLength: 72},
+		Hasher: passwords.Hasher{Algorithm: passwords.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost},
+	}, logger)
+	return services.NewUserUserService(context.Background(), store, logger, passwordService, mails, nil)
+}
+
+// addUser stores a confirmed client user with password.
+func addUser(t *testing.T, store *memstore.Store, email string) *models.User {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	assert.Nil(t, err)
+	ctx := context.Background()
+	profile := models.Profile{Names: null.StringFrom("Jane Doe")}
+	assert.Nil(t, store.Profiles().Insert(ctx, &profile))
+	role, err := store.Roles().FindBySlug(ctx, services.ClientRoleSlug)
+	assert.Nil(t, err)
+	user := models.User{
+		Email:     email,
+		Password:  string(hash),
+		Confirmed: null.BoolFrom(true),
+		ProfileID: profile.ID,
+		RoleID:    role.ID,
+	}
+	assert.Nil(t, store.Users().Insert(ctx, &user))
+	return &user
+}
+
+func TestAuthenticate(t *testing.T) {
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	user := addUser(t, store, "jane@example.com")
+
+	authenticated, err := userService.Authenticate(context.Background(), "jane@example.com", password)
+	assert.Nil(t, err)
+	assert.Equal(t, user.ID, authenticated.ID)
+
+	_, err = userService.Authenticate(context.Background(), "jane@example.com", "wrong horse")
+	assert.Equal(t, services.ErrInvalidCredentials, err)
+	_, err = userService.Authenticate(context.Background(), "nobody@example.com", password)
+	assert.Equal(t, services.ErrInvalidCredentials, err)
+}
+
+func TestVerifyTokenRejectsDeletedAccounts(t *testing.T) {
+	assert.Nil(t, os.Setenv("JWT_SIGNING_KEY", "test-signing-key"))
+	defer os.Unsetenv("JWT_SIGNING_KEY")
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	user := addUser(t, store, "jane@example.com")
+	ctx := context.Background()
+
+	token, err := userService.IssueJWTToken(user)
+	assert.Nil(t, err)
+	verified, err := userService.VerifyToken(ctx, token)
+	assert.Nil(t, err)
+	assert.Equal(t, user.Email, verified.Email)
+
+	user.Deleted = true
+	assert.Nil(t, store.Users().Update(ctx, user, models.UserColumns.Deleted))
+	_, err = userService.VerifyToken(ctx, token)
+	assert.True(t, services.IsKind(err, services.KindUnauthorized))
+}
+
+func TestConfirmUser(t *testing.T) {
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	ctx := context.Background()
+	user := addUser(t, store, "jane@example.com")
+	user.Confirmed = null.BoolFrom(false)
+	assert.Nil(t, store.Users().Update(ctx, user, models.UserColumns.Confirmed))
+
+	payload, _ := json.Marshal(services.UserTransactionMessage{EmailAddress: user.Email, Token: "account-1-token"})
+	mailLog := models.MailerLog{
+		Type:    models.SupportedMessageType["CONFIRMATION"],
+		Payload: string(payload),
+		Status:  models.SupportedStatus["SENT"],
+	}
+	assert.Nil(t, store.MailLogs().Insert(ctx, &mailLog))
+
+	_, err := userService.ConfirmUser(ctx, mailLog.ID, "account-1-forged")
+	assert.True(t, services.IsKind(err, services.KindValidation))
+
+	confirmed, err := userService.ConfirmUser(ctx, mailLog.ID, "account-1-token")
+	assert.Nil(t, err)
+	assert.True(t, confirmed.Confirmed.Bool)
+	stored, _ := store.Users().FindByID(ctx, user.ID)
+	assert.True(t, stored.Confirmed.Bool)
+}
+
+func TestEmailUniqueness(t *testing.T) {
+	store := memstore.New()
+	validationService := services.NewValidationService(context.Background(), store, newLogger())
+	addUser(t, store, "jane@example.com")
+
+	var payload struct {
+		Email string `json:"email" validate:"email,is_unique"`
+	}
+	payload.Email = "jane@example.com"
+	err := validationService.GetValidator().StructCtx(context.Background(), payload)
+	failure := services.AsServiceError(validationService.ValidationFailure(err))
+	if assert.NotNil(t, failure) && assert.Len(t, failure.Fields, 1) {
+		assert.Equal(t, "email", failure.Fields[0].Field)
+	}
+	assert.Equal(t, services.ErrEmailTaken.Message, failure.Message)
+	assert.Equal(t, services.ErrEmailTaken.Fields, failure.Fields)
+
+	payload.Email = "john@example.com"
+	assert.Nil(t, validationService.GetValidator().StructCtx(context.Background(), payload))
+}
+
+func TestKnownUniqueViolationsAreConflicts(t *testing.T) {
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	ctx := context.Background()
+	addUser(t, store, "jane@example.com")
+
+	// a signup that got past the is_unique validator
+	_, err := userService.CreateFederatedUser(ctx, "JANE@example.com", true, models.Profile{})
+	assert.True(t, services.IsKind(err, services.KindConflict))
+	assert.Equal(t, services.ErrEmailTaken.Fields, services.AsServiceError(err).Fields)
+
+	unmapped := services.AsServiceError(&pq.Error{
+		Code:       "23505",
+		Constraint: "api_keys_prefix_key",
+		Detail:     "Key (prefix)=(sk_abc) already exists.",
+	})
+	assert.Equal(t, services.KindInternal, unmapped.Kind, "only the constraints clients can run into are conflicts")
+	assert.Empty(t, unmapped.Fields)
+	assert.NotContains(t, unmapped.Message, "prefix")
+	assert.Equal(t, services.KindInternal, services.AsServiceError(&pq.Error{Code: "23502"}).Kind)
+}
+
+func TestFailedSignupsLeaveNoProfileBehind(t *testing.T) {
+	harness.EachStore(t, func(t *testing.T, store models.DataLayer) {
+		userService := newUserService(store, nil)
+		ctx := context.Background()
+		_, err := userService.CreateFederatedUser(ctx, "jane@example.com", true, models.Profile{})
+		assert.Nil(t, err)
+
+		user, err := userService.CreateUser(ctx, models.User{Email: "Jane@Example.com", Password: password}, models.Profile{})
+		assert.True(t, services.IsKind(err, services.KindConflict))
+		if assert.NotZero(t, user.ProfileID) {
+			_, err = store.Profiles().FindByID(ctx, user.ProfileID)
+			assert.Equal(t, sql.ErrNoRows, errors.Cause(err))
+		}
+	})
+}
+
+func TestSetLocale(t *testing.T) {
+	store := memstore.New()
+	userService := newUserService(store, nil)
+	ctx := context.Background()
+	user := addUser(t, store, "jane@example.com")
+
+	assert.Nil(t, userService.SetLocale(ctx, user, "rw"))
+	stored, _ := store.Users().FindByID(ctx, user.ID)
+	assert.Equal(t, null.StringFrom("rw"), stored.Locale)
+
+	err := userService.SetLocale(ctx, user, "de")
+	assert.True(t, services.IsKind(err, services.KindValidation))
+	stored, _ = store.Users().FindByID(ctx, user.ID)
+	assert.Equal(t, "rw", stored.Locale.String)
+
+	assert.Nil(t, userService.SetLocale(ctx, user, ""))
+	stored, _ = store.Users().FindByID(ctx, user.ID)
+	assert.False(t, stored.Locale.Valid)
+}
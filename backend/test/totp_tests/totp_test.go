@@ -0,0 +1,57 @@
+package totp_tests
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ntwarijoshua/siena/internal/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 seed of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCodeMatchesRFCVectors(t *testing.T) {
+	// RFC 6238 lists 8 digit codes, we use the last 6 of them.
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		code, err := totp.Code(rfcSecret, totp.Step(time.Unix(tc.unix, 0)))
+		assert.Nil(t, err)
+		assert.Equal(t, tc.code, code, "at %d", tc.unix)
+	}
+}
+
+func TestValidateAcceptsAdjacentSteps(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	previous, _ := totp.Code(rfcSecret, totp.Step(now)-1)
+	tooOld, _ := totp.Code(rfcSecret, totp.Step(now)-2)
+
+	step, ok := totp.Validate(rfcSecret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, totp.Step(now)-1, step)
+
+	_, ok = totp.Validate(rfcSecret, tooOld, now)
+	assert.False(t, ok)
+	_, ok = totp.Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestProvisioningURI(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.Nil(t, err)
+	uri := totp.ProvisioningURI("Siena", "jane@siena.rw", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Siena:jane@siena.rw?"))
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=Siena")
+}
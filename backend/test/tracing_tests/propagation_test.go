@@ -0,0 +1,122 @@
+package tracing_tests
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/ntwarijoshua/siena/internal/models"
+	"github.com/ntwarijoshua/siena/internal/models/memstore"
+	"github.com/ntwarijoshua/siena/internal/services"
+	"github.com/ntwarijoshua/siena/internal/tracing"
+	"github.com/ntwarijoshua/siena/test/harness"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/volatiletech/sqlboiler/boil"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContinuesAcrossQueuedMessage(t *testing.T) {
+	assert := assert.New(t)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(exporter)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	ctx, signupSpan := tracing.Tracer().Start(context.Background(), "POST /api/v1/users")
+	message := services.UserTransactionMessage{TrackingId: 1, TraceContext: tracing.Inject(ctx)}
+	signupSpan.End()
+
+	raw, err := json.Marshal(message)
+	assert.Nil(err)
+	var received services.UserTransactionMessage
+	assert.Nil(json.Unmarshal(raw, &received))
+
+	_, consumerSpan := tracing.Tracer().Start(
+		tracing.Extract(context.Background(), received.TraceContext), "account-confirmation-emails process",
+	)
+	consumerSpan.End()
+	assert.Nil(provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	if assert.Len(spans, 2) {
+		assert.Equal(spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+		assert.Equal(spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+	}
+}
+
+// spanTransport remembers the span context each mail was sent in.
+type spanTransport struct {
+	sent []trace.SpanContext
+}
+
+func (t *spanTransport) Send(ctx context.Context, _ services.Mail) error {
+	t.sent = append(t.sent, trace.SpanContextFromContext(ctx))
+	return nil
+}
+
+func TestQueuedMailIsSentInTheTraceOfTheRequest(t *testing.T) {
+	assert := assert.New(t)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(exporter)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	store := memstore.New()
+	broker := harness.NewBroker()
+	transport := &spanTransport{}
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+	mailer := services.NewMailerService(context.Background(), store, logger, transport)
+	broker.Subscribe(services.ConfirmationMailTopic, mailer.Consume)
+
+	ctx, requestSpan := tracing.Tracer().Start(context.Background(), "POST /api/v1/users")
+	err := services.NewMailQueue(store, broker).Queue(ctx, &services.UserTransactionMessage{
+		EmailAddress: "ada@example.com",
+		Type:         models.SupportedMessageType["CONFIRMATION"],
+		Token:        "token",
+	})
+	requestSpan.End()
+	assert.Nil(err)
+
+	// the consumer runs later, outside of the request
+	assert.Nil(broker.Deliver(services.ConfirmationMailTopic))
+	assert.Nil(provider.ForceFlush(context.Background()))
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	request := spans["POST /api/v1/users"]
+	publish := spans[services.ConfirmationMailTopic+" publish"]
+	process := spans[services.ConfirmationMailTopic+" process"]
+	assert.Equal(request.SpanContext.SpanID(), publish.Parent.SpanID())
+	assert.Equal(publish.SpanContext.SpanID(), process.Parent.SpanID(), "the consumer continues the publishing span")
+	assert.Equal(request.SpanContext.TraceID(), process.SpanContext.TraceID())
+	if assert.Len(transport.sent, 1) {
+		assert.Equal(process.SpanContext.SpanID(), transport.sent[0].SpanID(), "the mail is sent within the consumer span")
+	}
+}
+
+func TestStatementsOfATransactionAreTraced(t *testing.T) {
+	assert := assert.New(t)
+	store := harness.NewDatabase(t)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := tracing.NewProvider(exporter)
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+
+	ctx, requestSpan := tracing.Tracer().Start(context.Background(), "DELETE /api/v1/users/me")
+	err := store.Transaction(ctx, func(tx boil.ContextExecutor) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", 42)
+		return err
+	})
+	requestSpan.End()
+	assert.Nil(err)
+	assert.Nil(provider.ForceFlush(context.Background()))
+
+	spans := exporter.GetSpans()
+	if assert.Len(spans, 2) {
+		assert.Equal("sql exec", spans[0].Name)
+		assert.Equal(spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	}
+}